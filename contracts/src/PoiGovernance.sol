@@ -0,0 +1,150 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.8.0;
+
+/**
+ * @title PoiGovernance
+ * @dev Signer-set governance for PoI chains. Predeployed in the genesis block at
+ *      the address configured as `poi.governance`; the consensus engine reads the
+ *      signer set and weights from storage at every epoch checkpoint.
+ * @author IoT Network
+ *
+ * The storage layout of `signers` (slot 0) and `weights` (slot 1) is read by the
+ * consensus engine directly and must not change.
+ */
+contract PoiGovernance {
+
+    // Signer set and performance weights applied at the next epoch checkpoint
+    address[] public signers;
+    mapping(address => uint64) public weights;
+
+    // Struct to store a proposal to add, update or remove a signer
+    struct Proposal {
+        address account;
+        bool authorize;
+        uint64 weight;
+        uint256 votes;
+        bool executed;
+        mapping(address => bool) voted;
+    }
+
+    Proposal[] private proposals;
+
+    event ProposalCreated(uint256 indexed id, address indexed account, bool authorize, uint64 weight, address proposer);
+    event ProposalVoted(uint256 indexed id, address indexed voter);
+    event SignerAdded(address indexed account, uint64 weight);
+    event SignerUpdated(address indexed account, uint64 weight);
+    event SignerRemoved(address indexed account);
+
+    modifier onlySigner() {
+        require(isSigner(msg.sender), "Not a signer");
+        _;
+    }
+
+    /**
+     * @dev Returns whether an account is in the signer set
+     */
+    function isSigner(address account) public view returns (bool) {
+        for (uint256 i = 0; i < signers.length; i++) {
+            if (signers[i] == account) {
+                return true;
+            }
+        }
+        return false;
+    }
+
+    /**
+     * @dev Returns the signer set and the weight of each signer
+     */
+    function getSigners() external view returns (address[] memory, uint64[] memory) {
+        uint64[] memory signerWeights = new uint64[](signers.length);
+        for (uint256 i = 0; i < signers.length; i++) {
+            signerWeights[i] = weights[signers[i]];
+        }
+        return (signers, signerWeights);
+    }
+
+    /**
+     * @dev Proposes adding a signer or updating its weight (authorize) or removing
+     *      it, casting the proposer's vote right away
+     */
+    function propose(address account, bool authorize, uint64 weight) external onlySigner returns (uint256) {
+        require(account != address(0), "Invalid account");
+        require(authorize || isSigner(account), "Not a signer");
+        require(weight <= uint64(type(int64).max), "Weight too large");
+
+        uint256 id = proposals.length;
+        Proposal storage proposal = proposals.push();
+        proposal.account = account;
+        proposal.authorize = authorize;
+        proposal.weight = weight;
+
+        emit ProposalCreated(id, account, authorize, weight, msg.sender);
+        _vote(id);
+        return id;
+    }
+
+    /**
+     * @dev Votes on a pending proposal, executing it once a majority of the
+     *      signers voted for it
+     */
+    function vote(uint256 id) external onlySigner {
+        require(id < proposals.length, "Unknown proposal");
+        _vote(id);
+    }
+
+    /**
+     * @dev Returns the details of a proposal
+     */
+    function getProposal(uint256 id) external view returns (address account, bool authorize, uint64 weight, uint256 votes, bool executed) {
+        require(id < proposals.length, "Unknown proposal");
+        Proposal storage proposal = proposals[id];
+        return (proposal.account, proposal.authorize, proposal.weight, proposal.votes, proposal.executed);
+    }
+
+    /**
+     * @dev Returns the number of proposals ever made
+     */
+    function getProposalCount() external view returns (uint256) {
+        return proposals.length;
+    }
+
+    function _vote(uint256 id) private {
+        Proposal storage proposal = proposals[id];
+        require(!proposal.executed, "Proposal already executed");
+        require(!proposal.voted[msg.sender], "Already voted");
+
+        proposal.voted[msg.sender] = true;
+        proposal.votes++;
+        emit ProposalVoted(id, msg.sender);
+
+        if (proposal.votes > signers.length / 2) {
+            proposal.executed = true;
+            _execute(proposal);
+        }
+    }
+
+    function _execute(Proposal storage proposal) private {
+        if (proposal.authorize) {
+            if (!isSigner(proposal.account)) {
+                signers.push(proposal.account);
+                weights[proposal.account] = proposal.weight;
+                emit SignerAdded(proposal.account, proposal.weight);
+            } else {
+                weights[proposal.account] = proposal.weight;
+                emit SignerUpdated(proposal.account, proposal.weight);
+            }
+            return;
+        }
+        // Never drop the last signer, the chain could not be sealed anymore
+        require(signers.length > 1, "Cannot remove the last signer");
+        for (uint256 i = 0; i < signers.length; i++) {
+            if (signers[i] == proposal.account) {
+                signers[i] = signers[signers.length - 1];
+                signers.pop();
+                break;
+            }
+        }
+        delete weights[proposal.account];
+        emit SignerRemoved(proposal.account);
+    }
+}
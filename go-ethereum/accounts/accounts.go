@@ -40,6 +40,8 @@ const (
 	MimetypeTypedData         = "data/typed"
 	MimetypeClique            = "application/x-clique-header"
 	MimetypePoi               = "application/x-poi-header"
+	MimetypePoiAttestation    = "application/x-poi-attestation"
+	MimetypePoiRotation       = "application/x-poi-rotation"
 	MimetypeTextPlain         = "text/plain"
 )
 
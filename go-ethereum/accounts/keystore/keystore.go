@@ -284,8 +284,7 @@ func (ks *KeyStore) SignTx(a accounts.Account, tx *types.Transaction, chainID *b
 		return nil, ErrLocked
 	}
 	// Depending on the presence of the chain ID, sign with 2718 or homestead
-	signer := types.LatestSignerForChainID(chainID)
-	return types.SignTx(tx, signer, unlockedKey.PrivateKey)
+	return types.SignTx(tx, txSigner(tx, chainID), unlockedKey.PrivateKey)
 }
 
 // SignHashWithPassphrase signs hash if the private key matching the given address
@@ -309,8 +308,18 @@ func (ks *KeyStore) SignTxWithPassphrase(a accounts.Account, passphrase string,
 	}
 	defer zeroKey(key.PrivateKey)
 	// Depending on the presence of the chain ID, sign with or without replay protection.
+	return types.SignTx(tx, txSigner(tx, chainID), key.PrivateKey)
+}
+
+// txSigner returns the signer to sign the transaction with. Sensor transactions
+// only exist on chains with the sensor data fork, so they're signed with the
+// sensor signer instead of extending the signer of every chain.
+func txSigner(tx *types.Transaction, chainID *big.Int) types.Signer {
 	signer := types.LatestSignerForChainID(chainID)
-	return types.SignTx(tx, signer, key.PrivateKey)
+	if tx.Type() == types.SensorTxType && chainID != nil {
+		signer = types.NewSensorSigner(chainID, signer)
+	}
+	return signer
 }
 
 // Unlock unlocks the given account indefinitely.
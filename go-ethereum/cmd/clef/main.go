@@ -969,6 +969,31 @@ func testExternalUI(api *core.SignerAPI) {
 		_, err = api.SignData(ctx, accounts.MimetypeClique, *addr, hexutil.Encode(cliqueRlp))
 		expectApprove("signdata - clique header", err)
 	}
+	{ // Sign data test - poi header
+		api.UI.ShowInfo("Please approve the next request for signing a poi header")
+		time.Sleep(delay)
+		poiHeader := types.Header{
+			ParentHash:  common.HexToHash("0000H45H"),
+			UncleHash:   types.EmptyUncleHash,
+			Coinbase:    common.HexToAddress("0000H45H"),
+			Root:        common.HexToHash("0000H00H"),
+			TxHash:      common.HexToHash("0000H45H"),
+			ReceiptHash: common.HexToHash("0000H45H"),
+			Difficulty:  big.NewInt(2),
+			Number:      big.NewInt(1337),
+			GasLimit:    1338,
+			GasUsed:     1338,
+			Time:        1338,
+			Extra:       make([]byte, 32),
+		}
+		poiRlp, err := rlp.EncodeToBytes(poiHeader)
+		if err != nil {
+			utils.Fatalf("Should not error: %v", err)
+		}
+		addr, _ := common.NewMixedcaseAddressFromString("0x0011223344556677889900112233445566778899")
+		_, err = api.SignData(ctx, accounts.MimetypePoi, *addr, hexutil.Encode(poiRlp))
+		expectApprove("signdata - poi header", err)
+	}
 	{ // Sign data test - typed data
 		api.UI.ShowInfo("Please approve the next request for signing EIP-712 typed data")
 		time.Sleep(delay)
@@ -200,6 +200,10 @@ func makeFullNode(ctx *cli.Context) (*node.Node, ethapi.Backend) {
 	if ctx.IsSet(utils.GraphQLEnabledFlag.Name) {
 		utils.RegisterGraphQLService(stack, backend, filterSystem, &cfg.Node)
 	}
+	// Configure the IoT data index if requested.
+	if ctx.IsSet(utils.IoTIndexEnabledFlag.Name) {
+		utils.RegisterIoTIndexService(stack, backend, ctx)
+	}
 	// Add the Ethereum Stats daemon if requested.
 	if cfg.Ethstats.URL != "" {
 		utils.RegisterEthStatsService(stack, backend, cfg.Ethstats.URL)
@@ -213,8 +217,11 @@ func makeFullNode(ctx *cli.Context) (*node.Node, ethapi.Backend) {
 		utils.RegisterFullSyncTester(stack, eth, common.BytesToHash(hex))
 	}
 	// Start the dev mode if requested, or launch the engine API for
-	// interacting with external consensus client.
-	if ctx.IsSet(utils.DeveloperFlag.Name) {
+	// interacting with external consensus client. PoI dev mode is sealed by
+	// the miner, started along with the node.
+	if ctx.IsSet(utils.DeveloperFlag.Name) && ctx.Bool(utils.DeveloperPoiFlag.Name) {
+		log.Info("Starting PoI developer mode", "period", ctx.Uint64(utils.DeveloperPeriodFlag.Name))
+	} else if ctx.IsSet(utils.DeveloperFlag.Name) {
 		simBeacon, err := catalyst.NewSimulatedBeacon(ctx.Uint64(utils.DeveloperPeriodFlag.Name), eth)
 		if err != nil {
 			utils.Fatalf("failed to register dev mode catalyst service: %v", err)
@@ -30,6 +30,7 @@ import (
 	"github.com/ethereum/go-ethereum/cmd/utils"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/poi"
 	"github.com/ethereum/go-ethereum/console/prompt"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state/snapshot"
@@ -37,6 +38,7 @@ import (
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/internal/flags"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/trie"
 	"github.com/olekukonko/tablewriter"
 	"github.com/urfave/cli/v2"
@@ -51,6 +53,11 @@ var (
 		Name:  "remove.chain",
 		Usage: "If set, selects the state data for removal",
 	}
+	poiSnapshotDepthFlag = &cli.Uint64Flag{
+		Name:  "depth",
+		Usage: "Number of recent blocks to keep all PoI snapshots for",
+		Value: params.FullImmutabilityThreshold,
+	}
 
 	removedbCommand = &cli.Command{
 		Action:    removeDB,
@@ -79,6 +86,7 @@ Remove blockchain and state databases`,
 			dbExportCmd,
 			dbMetadataCmd,
 			dbCheckStateContentCmd,
+			dbPrunePoiSnapshotsCmd,
 		},
 	}
 	dbInspectCmd = &cli.Command{
@@ -203,6 +211,18 @@ WARNING: This is a low-level operation which may cause database corruption!`,
 		}, utils.NetworkFlags, utils.DatabaseFlags),
 		Description: "Shows metadata about the chain status.",
 	}
+	dbPrunePoiSnapshotsCmd = &cli.Command{
+		Action: prunePoiSnapshots,
+		Name:   "prune-poi-snapshots",
+		Usage:  "Delete stale PoI consensus snapshots",
+		Flags: flags.Merge([]cli.Flag{
+			poiSnapshotDepthFlag,
+		}, utils.NetworkFlags, utils.DatabaseFlags),
+		Description: `This command deletes the PoI consensus snapshots that are older than the given
+depth below the current head, e.g. side chain and epoch checkpoint snapshots.
+Snapshots at canonical checkpoint intervals are kept, since they are needed to
+avoid replaying the chain from genesis.`,
+	}
 )
 
 func removeDB(ctx *cli.Context) error {
@@ -419,6 +439,23 @@ func dbCompact(ctx *cli.Context) error {
 	return nil
 }
 
+// prunePoiSnapshots deletes the stale PoI snapshots from the database.
+func prunePoiSnapshots(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, false)
+	defer db.Close()
+
+	start := time.Now()
+	pruned, err := poi.PruneSnapshots(db, ctx.Uint64(poiSnapshotDepthFlag.Name))
+	if err != nil {
+		return err
+	}
+	log.Info("Pruned PoI snapshots", "count", pruned, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
 // dbGet shows the value of a given database key
 func dbGet(ctx *cli.Context) error {
 	if ctx.NArg() != 1 {
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/contracts/iot"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	lightKDFFlag = &cli.BoolFlag{
+		Name:  "lightkdf",
+		Usage: "Use less secure scrypt parameters for the device keys",
+	}
+	funderFlag = &cli.PathFlag{
+		Name:      "funder",
+		Usage:     "Keyfile of the account funding the devices",
+		TakesFile: true,
+	}
+	funderPasswordFlag = &cli.PathFlag{
+		Name:      "funder.password",
+		Usage:     "Password file of the funder keyfile",
+		TakesFile: true,
+	}
+	amountFlag = &cli.Float64Flag{
+		Name:  "amount",
+		Usage: "Balance in ether every device is topped up to",
+		Value: 1,
+	}
+)
+
+var generateCommand = &cli.Command{
+	Name:  "generate",
+	Usage: "Generate device keys into the keystore",
+	Description: `
+Generates keys until the keystore holds --devices keys, all encrypted with the
+password from --password. Keys already in the keystore are kept.`,
+	Flags: []cli.Flag{
+		keystoreFlag,
+		utils.PasswordFileFlag,
+		devicesFlag,
+		lightKDFFlag,
+	},
+	Action: generate,
+}
+
+var deployCommand = &cli.Command{
+	Name:  "deploy",
+	Usage: "Deploy an IoTDataTracker contract owned by the funder",
+	Description: `
+Deploys a new IoTDataTracker contract from the --funder account, waits for it to
+be mined and prints its address for use with --contract.`,
+	Flags: []cli.Flag{
+		rpcFlag,
+		funderFlag,
+		funderPasswordFlag,
+	},
+	Action: deploy,
+}
+
+var fundCommand = &cli.Command{
+	Name:  "fund",
+	Usage: "Top up the balances of the devices",
+	Description: `
+Sends ether from the --funder account to the first --devices keys of the
+keystore whose balance is below --amount, and waits for the last transfer to be
+mined.`,
+	Flags: []cli.Flag{
+		rpcFlag,
+		keystoreFlag,
+		devicesFlag,
+		funderFlag,
+		funderPasswordFlag,
+		amountFlag,
+	},
+	Action: fund,
+}
+
+func generate(ctx *cli.Context) error {
+	var (
+		ks       = makeKeystore(ctx, ctx.Bool(lightKDFFlag.Name))
+		password = devicePassword(ctx)
+		missing  = ctx.Int(devicesFlag.Name) - len(ks.Accounts())
+	)
+	if missing <= 0 {
+		log.Info("Keystore already holds enough keys", "keys", len(ks.Accounts()))
+		return nil
+	}
+	err := parallel(missing, func(int) error {
+		_, err := ks.NewAccount(password)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	log.Info("Generated device keys", "generated", missing, "keys", len(ks.Accounts()))
+	return nil
+}
+
+// funderKey decrypts the key of the funder account.
+func funderKey(ctx *cli.Context) *keystore.Key {
+	if !ctx.IsSet(funderFlag.Name) {
+		utils.Fatalf("No funder keyfile given (--%s)", funderFlag.Name)
+	}
+	keyjson, err := os.ReadFile(ctx.Path(funderFlag.Name))
+	if err != nil {
+		utils.Fatalf("Failed to read funder keyfile: %v", err)
+	}
+	var password string
+	if path := ctx.Path(funderPasswordFlag.Name); path != "" {
+		blob, err := os.ReadFile(path)
+		if err != nil {
+			utils.Fatalf("Failed to read funder password file: %v", err)
+		}
+		password = firstLine(string(blob))
+	}
+	key, err := keystore.DecryptKey(keyjson, password)
+	if err != nil {
+		utils.Fatalf("Failed to decrypt funder key: %v", err)
+	}
+	return key
+}
+
+func deploy(ctx *cli.Context) error {
+	var (
+		key             = funderKey(ctx)
+		client, chainID = dial(ctx)
+	)
+	defer client.Close()
+
+	opts, err := bind.NewKeyedTransactorWithChainID(key.PrivateKey, chainID)
+	if err != nil {
+		return err
+	}
+	tracker, tx, err := iot.Deploy(opts, client)
+	if err != nil {
+		return err
+	}
+	log.Info("Sent contract deployment, waiting for inclusion", "tx", tx.Hash())
+	if _, err := bind.WaitDeployed(context.Background(), client, tx); err != nil {
+		return err
+	}
+	fmt.Println(tracker.Address().Hex())
+	return nil
+}
+
+func fund(ctx *cli.Context) error {
+	key := funderKey(ctx)
+	target, _ := new(big.Float).Mul(big.NewFloat(ctx.Float64(amountFlag.Name)), big.NewFloat(params.Ether)).Int(nil)
+
+	var (
+		ks              = makeKeystore(ctx, false)
+		client, chainID = dial(ctx)
+		background      = context.Background()
+		signer          = types.LatestSignerForChainID(chainID)
+	)
+	defer client.Close()
+
+	accounts := ks.Accounts()
+	if n := ctx.Int(devicesFlag.Name); n < len(accounts) {
+		accounts = accounts[:n]
+	}
+	nonce, err := client.PendingNonceAt(background, key.Address)
+	if err != nil {
+		return err
+	}
+	tip, err := client.SuggestGasTipCap(background)
+	if err != nil {
+		return err
+	}
+	head, err := client.HeaderByNumber(background, nil)
+	if err != nil {
+		return err
+	}
+	if head.BaseFee == nil {
+		return errors.New("chain has no base fee, London is not active")
+	}
+	feeCap := new(big.Int).Add(tip, new(big.Int).Mul(head.BaseFee, big.NewInt(2)))
+
+	var last *types.Transaction
+	for _, account := range accounts {
+		balance, err := client.BalanceAt(background, account.Address, nil)
+		if err != nil {
+			return err
+		}
+		if balance.Cmp(target) >= 0 {
+			continue
+		}
+		tx, err := types.SignNewTx(key.PrivateKey, signer, &types.DynamicFeeTx{
+			ChainID:   chainID,
+			Nonce:     nonce,
+			GasTipCap: tip,
+			GasFeeCap: feeCap,
+			Gas:       params.TxGas,
+			To:        &account.Address,
+			Value:     new(big.Int).Sub(target, balance),
+		})
+		if err != nil {
+			return err
+		}
+		if err := client.SendTransaction(background, tx); err != nil {
+			return fmt.Errorf("failed to fund %x: %v", account.Address, err)
+		}
+		nonce++
+		last = tx
+	}
+	if last == nil {
+		log.Info("All devices are funded", "devices", len(accounts))
+		return nil
+	}
+	log.Info("Sent device funding, waiting for inclusion", "last", last.Hash())
+	if _, err := bind.WaitMined(background, client, last); err != nil {
+		return err
+	}
+	log.Info("Funded devices", "devices", len(accounts))
+	return nil
+}
@@ -0,0 +1,141 @@
+// iotsim simulates IoT devices publishing sensor readings to an IoTDataTracker
+// contract over RPC, as a repeatable load generator for PoI networks.
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/internal/debug"
+	"github.com/ethereum/go-ethereum/internal/flags"
+	"github.com/urfave/cli/v2"
+)
+
+var app = flags.NewApp("IoT device simulator")
+
+// Commonly used command line flags.
+var (
+	rpcFlag = &cli.StringFlag{
+		Name:  "rpc",
+		Usage: "RPC endpoint of the node to send the transactions to",
+		Value: "http://localhost:8545",
+	}
+	keystoreFlag = &cli.PathFlag{
+		Name:      "keystore",
+		Usage:     "Directory holding the device keys",
+		Value:     "iotsim-keystore",
+		TakesFile: true,
+	}
+	devicesFlag = &cli.IntFlag{
+		Name:  "devices",
+		Usage: "Number of simulated devices",
+		Value: 100,
+	}
+)
+
+func init() {
+	app.Flags = append(app.Flags, debug.Flags...)
+	app.Before = func(ctx *cli.Context) error {
+		flags.MigrateGlobalFlags(ctx)
+		return debug.Setup(ctx)
+	}
+	app.After = func(ctx *cli.Context) error {
+		debug.Exit()
+		return nil
+	}
+	app.Commands = []*cli.Command{
+		generateCommand,
+		deployCommand,
+		fundCommand,
+		runCommand,
+	}
+}
+
+func main() {
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// makeKeystore opens the device keystore. Device keys are decrypted once at
+// startup, so the light scrypt parameters are used for new keys if requested.
+func makeKeystore(ctx *cli.Context, light bool) *keystore.KeyStore {
+	scryptN, scryptP := keystore.StandardScryptN, keystore.StandardScryptP
+	if light {
+		scryptN, scryptP = keystore.LightScryptN, keystore.LightScryptP
+	}
+	return keystore.NewKeyStore(ctx.Path(keystoreFlag.Name), scryptN, scryptP)
+}
+
+// devicePassword returns the password of the device keys, the first line of
+// the password file or empty if none is given.
+func devicePassword(ctx *cli.Context) string {
+	if list := utils.MakePasswordList(ctx); len(list) > 0 {
+		return list[0]
+	}
+	return ""
+}
+
+// firstLine returns the first line of a password file.
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(text, "\n")
+	return strings.TrimRight(line, "\r")
+}
+
+// dial connects to the node and retrieves the chain id to sign for.
+func dial(ctx *cli.Context) (*ethclient.Client, *big.Int) {
+	client, err := ethclient.Dial(ctx.String(rpcFlag.Name))
+	if err != nil {
+		utils.Fatalf("Failed to connect to %s: %v", ctx.String(rpcFlag.Name), err)
+	}
+	chainID, err := client.ChainID(context.Background())
+	if err != nil {
+		utils.Fatalf("Failed to retrieve chain id: %v", err)
+	}
+	return client, chainID
+}
+
+// parallel runs fn for every index below n on all CPUs, returning the first
+// error encountered.
+func parallel(n int, fn func(i int) error) error {
+	var (
+		next = make(chan int)
+		errc = make(chan error, 1)
+		wg   sync.WaitGroup
+	)
+	for w := 0; w < runtime.NumCPU(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				if err := fn(i); err != nil {
+					select {
+					case errc <- err:
+					default:
+					}
+				}
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	select {
+	case err := <-errc:
+		return err
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,292 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/contracts/iot"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/time/rate"
+)
+
+var (
+	contractFlag = &cli.StringFlag{
+		Name:  "contract",
+		Usage: "Address of the IoTDataTracker contract",
+	}
+	templatesFlag = &cli.StringFlag{
+		Name:  "templates",
+		Usage: "Comma separated device templates, assigned to the devices round robin",
+		Value: "weatherStation,securitySensor,airQuality,smartMeter",
+	}
+	intervalFlag = &cli.DurationFlag{
+		Name:  "interval",
+		Usage: "Time between two sensor updates of a device",
+		Value: 5 * time.Second,
+	}
+	rateFlag = &cli.Float64Flag{
+		Name:  "rate",
+		Usage: "Maximum number of transactions sent per second by all devices (0 = unlimited)",
+	}
+	durationFlag = &cli.DurationFlag{
+		Name:  "duration",
+		Usage: "Duration of the simulation",
+		Value: time.Minute,
+	}
+	drainFlag = &cli.DurationFlag{
+		Name:  "drain",
+		Usage: "Time to wait for the sent transactions to be included after the simulation",
+		Value: 30 * time.Second,
+	}
+	pollFlag = &cli.DurationFlag{
+		Name:  "poll",
+		Usage: "Interval of polling the chain for included transactions",
+		Value: 250 * time.Millisecond,
+	}
+	gasLimitFlag = &cli.Uint64Flag{
+		Name:  "gaslimit",
+		Usage: "Gas limit of the sensor updates, estimated per transaction if 0",
+	}
+	batchFlag = &cli.IntFlag{
+		Name:  "batch",
+		Usage: "Maximum number of readings per transaction",
+		Value: iot.DefaultBatchSize,
+	}
+)
+
+var runCommand = &cli.Command{
+	Name:  "run",
+	Usage: "Simulate devices publishing sensor readings",
+	Description: `
+Unlocks the first --devices keys of the keystore, registers the devices that
+are not registered yet and lets every device publish its changed sensor values
+each --interval for --duration. Afterwards it waits up to --drain for the sent
+transactions to be included and reports inclusion and latency statistics.`,
+	Flags: []cli.Flag{
+		rpcFlag,
+		keystoreFlag,
+		utils.PasswordFileFlag,
+		devicesFlag,
+		contractFlag,
+		templatesFlag,
+		intervalFlag,
+		rateFlag,
+		durationFlag,
+		drainFlag,
+		pollFlag,
+		gasLimitFlag,
+		batchFlag,
+	},
+	Action: run,
+}
+
+// simDevice is a simulated device along with the client sending its readings.
+type simDevice struct {
+	*device
+	id     string
+	client *iot.Client
+}
+
+func run(ctx *cli.Context) error {
+	if !common.IsHexAddress(ctx.String(contractFlag.Name)) {
+		utils.Fatalf("Invalid or missing contract address (--%s)", contractFlag.Name)
+	}
+	var kinds []*template
+	for _, name := range strings.Split(ctx.String(templatesFlag.Name), ",") {
+		t := findTemplate(strings.TrimSpace(name))
+		if t == nil {
+			utils.Fatalf("Unknown device template %q", name)
+		}
+		kinds = append(kinds, t)
+	}
+	var (
+		address         = common.HexToAddress(ctx.String(contractFlag.Name))
+		ks              = makeKeystore(ctx, false)
+		password        = devicePassword(ctx)
+		client, chainID = dial(ctx)
+	)
+	defer client.Close()
+
+	accounts := ks.Accounts()
+	if n := ctx.Int(devicesFlag.Name); n < len(accounts) {
+		accounts = accounts[:n]
+	}
+	if len(accounts) == 0 {
+		utils.Fatalf("No device keys in %s, run generate first", ctx.Path(keystoreFlag.Name))
+	}
+	log.Info("Unlocking device keys", "devices", len(accounts))
+	devices := make([]*simDevice, len(accounts))
+	err := parallel(len(accounts), func(i int) error {
+		if err := ks.Unlock(accounts[i], password); err != nil {
+			return fmt.Errorf("failed to unlock %x: %v", accounts[i].Address, err)
+		}
+		opts, err := bind.NewKeyStoreTransactorWithChainID(ks, accounts[i], chainID)
+		if err != nil {
+			return err
+		}
+		opts.GasLimit = ctx.Uint64(gasLimitFlag.Name)
+
+		c, err := iot.NewClient(address, client, opts)
+		if err != nil {
+			return err
+		}
+		c.BatchSize = ctx.Int(batchFlag.Name)
+
+		kind := kinds[i%len(kinds)]
+		devices[i] = &simDevice{
+			device: newDevice(kind, int64(i)),
+			id:     fmt.Sprintf("%s-%d", kind.Name, i),
+			client: c,
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := register(devices); err != nil {
+		return err
+	}
+	tracker, err := newTracker(client)
+	if err != nil {
+		return err
+	}
+	limit := rate.Inf
+	if r := ctx.Float64(rateFlag.Name); r > 0 {
+		limit = rate.Limit(r)
+	}
+	var (
+		limiter  = rate.NewLimiter(limit, 1)
+		interval = ctx.Duration(intervalFlag.Name)
+		poll     = ctx.Duration(pollFlag.Name)
+	)
+	trackCtx, stopTracking := context.WithCancel(context.Background())
+	defer stopTracking()
+	go tracker.loop(trackCtx, poll)
+
+	log.Info("Starting simulation", "devices", len(devices), "interval", interval, "duration", ctx.Duration(durationFlag.Name))
+	simCtx, stopSimulation := context.WithTimeout(context.Background(), ctx.Duration(durationFlag.Name))
+	defer stopSimulation()
+
+	var wg sync.WaitGroup
+	for _, d := range devices {
+		wg.Add(1)
+		go func(d *simDevice) {
+			defer wg.Done()
+			d.simulate(simCtx, interval, limiter, tracker)
+		}(d)
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	progress := time.NewTicker(10 * time.Second)
+	defer progress.Stop()
+
+	for running := true; running; {
+		select {
+		case <-progress.C:
+			r := tracker.report()
+			log.Info("Simulation progress", "sent", r.Sent, "failed", r.Failed, "included", r.Included, "pending", r.Pending, "p50", r.P50)
+		case <-done:
+			running = false
+		}
+	}
+	// Wait for the outstanding transactions to be included
+	drain := time.After(ctx.Duration(drainFlag.Name))
+	for tracker.outstanding() > 0 {
+		select {
+		case <-time.After(poll):
+		case <-drain:
+			log.Warn("Stopped waiting for inclusion", "pending", tracker.outstanding())
+			tracker.report().print(os.Stdout)
+			return nil
+		}
+	}
+	tracker.report().print(os.Stdout)
+	return nil
+}
+
+// register registers the devices that are not registered yet and waits for the
+// registrations to be mined, as gas estimation of the readings depends on it.
+func register(devices []*simDevice) error {
+	background := context.Background()
+
+	txs := make([]*types.Transaction, len(devices))
+	err := parallel(len(devices), func(i int) error {
+		registered, err := devices[i].client.Registered(background)
+		if err != nil || registered {
+			return err
+		}
+		if txs[i], err = devices[i].client.Register(background, devices[i].id, "iotsim"); err != nil {
+			return fmt.Errorf("failed to register %s: %v", devices[i].id, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	var registered atomic.Int64
+	err = parallel(len(devices), func(i int) error {
+		if txs[i] == nil {
+			return nil
+		}
+		if _, err := devices[i].client.Wait(background, txs[i]); err != nil {
+			return fmt.Errorf("registration of %s failed: %v", devices[i].id, err)
+		}
+		registered.Add(1)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	log.Info("Registered devices", "new", registered.Load(), "devices", len(devices))
+	return nil
+}
+
+// simulate publishes the changed sensor values of the device every interval
+// until the context is cancelled.
+func (d *simDevice) simulate(ctx context.Context, interval time.Duration, limiter *rate.Limiter, tracker *tracker) {
+	// Spread the devices over the interval instead of updating in lockstep
+	select {
+	case <-time.After(time.Duration(rand.Int63n(int64(interval)))):
+	case <-ctx.Done():
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if readings := d.update(); len(readings) > 0 {
+			if err := limiter.Wait(ctx); err != nil {
+				return
+			}
+			sent := time.Now()
+			txs, err := d.client.Publish(ctx, readings)
+			tracker.track(txs, sent)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				tracker.fail()
+				log.Debug("Failed to publish readings", "device", d.id, "err", err)
+			}
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
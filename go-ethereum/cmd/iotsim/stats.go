@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"golang.org/x/exp/slices"
+)
+
+// tracker follows the chain to measure how long the sent transactions take to
+// be included. Inclusion is observed by polling, so latencies are rounded up to
+// the poll interval.
+type tracker struct {
+	client *ethclient.Client
+	start  time.Time
+
+	pending   map[common.Hash]time.Time // Send time of transactions not yet included
+	latencies []time.Duration           // Inclusion latency of every included transaction
+	sent      int                       // Number of transactions sent
+	failed    int                       // Number of transactions that failed to send
+	reverted  int                       // Number of included transactions that reverted
+	blocks    int                       // Number of blocks including simulated transactions
+	head      uint64                    // Last block processed
+	lock      sync.Mutex
+}
+
+// newTracker creates a tracker starting after the current head of the chain.
+func newTracker(client *ethclient.Client) (*tracker, error) {
+	head, err := client.BlockNumber(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return &tracker{
+		client:  client,
+		start:   time.Now(),
+		pending: make(map[common.Hash]time.Time),
+		head:    head,
+	}, nil
+}
+
+// track records the transactions sent at the given time.
+func (t *tracker) track(txs []*types.Transaction, sent time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for _, tx := range txs {
+		t.pending[tx.Hash()] = sent
+	}
+	t.sent += len(txs)
+}
+
+// fail records a transaction that failed to send.
+func (t *tracker) fail() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.failed++
+}
+
+// outstanding returns the number of sent transactions not yet included.
+func (t *tracker) outstanding() int {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return len(t.pending)
+}
+
+// loop polls the chain for new blocks until the context is cancelled.
+func (t *tracker) loop(ctx context.Context, interval time.Duration) {
+	timer := time.NewTicker(interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			if err := t.poll(ctx); err != nil && ctx.Err() == nil {
+				log.Warn("Failed to follow the chain", "err", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// poll processes the blocks mined since the last poll.
+func (t *tracker) poll(ctx context.Context) error {
+	head, err := t.client.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	for t.head < head {
+		block, err := t.client.BlockByNumber(ctx, new(big.Int).SetUint64(t.head+1))
+		if err != nil {
+			return err
+		}
+		if err := t.process(ctx, block); err != nil {
+			return err
+		}
+		t.head++
+	}
+	return nil
+}
+
+// process marks the simulated transactions in the block as included.
+func (t *tracker) process(ctx context.Context, block *types.Block) error {
+	now := time.Now()
+
+	t.lock.Lock()
+	var included []int
+	for i, tx := range block.Transactions() {
+		if sent, ok := t.pending[tx.Hash()]; ok {
+			delete(t.pending, tx.Hash())
+			t.latencies = append(t.latencies, now.Sub(sent))
+			included = append(included, i)
+		}
+	}
+	if len(included) > 0 {
+		t.blocks++
+	}
+	t.lock.Unlock()
+
+	if len(included) == 0 {
+		return nil
+	}
+	receipts, err := t.client.BlockReceipts(ctx, rpc.BlockNumberOrHashWithHash(block.Hash(), false))
+	if err != nil {
+		return err
+	}
+	reverted := 0
+	for _, i := range included {
+		if i < len(receipts) && receipts[i].Status != types.ReceiptStatusSuccessful {
+			reverted++
+		}
+	}
+	t.lock.Lock()
+	t.reverted += reverted
+	t.lock.Unlock()
+	return nil
+}
+
+// report is a snapshot of the simulation statistics.
+type report struct {
+	Elapsed  time.Duration
+	Sent     int
+	Failed   int
+	Included int
+	Reverted int
+	Pending  int
+	Blocks   int
+
+	Min, Mean, Max time.Duration
+	P50, P95, P99  time.Duration
+}
+
+// report computes the statistics gathered so far.
+func (t *tracker) report() *report {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	r := &report{
+		Elapsed:  time.Since(t.start),
+		Sent:     t.sent,
+		Failed:   t.failed,
+		Included: len(t.latencies),
+		Reverted: t.reverted,
+		Pending:  len(t.pending),
+		Blocks:   t.blocks,
+	}
+	if len(t.latencies) == 0 {
+		return r
+	}
+	latencies := slices.Clone(t.latencies)
+	slices.Sort(latencies)
+
+	var total time.Duration
+	for _, latency := range latencies {
+		total += latency
+	}
+	r.Min, r.Max = latencies[0], latencies[len(latencies)-1]
+	r.Mean = total / time.Duration(len(latencies))
+	r.P50 = percentile(latencies, 50)
+	r.P95 = percentile(latencies, 95)
+	r.P99 = percentile(latencies, 99)
+	return r
+}
+
+// percentile returns the nearest-rank percentile of the sorted latencies.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// print writes the report in human readable form.
+func (r *report) print(w io.Writer) {
+	seconds := r.Elapsed.Seconds()
+	fmt.Fprintf(w, "Elapsed:       %v\n", r.Elapsed.Round(time.Millisecond))
+	fmt.Fprintf(w, "Sent:          %d (%.2f tx/s)\n", r.Sent, float64(r.Sent)/seconds)
+	fmt.Fprintf(w, "Send failures: %d\n", r.Failed)
+	fmt.Fprintf(w, "Included:      %d (%.2f tx/s) in %d blocks\n", r.Included, float64(r.Included)/seconds, r.Blocks)
+	fmt.Fprintf(w, "Reverted:      %d\n", r.Reverted)
+	fmt.Fprintf(w, "Not included:  %d\n", r.Pending)
+	if r.Included > 0 {
+		fmt.Fprintf(w, "Latency:       min %v, mean %v, max %v\n", r.Min.Round(time.Millisecond), r.Mean.Round(time.Millisecond), r.Max.Round(time.Millisecond))
+		fmt.Fprintf(w, "Percentiles:   p50 %v, p95 %v, p99 %v\n", r.P50.Round(time.Millisecond), r.P95.Round(time.Millisecond), r.P99.Round(time.Millisecond))
+	}
+}
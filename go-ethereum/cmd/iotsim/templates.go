@@ -0,0 +1,149 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/contracts/iot"
+)
+
+// pattern describes how the value of a sensor evolves. Sensors either drift
+// within [Min, Max] by at most Change per update, or switch between discrete
+// Values with the given Probability per update.
+type pattern struct {
+	Sensor string
+
+	Min, Max, Change float64
+	Monotonic        bool // Value only grows, ignoring Max (e.g. energy meters)
+
+	Values      []string
+	Probability float64
+}
+
+// template is a kind of device with a fixed set of sensors.
+type template struct {
+	Name    string
+	Sensors []pattern
+}
+
+// templates are the device kinds that can be simulated, matching the ones of
+// contracts/iot-simulator.js.
+var templates = []*template{
+	{
+		Name: "weatherStation",
+		Sensors: []pattern{
+			{Sensor: "temperature", Min: 15, Max: 35, Change: 0.5},
+			{Sensor: "humidity", Min: 30, Max: 80, Change: 2},
+			{Sensor: "pressure", Min: 980, Max: 1020, Change: 1},
+			{Sensor: "light", Min: 0, Max: 1000, Change: 50},
+		},
+	},
+	{
+		Name: "securitySensor",
+		Sensors: []pattern{
+			{Sensor: "motion", Values: []string{"detected", "none"}, Probability: 0.1},
+			{Sensor: "door", Values: []string{"open", "closed"}, Probability: 0.05},
+			{Sensor: "window", Values: []string{"open", "closed"}, Probability: 0.03},
+			{Sensor: "battery", Min: 0, Max: 100, Change: 0.1},
+		},
+	},
+	{
+		Name: "airQuality",
+		Sensors: []pattern{
+			{Sensor: "co2", Min: 400, Max: 2000, Change: 10},
+			{Sensor: "pm2_5", Min: 0, Max: 100, Change: 2},
+			{Sensor: "pm10", Min: 0, Max: 150, Change: 3},
+			{Sensor: "voc", Min: 0, Max: 500, Change: 5},
+		},
+	},
+	{
+		Name: "smartMeter",
+		Sensors: []pattern{
+			{Sensor: "power", Min: 100, Max: 5000, Change: 50},
+			{Sensor: "voltage", Min: 220, Max: 240, Change: 1},
+			{Sensor: "current", Min: 0, Max: 25, Change: 1},
+			{Sensor: "energy", Min: 0, Max: 999999, Change: 1, Monotonic: true},
+		},
+	},
+}
+
+// findTemplate returns the template with the given name, or nil if unknown.
+func findTemplate(name string) *template {
+	for _, t := range templates {
+		if t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// initial returns the value a sensor starts with.
+func (p *pattern) initial() string {
+	if len(p.Values) > 0 {
+		return p.Values[0]
+	}
+	return formatValue((p.Min + p.Max) / 2)
+}
+
+// next returns the value following the current one.
+func (p *pattern) next(rng *rand.Rand, current string) string {
+	if len(p.Values) > 0 {
+		if rng.Float64() >= p.Probability {
+			return current
+		}
+		others := make([]string, 0, len(p.Values)-1)
+		for _, value := range p.Values {
+			if value != current {
+				others = append(others, value)
+			}
+		}
+		return others[rng.Intn(len(others))]
+	}
+	value, _ := strconv.ParseFloat(current, 64)
+	change := (rng.Float64() - 0.5) * 2 * p.Change
+	if p.Monotonic {
+		return formatValue(value + math.Abs(change))
+	}
+	return formatValue(math.Max(p.Min, math.Min(p.Max, value+change)))
+}
+
+// formatValue formats a continuous sensor value the way devices report it.
+func formatValue(value float64) string {
+	return strconv.FormatFloat(value, 'f', 2, 64)
+}
+
+// device is the simulated state of a single device.
+type device struct {
+	template *template
+	values   []string // Current value of every sensor of the template
+	rng      *rand.Rand
+}
+
+// newDevice creates a device of the given template with its sensors at their
+// initial values.
+func newDevice(t *template, seed int64) *device {
+	d := &device{
+		template: t,
+		values:   make([]string, len(t.Sensors)),
+		rng:      rand.New(rand.NewSource(seed)),
+	}
+	for i := range t.Sensors {
+		d.values[i] = t.Sensors[i].initial()
+	}
+	return d
+}
+
+// update advances every sensor of the device and returns the readings of the
+// ones that changed, as unchanged values are not worth a transaction.
+func (d *device) update() []iot.Reading {
+	var readings []iot.Reading
+	for i := range d.template.Sensors {
+		p := &d.template.Sensors[i]
+		if value := p.next(d.rng, d.values[i]); value != d.values[i] {
+			d.values[i] = value
+			readings = append(readings, iot.Reading{Sensor: p.Sensor, Value: value})
+		}
+	}
+	return readings
+}
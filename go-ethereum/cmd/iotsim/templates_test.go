@@ -0,0 +1,66 @@
+package main
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+// Tests that simulated sensor values stay within the bounds of their pattern
+// and that only changed values are reported.
+func TestDeviceUpdate(t *testing.T) {
+	for i, kind := range templates {
+		d := newDevice(kind, int64(i))
+		for round := 0; round < 1000; round++ {
+			prev := append([]string(nil), d.values...)
+			readings := d.update()
+
+			changed := 0
+			for j, p := range kind.Sensors {
+				if d.values[j] != prev[j] {
+					changed++
+				}
+				if len(p.Values) > 0 {
+					continue
+				}
+				value, err := strconv.ParseFloat(d.values[j], 64)
+				if err != nil {
+					t.Fatalf("%s/%s: invalid value %q: %v", kind.Name, p.Sensor, d.values[j], err)
+				}
+				old, _ := strconv.ParseFloat(prev[j], 64)
+				switch {
+				case p.Monotonic && value < old:
+					t.Fatalf("%s/%s: value decreased: %v -> %v", kind.Name, p.Sensor, old, value)
+				case !p.Monotonic && (value < p.Min || value > p.Max):
+					t.Fatalf("%s/%s: value %v out of range [%v, %v]", kind.Name, p.Sensor, value, p.Min, p.Max)
+				}
+			}
+			if len(readings) != changed {
+				t.Fatalf("%s: reading count mismatch: have %d, want %d", kind.Name, len(readings), changed)
+			}
+		}
+	}
+}
+
+// Tests the nearest-rank percentiles of the latency report.
+func TestPercentile(t *testing.T) {
+	latencies := make([]time.Duration, 200)
+	for i := range latencies {
+		latencies[i] = time.Duration(i+1) * time.Millisecond
+	}
+	tests := []struct {
+		p    int
+		want time.Duration
+	}{
+		{0, time.Millisecond},
+		{50, 100 * time.Millisecond},
+		{95, 190 * time.Millisecond},
+		{99, 198 * time.Millisecond},
+		{100, 200 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		if have := percentile(latencies, tt.p); have != tt.want {
+			t.Errorf("p%d mismatch: have %v, want %v", tt.p, have, tt.want)
+		}
+	}
+}
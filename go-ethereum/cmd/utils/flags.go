@@ -50,6 +50,7 @@ import (
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
 	"github.com/ethereum/go-ethereum/eth/filters"
 	"github.com/ethereum/go-ethereum/eth/gasprice"
+	"github.com/ethereum/go-ethereum/eth/iotindex"
 	"github.com/ethereum/go-ethereum/eth/tracers"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/ethdb/remotedb"
@@ -166,6 +167,11 @@ var (
 		Usage:    "Block period to use in developer mode (0 = mine only if transaction pending)",
 		Category: flags.DevCategory,
 	}
+	DeveloperPoiFlag = &cli.BoolFlag{
+		Name:     "dev.poi",
+		Usage:    "Seal developer mode blocks with the PoI engine and the developer account as signer instead of the simulated beacon",
+		Category: flags.DevCategory,
+	}
 	DeveloperGasLimitFlag = &cli.Uint64Flag{
 		Name:     "dev.gaslimit",
 		Usage:    "Initial block gas limit",
@@ -630,6 +636,17 @@ var (
 		Value:    strings.Join(node.DefaultConfig.GraphQLVirtualHosts, ","),
 		Category: flags.APICategory,
 	}
+	IoTIndexEnabledFlag = &cli.BoolFlag{
+		Name:     "iot.index",
+		Usage:    "Enable the IoT data index and its iot RPC API",
+		Category: flags.APICategory,
+	}
+	IoTIndexContractsFlag = &cli.StringFlag{
+		Name:     "iot.contracts",
+		Usage:    "Comma separated list of IoTDataTracker contract addresses to index",
+		Value:    "",
+		Category: flags.APICategory,
+	}
 	WSEnabledFlag = &cli.BoolFlag{
 		Name:     "ws",
 		Usage:    "Enable the WS-RPC server",
@@ -1803,25 +1820,37 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 		log.Info("Using developer account", "address", developer.Address)
 
 		// Create a new developer genesis block or reuse existing one
-		cfg.Genesis = core.DeveloperGenesisBlock(ctx.Uint64(DeveloperGasLimitFlag.Name), &developer.Address)
+		poi := ctx.Bool(DeveloperPoiFlag.Name)
+		if poi {
+			cfg.Genesis = core.DeveloperPoiGenesisBlock(ctx.Uint64(DeveloperPeriodFlag.Name), ctx.Uint64(DeveloperGasLimitFlag.Name), developer.Address)
+		} else {
+			cfg.Genesis = core.DeveloperGenesisBlock(ctx.Uint64(DeveloperGasLimitFlag.Name), &developer.Address)
+		}
 		if ctx.IsSet(DataDirFlag.Name) {
 			chaindb := tryMakeReadOnlyDatabase(ctx, stack)
 			if rawdb.ReadCanonicalHash(chaindb, 0) != (common.Hash{}) {
 				cfg.Genesis = nil // fallback to db content
 
-				//validate genesis has PoS enabled in block 0
 				genesis, err := core.ReadGenesis(chaindb)
 				if err != nil {
 					Fatalf("Could not read genesis from database: %v", err)
 				}
-				if !genesis.Config.TerminalTotalDifficultyPassed {
-					Fatalf("Bad developer-mode genesis configuration: terminalTotalDifficultyPassed must be true in developer mode")
-				}
-				if genesis.Config.TerminalTotalDifficulty == nil {
-					Fatalf("Bad developer-mode genesis configuration: terminalTotalDifficulty must be specified.")
-				}
-				if genesis.Difficulty.Cmp(genesis.Config.TerminalTotalDifficulty) != 1 {
-					Fatalf("Bad developer-mode genesis configuration: genesis block difficulty must be > terminalTotalDifficulty")
+				if poi {
+					// validate genesis has PoI configured
+					if genesis.Config.Poi == nil {
+						Fatalf("Bad developer-mode genesis configuration: poi must be configured in PoI developer mode")
+					}
+				} else {
+					// validate genesis has PoS enabled in block 0
+					if !genesis.Config.TerminalTotalDifficultyPassed {
+						Fatalf("Bad developer-mode genesis configuration: terminalTotalDifficultyPassed must be true in developer mode")
+					}
+					if genesis.Config.TerminalTotalDifficulty == nil {
+						Fatalf("Bad developer-mode genesis configuration: terminalTotalDifficulty must be specified.")
+					}
+					if genesis.Difficulty.Cmp(genesis.Config.TerminalTotalDifficulty) != 1 {
+						Fatalf("Bad developer-mode genesis configuration: genesis block difficulty must be > terminalTotalDifficulty")
+					}
 				}
 			}
 			chaindb.Close()
@@ -1895,6 +1924,24 @@ func RegisterFilterAPI(stack *node.Node, backend ethapi.Backend, ethcfg *ethconf
 	return filterSystem
 }
 
+// RegisterIoTIndexService adds the IoT data index and its RPC API to the node.
+func RegisterIoTIndexService(stack *node.Node, backend ethapi.Backend, ctx *cli.Context) {
+	var contracts []common.Address
+	for _, address := range SplitAndTrim(ctx.String(IoTIndexContractsFlag.Name)) {
+		if !common.IsHexAddress(address) {
+			Fatalf("Invalid IoT contract address %q", address)
+		}
+		contracts = append(contracts, common.HexToAddress(address))
+	}
+	if len(contracts) == 0 && backend.ChainConfig().SensorDataBlock == nil {
+		log.Warn("IoT data index enabled without contracts to index")
+	}
+	if _, err := iotindex.New(stack, backend, iotindex.Config{Contracts: contracts}); err != nil {
+		Fatalf("Failed to register the IoT data index: %v", err)
+	}
+	log.Info("Registered IoT data index", "contracts", len(contracts))
+}
+
 // RegisterFullSyncTester adds the full-sync tester service into node.
 func RegisterFullSyncTester(stack *node.Node, eth *eth.Ethereum, target common.Hash) {
 	catalyst.RegisterFullSyncTester(stack, eth, target)
@@ -432,6 +432,51 @@ func (beacon *Beacon) Close() error {
 	return beacon.ethone.Close()
 }
 
+// SealsOnDemand implements consensus.OnDemandSealer, delegating to the eth1
+// engine as sealing only happens pre-merge.
+func (beacon *Beacon) SealsOnDemand() bool {
+	if s, ok := beacon.ethone.(consensus.OnDemandSealer); ok {
+		return s.SealsOnDemand()
+	}
+	return false
+}
+
+// RestrictsSenders implements consensus.TxValidator, delegating pre-merge blocks
+// to the eth1 engine if it restricts transaction senders.
+func (beacon *Beacon) RestrictsSenders(header *types.Header) bool {
+	if beacon.IsPoSHeader(header) {
+		return false
+	}
+	if v, ok := beacon.ethone.(consensus.TxValidator); ok {
+		return v.RestrictsSenders(header)
+	}
+	return false
+}
+
+// ValidateTxSender implements consensus.TxValidator, delegating pre-merge blocks
+// to the eth1 engine if it restricts transaction senders.
+func (beacon *Beacon) ValidateTxSender(chain consensus.ChainHeaderReader, header *types.Header, sender common.Address) error {
+	if beacon.IsPoSHeader(header) {
+		return nil
+	}
+	if v, ok := beacon.ethone.(consensus.TxValidator); ok {
+		return v.ValidateTxSender(chain, header, sender)
+	}
+	return nil
+}
+
+// VerifyHeaderState implements consensus.StateVerifier, delegating pre-merge
+// headers to the eth1 engine if it verifies fields against the parent state.
+func (beacon *Beacon) VerifyHeaderState(chain consensus.ChainHeaderReader, header *types.Header) error {
+	if beacon.IsPoSHeader(header) {
+		return nil
+	}
+	if v, ok := beacon.ethone.(consensus.StateVerifier); ok {
+		return v.VerifyHeaderState(chain, header)
+	}
+	return nil
+}
+
 // IsPoSHeader reports the header belongs to the PoS-stage with some special fields.
 // This function is not suitable for a part of APIs like Prepare or CalcDifficulty
 // because the header difficulty is not set yet.
@@ -381,6 +381,13 @@ func (c *Clique) verifyCascadingFields(chain consensus.ChainHeaderReader, header
 	return c.verifySeal(snap, header, parents)
 }
 
+// Snapshot retrieves the authorization snapshot at a given point in time, e.g.
+// for another engine taking over sealing from Clique. The caller may optionally
+// pass in a batch of parents (ascending order) not yet in the database.
+func (c *Clique) Snapshot(chain consensus.ChainHeaderReader, number uint64, hash common.Hash, parents []*types.Header) (*Snapshot, error) {
+	return c.snapshot(chain, number, hash, parents)
+}
+
 // snapshot retrieves the authorization snapshot at a given point in time.
 func (c *Clique) snapshot(chain consensus.ChainHeaderReader, number uint64, hash common.Hash, parents []*types.Header) (*Snapshot, error) {
 	// Search for a snapshot in memory or on disk for checkpoints
@@ -702,6 +709,12 @@ func calcDifficulty(snap *Snapshot, signer common.Address) *big.Int {
 	return new(big.Int).Set(diffNoTurn)
 }
 
+// SealsOnDemand implements consensus.OnDemandSealer, returning whether empty
+// blocks are never sealed because the chain is configured with a zero period.
+func (c *Clique) SealsOnDemand() bool {
+	return c.config.Period == 0
+}
+
 // SealHash returns the hash of a block prior to it being sealed.
 func (c *Clique) SealHash(header *types.Header) common.Hash {
 	return SealHash(header)
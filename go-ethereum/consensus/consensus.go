@@ -127,3 +127,32 @@ type PoW interface {
 	// Hashrate returns the current mining hashrate of a PoW consensus engine.
 	Hashrate() float64
 }
+
+// TxValidator is an optional interface of consensus engines that restrict which
+// accounts may send transactions.
+type TxValidator interface {
+	// RestrictsSenders returns whether the senders of the transactions in the
+	// block of the given header need to be validated at all.
+	RestrictsSenders(header *types.Header) bool
+
+	// ValidateTxSender returns an error if the given sender is not allowed to
+	// send transactions in the block of the given header.
+	ValidateTxSender(chain ChainHeaderReader, header *types.Header, sender common.Address) error
+}
+
+// StateVerifier is an optional interface of consensus engines with header fields
+// that can only be verified against the state of the parent block.
+type StateVerifier interface {
+	// VerifyHeaderState verifies the parts of a header depending on the state of
+	// its parent. It's called before processing the block, once the parent state
+	// is available.
+	VerifyHeaderState(chain ChainHeaderReader, header *types.Header) error
+}
+
+// OnDemandSealer is an optional interface of consensus engines that only seal
+// blocks containing transactions, e.g. zero period proof-of-authority chains.
+type OnDemandSealer interface {
+	// SealsOnDemand returns whether empty blocks are never sealed, so sealing
+	// work only needs to be committed when new transactions arrive.
+	SealsOnDemand() bool
+}
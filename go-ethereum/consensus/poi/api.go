@@ -22,15 +22,9 @@ type API struct {
 // GetSnapshot retrieves the state snapshot at a given block.
 func (api *API) GetSnapshot(number *rpc.BlockNumber) (*Snapshot, error) {
 	// Retrieve the requested block number (or current if none requested)
-	var header *types.Header
-	if number == nil || *number == rpc.LatestBlockNumber {
-		header = api.chain.CurrentHeader()
-	} else {
-		header = api.chain.GetHeaderByNumber(uint64(number.Int64()))
-	}
-	// Ensure we have an actually valid block and return its snapshot
-	if header == nil {
-		return nil, errUnknownBlock
+	header, err := api.headerAt(number)
+	if err != nil {
+		return nil, err
 	}
 	return api.poi.snapshot(api.chain, header.Number.Uint64(), header.Hash(), nil)
 }
@@ -47,15 +41,9 @@ func (api *API) GetSnapshotAtHash(hash common.Hash) (*Snapshot, error) {
 // GetSigners retrieves the list of authorized signers at the specified block.
 func (api *API) GetSigners(number *rpc.BlockNumber) ([]common.Address, error) {
 	// Retrieve the requested block number (or current if none requested)
-	var header *types.Header
-	if number == nil || *number == rpc.LatestBlockNumber {
-		header = api.chain.CurrentHeader()
-	} else {
-		header = api.chain.GetHeaderByNumber(uint64(number.Int64()))
-	}
-	// Ensure we have an actually valid block and return the signers from its snapshot
-	if header == nil {
-		return nil, errUnknownBlock
+	header, err := api.headerAt(number)
+	if err != nil {
+		return nil, err
 	}
 	snap, err := api.poi.snapshot(api.chain, header.Number.Uint64(), header.Hash(), nil)
 	if err != nil {
@@ -200,7 +188,7 @@ func (api *API) GetSigner(rlpOrBlockNr *blockNumberOrHashOrRLP) (common.Address,
 		} else if hash, ok := blockNrOrHash.Hash(); ok {
 			header = api.chain.GetHeaderByHash(hash)
 		} else if number, ok := blockNrOrHash.Number(); ok {
-			header = api.chain.GetHeaderByNumber(uint64(number.Int64()))
+			header, _ = api.headerAt(&number)
 		}
 		if header == nil {
 			return common.Address{}, fmt.Errorf("missing block %v", blockNrOrHash.String())
@@ -218,24 +206,359 @@ func (api *API) GetSigner(rlpOrBlockNr *blockNumberOrHashOrRLP) (common.Address,
 	return api.poi.Author(header)
 }
 
-// SetSignerPerformance sets the performance metric for a signer
+// maxScheduleBlocks is the maximum number of slots GetSchedule returns and the
+// maximum distance it projects beyond the current head.
+const maxScheduleBlocks = 1024
+
+// maxHealthHistoryBlocks is the maximum number of blocks GetHealthHistory scans
+// for health transitions.
+const maxHealthHistoryBlocks = 8192
+
+// headerAt retrieves the canonical header at a given block number, resolving the
+// block tags, or the current head if none is requested.
+func (api *API) headerAt(number *rpc.BlockNumber) (*types.Header, error) {
+	var header *types.Header
+	switch {
+	case number == nil || *number == rpc.LatestBlockNumber || *number == rpc.PendingBlockNumber:
+		header = api.chain.CurrentHeader()
+	case *number == rpc.EarliestBlockNumber:
+		header = api.chain.GetHeaderByNumber(0)
+	case *number == rpc.FinalizedBlockNumber || *number == rpc.SafeBlockNumber:
+		chain, ok := api.chain.(interface {
+			CurrentFinalBlock() *types.Header
+			CurrentSafeBlock() *types.Header
+		})
+		if !ok {
+			return nil, fmt.Errorf("%v block not available", number)
+		}
+		if *number == rpc.FinalizedBlockNumber {
+			header = chain.CurrentFinalBlock()
+		} else {
+			header = chain.CurrentSafeBlock()
+		}
+	case *number < 0:
+		return nil, fmt.Errorf("unsupported block number %v", number)
+	default:
+		header = api.chain.GetHeaderByNumber(uint64(number.Int64()))
+	}
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+	return header, nil
+}
+
+// snapshotAt retrieves the state snapshot at a given block, or at the current
+// head if none is requested.
+func (api *API) snapshotAt(number *rpc.BlockNumber) (*Snapshot, error) {
+	header, err := api.headerAt(number)
+	if err != nil {
+		return nil, err
+	}
+	return api.poi.snapshot(api.chain, header.Number.Uint64(), header.Hash(), nil)
+}
+
+type signerStatus struct {
+	Healthy     bool      `json:"healthy"`            // Whether the signer is healthy
+	Active      bool      `json:"active"`             // Whether the signer is in the active signer pool
+	Performance int64     `json:"performance"`        // Performance metric of the signer
+	Recovery    *Recovery `json:"recovery,omitempty"` // Recovery progress if the signer is unhealthy
+}
+
+// GetHealth retrieves the health, performance and recovery progress of every
+// signer at the specified block.
+func (api *API) GetHealth(number *rpc.BlockNumber) (map[common.Address]*signerStatus, error) {
+	snap, err := api.snapshotAt(number)
+	if err != nil {
+		return nil, err
+	}
+	active := make(map[common.Address]bool)
+	for _, signer := range snap.GetActiveSigners() {
+		active[signer] = true
+	}
+	statuses := make(map[common.Address]*signerStatus)
+	for signer := range snap.Signers {
+		status := &signerStatus{
+			Healthy:     snap.IsHealthy(signer),
+			Active:      active[signer],
+			Performance: snap.GetPerformance(signer),
+		}
+		if recovery, ok := snap.Recoveries[signer]; ok && !status.Healthy {
+			status.Recovery = &recovery
+		}
+		statuses[signer] = status
+	}
+	return statuses, nil
+}
+
+// HealthTransition is a change of the health of a signer, applied by the block
+// at the given height.
+type HealthTransition struct {
+	Number  uint64         `json:"number"`  // Block number the health changed at
+	Hash    common.Hash    `json:"hash"`    // Block hash the health changed at
+	Signer  common.Address `json:"signer"`  // Signer whose health changed
+	Healthy bool           `json:"healthy"` // Whether the signer became healthy or unhealthy
+}
+
+// GetHealthHistory returns the health transitions of the signers applied by the
+// canonical blocks within [from, to], in block order. Signers joining or leaving
+// the signer set are not transitions.
+func (api *API) GetHealthHistory(from rpc.BlockNumber, to rpc.BlockNumber) ([]*HealthTransition, error) {
+	first, err := api.headerAt(&from)
+	if err != nil {
+		return nil, err
+	}
+	last, err := api.headerAt(&to)
+	if err != nil {
+		return nil, err
+	}
+	start, end := first.Number.Uint64(), last.Number.Uint64()
+	if start > end {
+		return nil, fmt.Errorf("invalid block range: %d > %d", start, end)
+	}
+	if end-start >= maxHealthHistoryBlocks {
+		return nil, fmt.Errorf("too many blocks requested: have %d, max %d", end-start+1, maxHealthHistoryBlocks)
+	}
+	// The genesis block doesn't transition anything, start diffing after it
+	if start == 0 {
+		start = 1
+	}
+	transitions := []*HealthTransition{}
+	if start > end {
+		return transitions, nil
+	}
+	parent := api.chain.GetHeaderByNumber(start - 1)
+	if parent == nil {
+		return nil, fmt.Errorf("missing block %d", start-1)
+	}
+	prev, err := api.poi.snapshot(api.chain, parent.Number.Uint64(), parent.Hash(), nil)
+	if err != nil {
+		return nil, err
+	}
+	for number := start; number <= end; number++ {
+		header := api.chain.GetHeaderByNumber(number)
+		if header == nil {
+			return nil, fmt.Errorf("missing block %d", number)
+		}
+		snap, err := api.poi.snapshot(api.chain, number, header.Hash(), nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, signer := range snap.signers() {
+			if _, ok := prev.Signers[signer]; !ok {
+				continue
+			}
+			if healthy := snap.IsHealthy(signer); healthy != prev.IsHealthy(signer) {
+				transitions = append(transitions, &HealthTransition{
+					Number:  number,
+					Hash:    header.Hash(),
+					Signer:  signer,
+					Healthy: healthy,
+				})
+			}
+		}
+		prev = snap
+	}
+	return transitions, nil
+}
+
+// GetFailures returns the number of consecutive in-turn slots each signer missed
+// on the canonical chain up to the specified block.
+func (api *API) GetFailures(number *rpc.BlockNumber) (map[common.Address]int, error) {
+	snap, err := api.snapshotAt(number)
+	if err != nil {
+		return nil, err
+	}
+	failures := make(map[common.Address]int, len(snap.Failures))
+	for signer, count := range snap.Failures {
+		failures[signer] = count
+	}
+	return failures, nil
+}
+
+// GetActiveSigners retrieves the sorted pool of active signers the in-turn
+// rotation runs over at the specified block.
+func (api *API) GetActiveSigners(number *rpc.BlockNumber) ([]common.Address, error) {
+	snap, err := api.snapshotAt(number)
+	if err != nil {
+		return nil, err
+	}
+	return snap.GetActiveSigners(), nil
+}
+
+// GetSchedule returns the expected in-turn and backup signers of count blocks
+// starting at the given block (or the next block if none is requested). Slots
+// of blocks beyond the current head are projected from the head snapshot,
+// assuming that every block is sealed in-turn.
+func (api *API) GetSchedule(from *rpc.BlockNumber, count uint64) ([]*Slot, error) {
+	if count > maxScheduleBlocks {
+		return nil, fmt.Errorf("too many blocks requested: have %d, max %d", count, maxScheduleBlocks)
+	}
+	head := api.chain.CurrentHeader()
+
+	start := head.Number.Uint64() + 1
+	if from != nil && *from >= 0 {
+		start = uint64(from.Int64())
+	}
+	if start == 0 {
+		return nil, errUnknownBlock
+	}
+	// Gather the slots of already known blocks from their parent snapshots
+	slots := make([]*Slot, 0, count)
+	for number := start; number <= head.Number.Uint64() && uint64(len(slots)) < count; number++ {
+		parent := api.chain.GetHeaderByNumber(number - 1)
+		if parent == nil {
+			return nil, fmt.Errorf("missing block %d", number-1)
+		}
+		snap, err := api.poi.snapshot(api.chain, parent.Number.Uint64(), parent.Hash(), nil)
+		if err != nil {
+			return nil, err
+		}
+		slots = append(slots, snap.schedule(1)...)
+	}
+	// Project the remaining slots on top of the current head
+	if remaining := count - uint64(len(slots)); remaining > 0 {
+		var skip uint64
+		if start > head.Number.Uint64()+1 {
+			skip = start - head.Number.Uint64() - 1
+		}
+		if skip > maxScheduleBlocks {
+			return nil, fmt.Errorf("schedule too far in the future: %d blocks beyond head, max %d", skip, maxScheduleBlocks)
+		}
+		snap, err := api.poi.snapshot(api.chain, head.Number.Uint64(), head.Hash(), nil)
+		if err != nil {
+			return nil, err
+		}
+		slots = append(slots, snap.schedule(skip + remaining)[skip:]...)
+	}
+	return slots, nil
+}
+
+// SetSignerPerformance proposes a new performance metric for a signer. The
+// change is voted into the chain and only takes effect once a majority of the
+// signers agree on it.
 func (api *API) SetSignerPerformance(address common.Address, performance int64) error {
-	// Check if the caller is authorized (must be a current signer)
 	header := api.chain.CurrentHeader()
 	snap, err := api.poi.snapshot(api.chain, header.Number.Uint64(), header.Hash(), nil)
 	if err != nil {
 		return err
 	}
-	
-	// Update the performance in the current snapshot
-	api.poi.lock.Lock()
-	defer api.poi.lock.Unlock()
-	
-	if api.poi.recents != nil {
-		if snapshot, ok := api.poi.recents.Get(snap.Hash); ok {
-			return snapshot.SetPerformance(address, performance)
+	if _, ok := snap.Signers[address]; !ok {
+		return errUnauthorizedSigner
+	}
+	if performance < 0 {
+		return errInvalidPerformance
+	}
+	api.poi.proposeStatus(statusPerformance, address, uint64(performance))
+	return nil
+}
+
+// SetSignerHealth proposes a new health status for a signer. The change is
+// voted into the chain and only takes effect once a majority of the signers
+// agree on it.
+func (api *API) SetSignerHealth(address common.Address, healthy bool) error {
+	header := api.chain.CurrentHeader()
+	snap, err := api.poi.snapshot(api.chain, header.Number.Uint64(), header.Hash(), nil)
+	if err != nil {
+		return err
+	}
+	if _, ok := snap.Signers[address]; !ok {
+		return errUnauthorizedSigner
+	}
+	health := Healthy
+	if !healthy {
+		health = Unhealthy
+	}
+	api.poi.proposeStatus(statusHealth, address, uint64(health))
+	return nil
+}
+
+// DeviceProposals returns the current device registry proposals the node tries
+// to uphold and vote on.
+func (api *API) DeviceProposals() map[common.Address]*DeviceVote {
+	api.poi.lock.RLock()
+	defer api.poi.lock.RUnlock()
+
+	proposals := make(map[common.Address]*DeviceVote)
+	for address, vote := range api.poi.deviceProposals {
+		proposals[address] = &DeviceVote{
+			Signer:    api.poi.signer,
+			Address:   address,
+			Metadata:  vote.Metadata,
+			Authorize: vote.Authorize,
 		}
 	}
-	
-	return snap.SetPerformance(address, performance)
+	return proposals
+}
+
+// ProposeDevice injects a new device registry proposal that the signer will
+// attempt to push through. Authorizing a device registers it with the given
+// metadata hash, revoking removes it from the registry.
+func (api *API) ProposeDevice(address common.Address, metadata common.Hash, auth bool) {
+	if !auth {
+		metadata = common.Hash{}
+	}
+	api.poi.proposeDevice(address, metadata, auth)
+}
+
+// DiscardDevice drops a currently running device registry proposal, stopping
+// the signer from casting further votes (either for or against).
+func (api *API) DiscardDevice(address common.Address) {
+	api.poi.discardDevice(address)
+}
+
+// GetDevices retrieves the device identity registry at the specified block.
+func (api *API) GetDevices(number *rpc.BlockNumber) (map[common.Address]Device, error) {
+	snap, err := api.snapshotAt(number)
+	if err != nil {
+		return nil, err
+	}
+	devices := make(map[common.Address]Device, len(snap.Devices))
+	for address, device := range snap.Devices {
+		devices[address] = device
+	}
+	return devices, nil
+}
+
+// GetDevice retrieves the registration of a single device at the specified
+// block, returning nil if the device isn't registered.
+func (api *API) GetDevice(address common.Address, number *rpc.BlockNumber) (*Device, error) {
+	snap, err := api.snapshotAt(number)
+	if err != nil {
+		return nil, err
+	}
+	device, ok := snap.Devices[address]
+	if !ok {
+		return nil, nil
+	}
+	return &device, nil
+}
+
+// SubmitEvidence queues up the proof of a signer equivocating, given as two RLP
+// encoded headers of the same height sealed by the same signer, for inclusion
+// in the blocks sealed by this node. Once included the signer is deauthorized,
+// or marked unhealthy if it's the only one.
+func (api *API) SubmitEvidence(first hexutil.Bytes, second hexutil.Bytes) (common.Hash, error) {
+	evidence := new(Evidence)
+	if err := rlp.DecodeBytes(first, &evidence.First); err != nil {
+		return common.Hash{}, err
+	}
+	if err := rlp.DecodeBytes(second, &evidence.Second); err != nil {
+		return common.Hash{}, err
+	}
+	return api.poi.submitEvidence(api.chain, evidence)
+}
+
+// Rotate hands the identity of the local signer over to a new key. The handover
+// is signed with the current key and queued up for inclusion in the blocks sealed
+// by this node. Once included, the new key takes over the health, performance
+// and recent blocks of the current one, and the node should be switched over to
+// sealing with the new key.
+func (api *API) Rotate(signer common.Address) (*Rotation, error) {
+	return api.poi.rotate(api.chain, signer)
+}
+
+// SubmitRotation queues up a signer rotation signed elsewhere by the key handing
+// its identity over, for inclusion in the blocks sealed by this node.
+func (api *API) SubmitRotation(rotation Rotation) (common.Hash, error) {
+	return api.poi.submitRotation(api.chain, &rotation)
 }
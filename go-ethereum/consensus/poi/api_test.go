@@ -0,0 +1,113 @@
+package poi
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Tests that the health history lists the health transitions of a block range
+// and that block tags are resolved instead of being cast to heights.
+func TestHealthHistory(t *testing.T) {
+	accounts := newTesterAccountPool()
+	signers := []common.Address{accounts.address("A"), accounts.address("B")}
+	if signers[0].Cmp(signers[1]) > 0 {
+		signers[0], signers[1] = signers[1], signers[0]
+	}
+	genspec := &core.Genesis{
+		Config:    new(params.ChainConfig),
+		ExtraData: make([]byte, extraVanity+len(signers)*common.AddressLength+extraSeal),
+		BaseFee:   big.NewInt(params.InitialBaseFee),
+	}
+	*genspec.Config = *params.TestChainConfig
+	genspec.Config.Poi = &params.PoiConfig{Period: 1, Epoch: 30000, RecoveryPeriod: 15}
+	for i, signer := range signers {
+		copy(genspec.ExtraData[extraVanity+i*common.AddressLength:], signer[:])
+	}
+	engine := New(genspec.Config.Poi, rawdb.NewMemoryDatabase())
+	engine.fakeDiff = true
+
+	// A and B vote B unhealthy in blocks 1 and 2, B recovers in block 4
+	votes := []struct {
+		signer    string
+		unhealthy bool
+	}{{"A", true}, {"B", true}, {"A", false}, {"B", false}, {"A", false}}
+
+	_, blocks, _ := core.GenerateChainWithGenesis(genspec, engine, len(votes), nil)
+	for i, block := range blocks {
+		header := block.Header()
+		if i > 0 {
+			header.ParentHash = blocks[i-1].Hash()
+		}
+		header.Extra = make([]byte, extraVanity+extraSeal)
+		header.Difficulty = diffInTurn
+		if votes[i].unhealthy {
+			header.MixDigest = encodeStatusVote(statusHealth, accounts.address("B"), uint64(Unhealthy))
+		}
+		accounts.sign(header, votes[i].signer)
+		blocks[i] = block.WithSeal(header)
+	}
+	chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, genspec, nil, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create test chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to import chain: %v", err)
+	}
+	api := &API{chain: chain, poi: engine}
+
+	transitions, err := api.GetHealthHistory(rpc.EarliestBlockNumber, rpc.LatestBlockNumber)
+	if err != nil {
+		t.Fatalf("failed to retrieve health history: %v", err)
+	}
+	want := []HealthTransition{
+		{Number: 2, Hash: blocks[1].Hash(), Signer: accounts.address("B"), Healthy: false},
+		{Number: 4, Hash: blocks[3].Hash(), Signer: accounts.address("B"), Healthy: true},
+	}
+	if len(transitions) != len(want) {
+		t.Fatalf("transition count mismatch: have %d, want %d", len(transitions), len(want))
+	}
+	for i, transition := range transitions {
+		if *transition != want[i] {
+			t.Errorf("transition %d mismatch: have %+v, want %+v", i, transition, want[i])
+		}
+	}
+	if transitions, err := api.GetHealthHistory(3, 5); err != nil || len(transitions) != 1 || transitions[0].Number != 4 {
+		t.Errorf("ranged history mismatch: have %v (%v), want block 4 only", transitions, err)
+	}
+	// Tags must resolve to their blocks, not wrap around to huge heights
+	pending := rpc.PendingBlockNumber
+	if snap, err := api.snapshotAt(&pending); err != nil || snap.Number != uint64(len(blocks)) {
+		t.Errorf("pending snapshot mismatch: have %v (%v), want block %d", snap, err, len(blocks))
+	}
+	chain.SetFinalized(blocks[1].Header())
+	finalized := rpc.FinalizedBlockNumber
+	if header, err := api.headerAt(&finalized); err != nil || header.Hash() != blocks[1].Hash() {
+		t.Errorf("finalized header mismatch: have %v (%v), want %x", header, err, blocks[1].Hash())
+	}
+	safe := rpc.SafeBlockNumber
+	if _, err := api.headerAt(&safe); err != errUnknownBlock {
+		t.Errorf("safe header error mismatch: have %v, want %v", err, errUnknownBlock)
+	}
+	// Returned maps must not alias the snapshot's
+	devices, _ := api.GetDevices(nil)
+	devices[common.Address{1}] = Device{}
+	failures, _ := api.GetFailures(nil)
+	failures[common.Address{1}] = 1
+
+	snap, _ := engine.snapshot(chain, chain.CurrentHeader().Number.Uint64(), chain.CurrentHeader().Hash(), nil)
+	if _, ok := snap.Devices[common.Address{1}]; ok {
+		t.Errorf("snapshot devices modified through the API")
+	}
+	if _, ok := snap.Failures[common.Address{1}]; ok {
+		t.Errorf("snapshot failures modified through the API")
+	}
+}
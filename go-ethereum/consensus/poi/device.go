@@ -0,0 +1,101 @@
+package poi
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// errUnregisteredSender is returned if sender restriction is enabled and a
+// transaction is sent by an account that is neither a registered device nor an
+// authorized signer.
+var errUnregisteredSender = errors.New("sender is not a registered device")
+
+// Device is an entry of the device identity registry.
+type Device struct {
+	Metadata common.Hash `json:"metadata"` // Hash of the attested device metadata
+	Block    uint64      `json:"block"`    // Block number the registration passed in
+}
+
+// DeviceVote represents a single vote that an authorized signer made to modify
+// the device identity registry.
+type DeviceVote struct {
+	Signer    common.Address `json:"signer"`    // Authorized signer that cast this vote
+	Block     uint64         `json:"block"`     // Block number the vote was cast in (expire old votes)
+	Address   common.Address `json:"address"`   // Device being voted on to change its registration
+	Metadata  common.Hash    `json:"metadata"`  // Hash of the attested device metadata
+	Authorize bool           `json:"authorize"` // Whether to register or revoke the device
+}
+
+// validDeviceVote returns whether it makes sense to cast the specified device
+// vote in the given snapshot context (e.g. don't try to revoke an unregistered
+// device). Registering an already registered device with different metadata is
+// meaningful, it re-attests the device.
+func (s *Snapshot) validDeviceVote(address common.Address, metadata common.Hash, authorize bool) bool {
+	device, registered := s.Devices[address]
+	if authorize {
+		return !registered || device.Metadata != metadata
+	}
+	return registered
+}
+
+// deviceTally returns the number of votes cast for the given device change.
+func (s *Snapshot) deviceTally(address common.Address, metadata common.Hash, authorize bool) int {
+	var votes int
+	for _, vote := range s.DeviceVotes {
+		if vote.Address == address && vote.Authorize == authorize && (!authorize || vote.Metadata == metadata) {
+			votes++
+		}
+	}
+	return votes
+}
+
+// dropDeviceVotes discards all the device votes matching the filter.
+func (s *Snapshot) dropDeviceVotes(drop func(vote *DeviceVote) bool) {
+	for i := 0; i < len(s.DeviceVotes); i++ {
+		if drop(s.DeviceVotes[i]) {
+			s.DeviceVotes = append(s.DeviceVotes[:i], s.DeviceVotes[i+1:]...)
+			i--
+		}
+	}
+}
+
+// castDevice tallies up a device vote cast by a signer and updates the device
+// registry once a majority of the signers agree on the change.
+func (s *Snapshot) castDevice(signer common.Address, number uint64, vote *deviceVote) {
+	// Header authorized, discard any previous vote from the signer on the device
+	s.dropDeviceVotes(func(old *DeviceVote) bool {
+		return old.Signer == signer && old.Address == vote.Address
+	})
+	if !s.validDeviceVote(vote.Address, vote.Metadata, vote.Authorize) {
+		return
+	}
+	s.DeviceVotes = append(s.DeviceVotes, &DeviceVote{
+		Signer:    signer,
+		Block:     number,
+		Address:   vote.Address,
+		Metadata:  vote.Metadata,
+		Authorize: vote.Authorize,
+	})
+	// If the vote passed, update the registry and discard votes around the device
+	if s.deviceTally(vote.Address, vote.Metadata, vote.Authorize) > len(s.Signers)/2 {
+		if vote.Authorize {
+			s.Devices[vote.Address] = Device{Metadata: vote.Metadata, Block: number}
+		} else {
+			delete(s.Devices, vote.Address)
+		}
+		s.dropDeviceVotes(func(old *DeviceVote) bool {
+			return old.Address == vote.Address
+		})
+	}
+}
+
+// registered returns whether an account may transact when sender restriction is
+// enabled, i.e. it's either a registered device or an authorized signer.
+func (s *Snapshot) registered(address common.Address) bool {
+	if _, ok := s.Devices[address]; ok {
+		return true
+	}
+	_, ok := s.Signers[address]
+	return ok
+}
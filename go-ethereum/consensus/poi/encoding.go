@@ -0,0 +1,228 @@
+package poi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"golang.org/x/exp/slices"
+)
+
+// snapshotVersion is the version of the binary snapshot encoding produced by
+// this node. It's stored as the first byte of the database blob, which can never
+// collide with the opening brace of the legacy JSON encoding.
+const snapshotVersion = 1
+
+// errUnknownSnapshotVersion is returned if a stored snapshot was encoded with a
+// format this node doesn't know about.
+var errUnknownSnapshotVersion = errors.New("unknown snapshot encoding version")
+
+// storedSnapshot is the RLP encoding of a snapshot. Maps are flattened into
+// slices sorted by key so the encoding is deterministic.
+type storedSnapshot struct {
+	Number      uint64
+	Hash        common.Hash
+	Signers     []common.Address
+	Recents     []storedRecent
+	Votes       []*Vote
+	Tally       []storedTally
+	StatusVotes []*StatusVote
+	Health      []storedHealth
+	Performance []storedPerformance
+	Recoveries  []storedRecovery
+	Devices     []storedDevice
+	DeviceVotes []*DeviceVote
+	Slashed     []storedSlashed
+	Failures    []storedFailures  `rlp:"optional"`
+	Rotation    []common.Address  `rlp:"optional"`
+	Successors  []storedSuccessor `rlp:"optional"`
+}
+
+type storedRecent struct {
+	Number uint64
+	Signer common.Address
+}
+
+type storedTally struct {
+	Address   common.Address
+	Authorize bool
+	Votes     uint64
+}
+
+type storedHealth struct {
+	Address common.Address
+	Health  SignerHealth
+}
+
+type storedPerformance struct {
+	Address     common.Address
+	Performance uint64 // Two's complement of the int64 metric, RLP has no signed integers
+}
+
+type storedRecovery struct {
+	Address common.Address
+	Since   uint64
+	Sealed  uint64
+}
+
+type storedDevice struct {
+	Address  common.Address
+	Metadata common.Hash
+	Block    uint64
+}
+
+type storedSlashed struct {
+	Address common.Address
+	Number  uint64
+}
+
+type storedFailures struct {
+	Address  common.Address
+	Failures uint64
+}
+
+type storedSuccessor struct {
+	Address common.Address
+	Signer  common.Address
+	Number  uint64
+}
+
+// sortedKeys returns the addresses keying a map in ascending order.
+func sortedKeys[V any](m map[common.Address]V) []common.Address {
+	keys := make([]common.Address, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b common.Address) int {
+		return bytes.Compare(a[:], b[:])
+	})
+	return keys
+}
+
+// encodeSnapshot serializes a snapshot into the versioned binary format.
+func encodeSnapshot(s *Snapshot) ([]byte, error) {
+	enc := &storedSnapshot{
+		Number:      s.Number,
+		Hash:        s.Hash,
+		Signers:     sortedKeys(s.Signers),
+		Votes:       s.Votes,
+		StatusVotes: s.StatusVotes,
+		DeviceVotes: s.DeviceVotes,
+		Rotation:    s.Rotation,
+	}
+	recents := make([]uint64, 0, len(s.Recents))
+	for number := range s.Recents {
+		recents = append(recents, number)
+	}
+	slices.Sort(recents)
+	for _, number := range recents {
+		enc.Recents = append(enc.Recents, storedRecent{Number: number, Signer: s.Recents[number]})
+	}
+	for _, address := range sortedKeys(s.Tally) {
+		tally := s.Tally[address]
+		enc.Tally = append(enc.Tally, storedTally{Address: address, Authorize: tally.Authorize, Votes: uint64(tally.Votes)})
+	}
+	for _, address := range sortedKeys(s.Health) {
+		enc.Health = append(enc.Health, storedHealth{Address: address, Health: s.Health[address]})
+	}
+	for _, address := range sortedKeys(s.Performance) {
+		enc.Performance = append(enc.Performance, storedPerformance{Address: address, Performance: uint64(s.Performance[address])})
+	}
+	for _, address := range sortedKeys(s.Recoveries) {
+		recovery := s.Recoveries[address]
+		enc.Recoveries = append(enc.Recoveries, storedRecovery{Address: address, Since: recovery.Since, Sealed: recovery.Sealed})
+	}
+	for _, address := range sortedKeys(s.Devices) {
+		device := s.Devices[address]
+		enc.Devices = append(enc.Devices, storedDevice{Address: address, Metadata: device.Metadata, Block: device.Block})
+	}
+	for _, address := range sortedKeys(s.Slashed) {
+		enc.Slashed = append(enc.Slashed, storedSlashed{Address: address, Number: s.Slashed[address]})
+	}
+	for _, address := range sortedKeys(s.Failures) {
+		enc.Failures = append(enc.Failures, storedFailures{Address: address, Failures: uint64(s.Failures[address])})
+	}
+	for _, address := range sortedKeys(s.Successors) {
+		successor := s.Successors[address]
+		enc.Successors = append(enc.Successors, storedSuccessor{Address: address, Signer: successor.Signer, Number: successor.Number})
+	}
+	blob, err := rlp.EncodeToBytes(enc)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{snapshotVersion}, blob...), nil
+}
+
+// decodeSnapshot deserializes a snapshot stored in either the binary format or
+// the legacy JSON one. The returned flag reports whether the blob was legacy.
+func decodeSnapshot(blob []byte) (*Snapshot, bool, error) {
+	if len(blob) == 0 {
+		return nil, false, errors.New("empty snapshot blob")
+	}
+	if blob[0] == '{' {
+		snap := new(Snapshot)
+		if err := json.Unmarshal(blob, snap); err != nil {
+			return nil, true, err
+		}
+		return snap, true, nil
+	}
+	if blob[0] != snapshotVersion {
+		return nil, false, fmt.Errorf("%w: %d", errUnknownSnapshotVersion, blob[0])
+	}
+	dec := new(storedSnapshot)
+	if err := rlp.DecodeBytes(blob[1:], dec); err != nil {
+		return nil, false, err
+	}
+	snap := &Snapshot{
+		Number:      dec.Number,
+		Hash:        dec.Hash,
+		Signers:     make(map[common.Address]struct{}, len(dec.Signers)),
+		Recents:     make(map[uint64]common.Address, len(dec.Recents)),
+		Votes:       dec.Votes,
+		Tally:       make(map[common.Address]Tally, len(dec.Tally)),
+		StatusVotes: dec.StatusVotes,
+		Health:      make(map[common.Address]SignerHealth, len(dec.Health)),
+		Performance: make(map[common.Address]int64, len(dec.Performance)),
+		Recoveries:  make(map[common.Address]Recovery, len(dec.Recoveries)),
+		Devices:     make(map[common.Address]Device, len(dec.Devices)),
+		DeviceVotes: dec.DeviceVotes,
+		Slashed:     make(map[common.Address]uint64, len(dec.Slashed)),
+		Failures:    make(map[common.Address]int, len(dec.Failures)),
+		Rotation:    dec.Rotation,
+		Successors:  make(map[common.Address]Successor, len(dec.Successors)),
+	}
+	for _, signer := range dec.Signers {
+		snap.Signers[signer] = struct{}{}
+	}
+	for _, recent := range dec.Recents {
+		snap.Recents[recent.Number] = recent.Signer
+	}
+	for _, tally := range dec.Tally {
+		snap.Tally[tally.Address] = Tally{Authorize: tally.Authorize, Votes: int(tally.Votes)}
+	}
+	for _, health := range dec.Health {
+		snap.Health[health.Address] = health.Health
+	}
+	for _, performance := range dec.Performance {
+		snap.Performance[performance.Address] = int64(performance.Performance)
+	}
+	for _, recovery := range dec.Recoveries {
+		snap.Recoveries[recovery.Address] = Recovery{Since: recovery.Since, Sealed: recovery.Sealed}
+	}
+	for _, device := range dec.Devices {
+		snap.Devices[device.Address] = Device{Metadata: device.Metadata, Block: device.Block}
+	}
+	for _, slashed := range dec.Slashed {
+		snap.Slashed[slashed.Address] = slashed.Number
+	}
+	for _, failures := range dec.Failures {
+		snap.Failures[failures.Address] = int(failures.Failures)
+	}
+	for _, successor := range dec.Successors {
+		snap.Successors[successor.Address] = Successor{Signer: successor.Signer, Number: successor.Number}
+	}
+	return snap, false, nil
+}
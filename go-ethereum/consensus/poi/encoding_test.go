@@ -0,0 +1,170 @@
+package poi
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// testerSnapshot creates a snapshot with the given number of signers and every
+// field populated, to exercise the snapshot encodings.
+func testerSnapshot(signers int) *Snapshot {
+	config := &params.PoiConfig{Period: 1, Epoch: 30000, WeightedSelection: true}
+
+	addresses := make([]common.Address, signers)
+	for i := range addresses {
+		addresses[i] = common.BytesToAddress([]byte{byte(i >> 8), byte(i), 0x01})
+	}
+	snap := newSnapshot(config, nil, 4096, common.Hash{0x01}, addresses)
+	for i, address := range addresses {
+		device := common.BytesToAddress([]byte{byte(i >> 8), byte(i), 0x02})
+
+		snap.Recents[snap.Number-uint64(i)] = address
+		snap.Performance[address] = int64(i) - int64(signers)/2
+		snap.Votes = append(snap.Votes, &Vote{Signer: address, Block: uint64(i), Address: device, Authorize: i%2 == 0})
+		snap.Tally[device] = Tally{Authorize: i%2 == 0, Votes: 1}
+		snap.StatusVotes = append(snap.StatusVotes, &StatusVote{Signer: address, Block: uint64(i), Address: address, Kind: statusPerformance, Value: uint64(i)})
+		snap.Devices[device] = Device{Metadata: common.Hash{byte(i)}, Block: uint64(i)}
+		snap.DeviceVotes = append(snap.DeviceVotes, &DeviceVote{Signer: address, Block: uint64(i), Address: device, Metadata: common.Hash{byte(i)}, Authorize: true})
+
+		if i%4 == 0 {
+			snap.Health[address] = Unhealthy
+			snap.Recoveries[address] = Recovery{Since: uint64(i), Sealed: uint64(i / 2)}
+			snap.Slashed[address] = uint64(i)
+		}
+		if i%4 == 1 {
+			snap.Failures[address] = i
+		}
+		if i%4 == 2 {
+			snap.Successors[device] = Successor{Signer: address, Number: uint64(i)}
+		}
+	}
+	snap.updateRotation(snap.Number)
+	return snap
+}
+
+// Tests that snapshots survive a round trip through the binary encoding and that
+// the encoding is deterministic.
+func TestSnapshotEncoding(t *testing.T) {
+	snap := testerSnapshot(64)
+
+	blob, err := encodeSnapshot(snap)
+	if err != nil {
+		t.Fatalf("failed to encode snapshot: %v", err)
+	}
+	again, err := encodeSnapshot(snap.copy())
+	if err != nil {
+		t.Fatalf("failed to re-encode snapshot: %v", err)
+	}
+	if string(blob) != string(again) {
+		t.Errorf("encoding not deterministic")
+	}
+	dec, legacy, err := decodeSnapshot(blob)
+	if err != nil {
+		t.Fatalf("failed to decode snapshot: %v", err)
+	}
+	if legacy {
+		t.Errorf("binary snapshot reported as legacy")
+	}
+	dec.config, dec.sigcache = snap.config, snap.sigcache
+	if !reflect.DeepEqual(dec, snap) {
+		t.Errorf("snapshot mismatch:\nhave %+v\nwant %+v", dec, snap)
+	}
+	// Unknown versions must be rejected rather than misinterpreted
+	blob[0] = snapshotVersion + 1
+	if _, _, err := decodeSnapshot(blob); err == nil {
+		t.Errorf("unknown snapshot version accepted")
+	}
+}
+
+// Tests that snapshots stored in the legacy JSON encoding are still loaded, and
+// get migrated to the binary encoding by the engine but not by loading alone.
+func TestSnapshotLegacyMigration(t *testing.T) {
+	var (
+		db   = rawdb.NewMemoryDatabase()
+		snap = testerSnapshot(8)
+		key  = append(rawdb.PoiSnapshotPrefix, snap.Hash[:]...)
+	)
+	blob, err := encodeLegacySnapshot(snap)
+	if err != nil {
+		t.Fatalf("failed to marshal legacy snapshot: %v", err)
+	}
+	if err := db.Put(key, blob); err != nil {
+		t.Fatalf("failed to store legacy snapshot: %v", err)
+	}
+	loaded, legacy, err := loadSnapshot(snap.config, nil, db, snap.Hash)
+	if err != nil {
+		t.Fatalf("failed to load legacy snapshot: %v", err)
+	}
+	if !legacy {
+		t.Errorf("legacy snapshot not reported as legacy")
+	}
+	if !reflect.DeepEqual(loaded.Signers, snap.Signers) || !reflect.DeepEqual(loaded.Performance, snap.Performance) || !reflect.DeepEqual(loaded.Devices, snap.Devices) {
+		t.Errorf("legacy snapshot mismatch: have %+v, want %+v", loaded, snap)
+	}
+	if stored, _ := db.Get(key); !bytes.Equal(stored, blob) {
+		t.Fatalf("legacy snapshot rewritten while loading")
+	}
+	// Retrieving the checkpoint through the engine migrates it
+	if _, err := New(snap.config, db).snapshot(nil, snap.Number, snap.Hash, nil); err != nil {
+		t.Fatalf("failed to retrieve legacy snapshot: %v", err)
+	}
+	migrated, err := db.Get(key)
+	if err != nil {
+		t.Fatalf("failed to retrieve migrated snapshot: %v", err)
+	}
+	if migrated[0] != snapshotVersion {
+		t.Errorf("snapshot not migrated: version byte %#x", migrated[0])
+	}
+	if len(migrated) >= len(blob) {
+		t.Errorf("binary snapshot not smaller than legacy one: have %d, legacy %d", len(migrated), len(blob))
+	}
+}
+
+// encodeLegacySnapshot serializes a snapshot into the legacy JSON encoding.
+func encodeLegacySnapshot(snap *Snapshot) ([]byte, error) {
+	return json.Marshal(snap)
+}
+
+func BenchmarkSnapshotEncodeJSON(b *testing.B) { benchmarkSnapshotEncode(b, encodeLegacySnapshot) }
+func BenchmarkSnapshotEncodeRLP(b *testing.B)  { benchmarkSnapshotEncode(b, encodeSnapshot) }
+
+func benchmarkSnapshotEncode(b *testing.B, encode func(*Snapshot) ([]byte, error)) {
+	snap := testerSnapshot(256)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	var size int
+	for i := 0; i < b.N; i++ {
+		blob, err := encode(snap)
+		if err != nil {
+			b.Fatal(err)
+		}
+		size = len(blob)
+	}
+	b.ReportMetric(float64(size), "bytes")
+}
+
+func BenchmarkSnapshotDecodeJSON(b *testing.B) { benchmarkSnapshotDecode(b, encodeLegacySnapshot) }
+func BenchmarkSnapshotDecodeRLP(b *testing.B)  { benchmarkSnapshotDecode(b, encodeSnapshot) }
+
+func benchmarkSnapshotDecode(b *testing.B, encode func(*Snapshot) ([]byte, error)) {
+	blob, err := encode(testerSnapshot(256))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, _, err := decodeSnapshot(blob); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package poi
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// maxEvidence is the maximum number of equivocation evidences a single header
+// may carry.
+const maxEvidence = 4
+
+var (
+	// errInvalidEvidence is returned if an equivocation evidence doesn't consist
+	// of two different headers of the same height sealed by the same signer.
+	errInvalidEvidence = errors.New("invalid equivocation evidence")
+
+	// errStaleEvidence is returned if an equivocation evidence is submitted that
+	// can't be applied anymore, because it's too old, the offender is not a
+	// signer or it has already been punished for the offence.
+	errStaleEvidence = errors.New("stale equivocation evidence")
+)
+
+// Evidence is the proof of a signer equivocating: two different headers of the
+// same height both sealed by the same signer.
+type Evidence struct {
+	First  *types.Header `json:"first"`
+	Second *types.Header `json:"second"`
+}
+
+// Hash returns the hash identifying the evidence.
+func (e *Evidence) Hash() common.Hash {
+	enc, _ := rlp.EncodeToBytes(e)
+	return crypto.Keccak256Hash(enc)
+}
+
+// Number returns the height of the offence.
+func (e *Evidence) Number() uint64 {
+	return e.First.Number.Uint64()
+}
+
+// sanityCheck verifies the parts of the evidence that can be checked without
+// recovering the signatures.
+func (e *Evidence) sanityCheck() error {
+	if e.First == nil || e.Second == nil || e.First.Number == nil || e.Second.Number == nil {
+		return errInvalidEvidence
+	}
+	if e.First.Number.Cmp(e.Second.Number) != 0 || e.First.Number.Sign() <= 0 || !e.First.Number.IsUint64() {
+		return errInvalidEvidence
+	}
+	// The headers are untrusted, reject the ones the seal hash can't be computed
+	// of instead of panicking on them
+	for _, header := range []*types.Header{e.First, e.Second} {
+		if len(header.Extra) < extraVanity+extraSeal {
+			return errInvalidEvidence
+		}
+		if header.WithdrawalsHash != nil || header.ExcessBlobGas != nil || header.BlobGasUsed != nil || header.ParentBeaconRoot != nil {
+			return errInvalidEvidence
+		}
+	}
+	// Headers only differing in their seal are the same block signed twice
+	if SealHash(e.First) == SealHash(e.Second) {
+		return errInvalidEvidence
+	}
+	return nil
+}
+
+// offender verifies the evidence and returns the signer that equivocated.
+func (e *Evidence) offender(sigcache *sigLRU) (common.Address, error) {
+	if err := e.sanityCheck(); err != nil {
+		return common.Address{}, err
+	}
+	first, err := ecrecover(e.First, sigcache)
+	if err != nil {
+		return common.Address{}, errInvalidEvidence
+	}
+	second, err := ecrecover(e.Second, sigcache)
+	if err != nil {
+		return common.Address{}, errInvalidEvidence
+	}
+	if first != second {
+		return common.Address{}, errInvalidEvidence
+	}
+	return first, nil
+}
+
+// validEvidence returns whether a verified evidence against the given offender
+// may still be applied on top of the snapshot by a header of the given number.
+// Evidence expires after an epoch and every offence is only punished once. If
+// the offender handed its identity over to a new key since, the evidence counts
+// against the new key.
+func (s *Snapshot) validEvidence(offender common.Address, offence uint64, number uint64) bool {
+	offender = s.successor(offender, offence)
+	if _, ok := s.Signers[offender]; !ok {
+		return false
+	}
+	if offence >= number || offence+s.config.Epoch <= number {
+		return false
+	}
+	if slashed, ok := s.Slashed[offender]; ok && offence <= slashed {
+		return false
+	}
+	return true
+}
+
+// slash punishes a signer for equivocating at the given height. The offender, or
+// the key it handed its identity over to, is deauthorized, unless it's the last
+// signer in which case it's marked unhealthy instead to keep the chain alive.
+func (s *Snapshot) slash(offender common.Address, offence uint64, header *types.Header) {
+	number := header.Number.Uint64()
+	offender = s.successor(offender, offence)
+
+	s.Slashed[offender] = offence
+	if len(s.Signers) > 1 {
+		s.deauthorize(offender, number)
+
+		// Discard any previous votes around the slashed signer
+		for i := 0; i < len(s.Votes); i++ {
+			if s.Votes[i].Address == offender {
+				s.Votes = append(s.Votes[:i], s.Votes[i+1:]...)
+				i--
+			}
+		}
+		delete(s.Tally, offender)
+		return
+	}
+	s.setStatus(statusHealth, offender, uint64(Unhealthy), header.Time)
+}
+
+// pruneSlashed forgets about punished offences that no evidence can be applied
+// for anymore.
+func (s *Snapshot) pruneSlashed(number uint64) {
+	for offender, offence := range s.Slashed {
+		if offence+s.config.Epoch <= number {
+			delete(s.Slashed, offender)
+		}
+	}
+}
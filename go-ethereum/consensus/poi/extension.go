@@ -0,0 +1,103 @@
+package poi
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+const (
+	extensionVersion = 1  // Version of the header extension format produced by this node
+	maxDeviceVotes   = 32 // Maximum number of device votes a single header may carry
+)
+
+var (
+	// errInvalidExtension is returned if a non-checkpoint block contains data
+	// between the extra-data vanity and seal that isn't a valid header extension.
+	errInvalidExtension = errors.New("invalid header extension")
+
+	// errInvalidDeviceVotes is returned if a header extension carries too many
+	// device votes or more than one vote on the same device.
+	errInvalidDeviceVotes = errors.New("invalid device votes in header extension")
+)
+
+// headerExtension is the PoI specific payload of non-checkpoint headers, carried
+// RLP encoded in the extra-data between the vanity and the seal, thus covered by
+// the signature like the rest of the header.
+type headerExtension struct {
+	Version uint64        // Version of the extension format
+	Devices []*deviceVote // Device identity votes cast by the signer of the block
+
+	Evidence  []*Evidence `rlp:"optional"`     // Equivocation evidences to punish signers for
+	Telemetry *telemetry  `rlp:"optional,nil"` // Self-reported state of the signer's node
+	Rotations []*Rotation `rlp:"optional"`     // Signer key handovers to apply
+}
+
+// deviceVote is a single device identity vote as carried in a header extension.
+type deviceVote struct {
+	Address   common.Address // Device being voted on to change its registration
+	Metadata  common.Hash    // Hash of the attested device metadata (zero when revoking)
+	Authorize bool           // Whether to register or revoke the device
+}
+
+// encodeExtension RLP encodes a header extension, returning nil if there's
+// nothing to carry.
+func encodeExtension(ext *headerExtension) ([]byte, error) {
+	if ext == nil || (len(ext.Devices) == 0 && len(ext.Evidence) == 0 && ext.Telemetry == nil && len(ext.Rotations) == 0) {
+		return nil, nil
+	}
+	return rlp.EncodeToBytes(ext)
+}
+
+// decodeExtension extracts the header extension of a non-checkpoint header,
+// returning nil if the header doesn't carry any.
+func decodeExtension(header *types.Header) (*headerExtension, error) {
+	if len(header.Extra) < extraVanity+extraSeal {
+		return nil, errMissingSignature
+	}
+	blob := header.Extra[extraVanity : len(header.Extra)-extraSeal]
+	if len(blob) == 0 {
+		return nil, nil
+	}
+	ext := new(headerExtension)
+	if err := rlp.DecodeBytes(blob, ext); err != nil {
+		return nil, errInvalidExtension
+	}
+	if ext.Version != extensionVersion {
+		return nil, errInvalidExtension
+	}
+	if len(ext.Devices) > maxDeviceVotes {
+		return nil, errInvalidDeviceVotes
+	}
+	seen := make(map[common.Address]struct{}, len(ext.Devices))
+	for _, vote := range ext.Devices {
+		if _, ok := seen[vote.Address]; ok {
+			return nil, errInvalidDeviceVotes
+		}
+		seen[vote.Address] = struct{}{}
+	}
+	if len(ext.Evidence) > maxEvidence {
+		return nil, errInvalidEvidence
+	}
+	for _, evidence := range ext.Evidence {
+		if err := evidence.sanityCheck(); err != nil {
+			return nil, err
+		}
+	}
+	if ext.Telemetry != nil {
+		if err := ext.Telemetry.sanityCheck(); err != nil {
+			return nil, err
+		}
+	}
+	if len(ext.Rotations) > maxRotations {
+		return nil, errInvalidRotation
+	}
+	for _, rotation := range ext.Rotations {
+		if err := rotation.sanityCheck(); err != nil {
+			return nil, err
+		}
+	}
+	return ext, nil
+}
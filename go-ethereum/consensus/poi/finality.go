@@ -0,0 +1,441 @@
+package poi
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+const (
+	// AttestationDelay is the number of blocks behind the head signers attest
+	// to, so that short-lived forks at the head don't split their attestations.
+	AttestationDelay = 2
+
+	// attestationWindow is the number of blocks behind the head attestations are
+	// tracked for. Older ones can't finalize anything the chain hasn't moved past.
+	attestationWindow = 256
+
+	// maxPendingAttestations is the number of attestations for blocks not yet
+	// imported that are kept around until the blocks arrive.
+	maxPendingAttestations = 4096
+
+	// attestationTag separates the attested data from any other signed message.
+	attestationTag = "poi-attestation"
+)
+
+var (
+	// errAlreadyAttested is returned if the local signer is asked to attest to a
+	// block not higher than the last one it attested to.
+	errAlreadyAttested = errors.New("block height already attested")
+
+	// errConflictingAttestation is returned if the local signer is asked to attest
+	// to a block not descending from the finalized block.
+	errConflictingAttestation = errors.New("block conflicts with finalized block")
+
+	// errStaleAttestation is returned if an attestation is for a block at or
+	// below the finalized block, or outside the tracked window.
+	errStaleAttestation = errors.New("stale attestation")
+
+	// errFutureAttestation is returned if an attestation is for a block too far
+	// ahead of the head to be kept until it's imported.
+	errFutureAttestation = errors.New("attestation too far in the future")
+
+	// errInvalidAttestation is returned if the signature of an attestation is
+	// malformed.
+	errInvalidAttestation = errors.New("invalid attestation signature")
+)
+
+// Attestation is the vote of a signer for a block to become final. A block is
+// final once more than 2/3 of the active signers attested to it, and safe once
+// more than half did.
+type Attestation struct {
+	Number    uint64      `json:"number"`
+	Hash      common.Hash `json:"hash"`
+	Signature []byte      `json:"signature"`
+}
+
+// AttestationRLP returns the rlp bytes which need to be signed to attest to a
+// block. The signature is made over the keccak256 hash of the bytes.
+func AttestationRLP(number uint64, hash common.Hash) []byte {
+	blob, err := rlp.EncodeToBytes([]interface{}{attestationTag, number, hash})
+	if err != nil {
+		panic("can't encode: " + err.Error())
+	}
+	return blob
+}
+
+// ID returns a unique identifier of the attestation, including the signature.
+func (a *Attestation) ID() common.Hash {
+	return crypto.Keccak256Hash(AttestationRLP(a.Number, a.Hash), a.Signature)
+}
+
+// Signer recovers the account that signed the attestation.
+func (a *Attestation) Signer() (common.Address, error) {
+	if len(a.Signature) != crypto.SignatureLength {
+		return common.Address{}, errInvalidAttestation
+	}
+	pubkey, err := crypto.Ecrecover(crypto.Keccak256(AttestationRLP(a.Number, a.Hash)), a.Signature)
+	if err != nil {
+		return common.Address{}, err
+	}
+	var signer common.Address
+	copy(signer[:], crypto.Keccak256(pubkey[1:])[12:])
+	return signer, nil
+}
+
+// attestedBlock is the last block a signer attested to.
+type attestedBlock struct {
+	Number uint64
+	Hash   common.Hash
+}
+
+// readAttested retrieves the last block the signer attested to, or nil if it
+// never attested to any.
+func readAttested(db ethdb.KeyValueReader, signer common.Address) (*attestedBlock, error) {
+	key := append(rawdb.PoiAttestedPrefix, signer[:]...)
+	if ok, err := db.Has(key); !ok || err != nil {
+		return nil, err
+	}
+	blob, err := db.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	attested := new(attestedBlock)
+	if err := rlp.DecodeBytes(blob, attested); err != nil {
+		return nil, err
+	}
+	return attested, nil
+}
+
+// writeAttested stores the last block the signer attested to.
+func writeAttested(db ethdb.KeyValueWriter, signer common.Address, attested *attestedBlock) error {
+	blob, err := rlp.EncodeToBytes(attested)
+	if err != nil {
+		return err
+	}
+	return db.Put(append(rawdb.PoiAttestedPrefix, signer[:]...), blob)
+}
+
+// Attest signs an attestation for the given header with the local signing key.
+// The local signer never attests twice at the same height, nor below the last
+// height it attested to, not even across restarts: the last attested block is
+// persisted before the attestation is returned. After a reorg the signer moves
+// on to the new canonical branch, as long as it descends from the finalized
+// block.
+func (c *Poi) Attest(chain FinalityChain, header *types.Header) (*Attestation, error) {
+	number := header.Number.Uint64()
+
+	c.attestLock.Lock()
+	defer c.attestLock.Unlock()
+
+	// Don't hold the signer fields for the entire signing procedure
+	c.lock.RLock()
+	signer, signFn := c.signer, c.signFn
+	c.lock.RUnlock()
+
+	if signFn == nil {
+		return nil, errUnauthorizedSigner
+	}
+	last, err := readAttested(c.db, signer)
+	if err != nil {
+		return nil, err
+	}
+	if last != nil && number <= last.Number {
+		return nil, errAlreadyAttested
+	}
+	if final := chain.CurrentFinalBlock(); final != nil && conflicts(chain, header, final) {
+		log.Warn("Refused attestation conflicting with finalized block", "number", number, "hash", header.Hash(), "finalized", final.Number)
+		return nil, errConflictingAttestation
+	}
+	snap, err := c.snapshot(chain, number, header.Hash(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := snap.Signers[signer]; !ok {
+		return nil, errUnauthorizedSigner
+	}
+	sig, err := signFn(accounts.Account{Address: signer}, accounts.MimetypePoiAttestation, AttestationRLP(number, header.Hash()))
+	if err != nil {
+		return nil, err
+	}
+	if err := writeAttested(c.db, signer, &attestedBlock{Number: number, Hash: header.Hash()}); err != nil {
+		return nil, err
+	}
+	return &Attestation{Number: number, Hash: header.Hash(), Signature: sig}, nil
+}
+
+// conflicts returns whether the header is neither a canonical block at or below
+// the finalized one, nor a descendant of it.
+func conflicts(chain consensus.ChainHeaderReader, header *types.Header, final *types.Header) bool {
+	if header.Number.Cmp(final.Number) <= 0 {
+		canon := chain.GetHeaderByNumber(header.Number.Uint64())
+		return canon == nil || canon.Hash() != header.Hash()
+	}
+	for header != nil && header.Number.Cmp(final.Number) > 0 {
+		header = chain.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	}
+	return header == nil || header.Hash() != final.Hash()
+}
+
+// FinalityChain is the chain the finality gadget reads blocks from and marks the
+// finalized and safe blocks in, e.g. core.BlockChain.
+type FinalityChain interface {
+	consensus.ChainHeaderReader
+
+	// CurrentFinalBlock retrieves the current finalized block.
+	CurrentFinalBlock() *types.Header
+
+	// CurrentSafeBlock retrieves the current safe block.
+	CurrentSafeBlock() *types.Header
+
+	// SetFinalized sets the finalized block.
+	SetFinalized(header *types.Header)
+
+	// SetSafe sets the safe block.
+	SetSafe(header *types.Header)
+}
+
+// Finality tallies up the attestations of the signers and advances the finalized
+// and safe blocks of the chain once enough signers attested to a canonical block.
+// Attestations for blocks on side chains are kept, and counted once a reorg makes
+// the blocks canonical. Attestations for blocks not yet imported are kept until
+// the blocks arrive.
+type Finality struct {
+	engine *Poi
+	chain  FinalityChain
+
+	votes   map[uint64]map[common.Hash]map[common.Address]*Attestation // Verified attestations per height and attested block
+	pending map[uint64]map[common.Hash]map[common.Address]*Attestation // Attestations for blocks not yet imported
+	queued  int                                                        // Number of pending attestations
+	lock    sync.Mutex
+}
+
+// NewFinality creates a finality gadget tallying attestations for the given chain.
+func NewFinality(engine *Poi, chain FinalityChain) *Finality {
+	return &Finality{
+		engine:  engine,
+		chain:   chain,
+		votes:   make(map[uint64]map[common.Hash]map[common.Address]*Attestation),
+		pending: make(map[uint64]map[common.Hash]map[common.Address]*Attestation),
+	}
+}
+
+// Add verifies an attestation and tallies it up, finalizing the attested block if
+// enough signers attested to it. It returns whether the attestation is new, so
+// that only new ones get relayed. Attestations for blocks not yet imported can't
+// be verified, they are kept until the block arrives and aren't reported as new.
+func (f *Finality) Add(att *Attestation) (bool, error) {
+	signer, err := att.Signer()
+	if err != nil {
+		return false, err
+	}
+	head := f.chain.CurrentHeader().Number.Uint64()
+	if att.Number+attestationWindow < head {
+		return false, errStaleAttestation
+	}
+	if att.Number > head+attestationWindow {
+		return false, errFutureAttestation
+	}
+	if final := f.chain.CurrentFinalBlock(); final != nil && att.Number <= final.Number.Uint64() {
+		return false, errStaleAttestation
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if head > attestationWindow {
+		f.prune(head - attestationWindow)
+	}
+	header := f.chain.GetHeader(att.Hash, att.Number)
+	if header == nil {
+		return false, f.queue(signer, att)
+	}
+	added, err := f.add(signer, att)
+	if !added || err != nil {
+		return false, err
+	}
+	return true, f.tally(header)
+}
+
+// Update verifies and tallies up the pending attestations of the blocks imported
+// since, and re-tallies the attestations of all canonical blocks, so that votes
+// cast on a side chain count once a reorg makes it canonical. It is meant to be
+// called on every new head and returns the attestations that became verified.
+func (f *Finality) Update() []*Attestation {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	head := f.chain.CurrentHeader().Number.Uint64()
+	if head > attestationWindow {
+		f.prune(head - attestationWindow)
+	}
+	// Move the attestations of the imported blocks over to the verified ones
+	var verified []*Attestation
+	for number, blocks := range f.pending {
+		for hash, atts := range blocks {
+			if f.chain.GetHeader(hash, number) == nil {
+				continue
+			}
+			delete(blocks, hash)
+			f.queued -= len(atts)
+
+			for signer, att := range atts {
+				if added, err := f.add(signer, att); err != nil {
+					log.Trace("Dropped pending attestation", "number", number, "hash", hash, "err", err)
+				} else if added {
+					verified = append(verified, att)
+				}
+			}
+		}
+		if len(blocks) == 0 {
+			delete(f.pending, number)
+		}
+	}
+	// Tally up the canonical blocks from the oldest one, finalizing the highest
+	var from uint64
+	if final := f.chain.CurrentFinalBlock(); final != nil {
+		from = final.Number.Uint64() + 1
+	}
+	if head > attestationWindow && from < head-attestationWindow {
+		from = head - attestationWindow
+	}
+	for number := from; number <= head; number++ {
+		if len(f.votes[number]) == 0 {
+			continue
+		}
+		header := f.chain.GetHeaderByNumber(number)
+		if header == nil || len(f.votes[number][header.Hash()]) == 0 {
+			continue
+		}
+		if err := f.tally(header); err != nil {
+			log.Debug("Failed to tally attestations", "number", number, "hash", header.Hash(), "err", err)
+		}
+	}
+	return verified
+}
+
+// queue keeps an attestation for a block not yet imported until the block arrives.
+// The lock must be held.
+func (f *Finality) queue(signer common.Address, att *Attestation) error {
+	if f.queued >= maxPendingAttestations {
+		return errUnknownBlock
+	}
+	blocks := f.pending[att.Number]
+	if blocks == nil {
+		blocks = make(map[common.Hash]map[common.Address]*Attestation)
+		f.pending[att.Number] = blocks
+	}
+	atts := blocks[att.Hash]
+	if atts == nil {
+		atts = make(map[common.Address]*Attestation)
+		blocks[att.Hash] = atts
+	}
+	if _, ok := atts[signer]; !ok {
+		atts[signer] = att
+		f.queued++
+	}
+	return nil
+}
+
+// add verifies that the signer of an attestation for an imported block was
+// authorized at the block, and stores the attestation, returning whether it is
+// new. The lock must be held.
+func (f *Finality) add(signer common.Address, att *Attestation) (bool, error) {
+	snap, err := f.engine.snapshot(f.chain, att.Number, att.Hash, nil)
+	if err != nil {
+		return false, err
+	}
+	if _, ok := snap.Signers[signer]; !ok {
+		return false, errUnauthorizedSigner
+	}
+	blocks := f.votes[att.Number]
+	if blocks == nil {
+		blocks = make(map[common.Hash]map[common.Address]*Attestation)
+		f.votes[att.Number] = blocks
+	}
+	votes := blocks[att.Hash]
+	if votes == nil {
+		votes = make(map[common.Address]*Attestation)
+		blocks[att.Hash] = votes
+	}
+	if _, ok := votes[signer]; ok {
+		return false, nil
+	}
+	votes[signer] = att
+	return true, nil
+}
+
+// tally counts the attestations of the active signers for a block and marks it
+// safe or final if enough of them attested to it and the block is canonical.
+// The lock must be held.
+func (f *Finality) tally(header *types.Header) error {
+	number, hash := header.Number.Uint64(), header.Hash()
+
+	snap, err := f.engine.snapshot(f.chain, number, hash, nil)
+	if err != nil {
+		return err
+	}
+	// Only active signers count towards finality, unhealthy ones are still
+	// allowed to attest but don't carry any weight
+	active := snap.GetActiveSigners()
+	var count int
+	for _, signer := range active {
+		if _, ok := f.votes[number][hash][signer]; ok {
+			count++
+		}
+	}
+	if canon := f.chain.GetHeaderByNumber(number); canon == nil || canon.Hash() != hash {
+		return nil // Side chain block, keep the votes in case it becomes canonical
+	}
+	if 2*count > len(active) {
+		if safe := f.chain.CurrentSafeBlock(); safe == nil || safe.Number.Uint64() < number {
+			f.chain.SetSafe(header)
+		}
+	}
+	if final := f.chain.CurrentFinalBlock(); 3*count > 2*len(active) && (final == nil || final.Number.Uint64() < number) {
+		log.Info("Finalized block", "number", number, "hash", hash, "attestations", count, "signers", len(active))
+		f.chain.SetFinalized(header)
+		f.prune(number)
+	}
+	return nil
+}
+
+// Attestations returns the verified attestations collected for a block.
+func (f *Finality) Attestations(hash common.Hash) []*Attestation {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	var atts []*Attestation
+	for _, blocks := range f.votes {
+		for _, att := range blocks[hash] {
+			atts = append(atts, att)
+		}
+	}
+	return atts
+}
+
+// prune drops the attestations of all blocks at or below the given height, which
+// can't advance finality anymore. The lock must be held.
+func (f *Finality) prune(number uint64) {
+	for height := range f.votes {
+		if height <= number {
+			delete(f.votes, height)
+		}
+	}
+	for height, blocks := range f.pending {
+		if height <= number {
+			for _, atts := range blocks {
+				f.queued -= len(atts)
+			}
+			delete(f.pending, height)
+		}
+	}
+}
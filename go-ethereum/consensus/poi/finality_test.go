@@ -0,0 +1,289 @@
+package poi
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+	"golang.org/x/exp/slices"
+)
+
+// Tests that a block becomes safe once more than half of the signers attested to
+// it and final once more than 2/3 did, and that only signers may attest.
+func TestFinality(t *testing.T) {
+	keys := make([]*ecdsa.PrivateKey, 3)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+	}
+	slices.SortFunc(keys, func(a, b *ecdsa.PrivateKey) int {
+		return crypto.PubkeyToAddress(a.PublicKey).Cmp(crypto.PubkeyToAddress(b.PublicKey))
+	})
+	genspec := &core.Genesis{
+		Config:    new(params.ChainConfig),
+		ExtraData: make([]byte, extraVanity+len(keys)*common.AddressLength+extraSeal),
+		BaseFee:   big.NewInt(params.InitialBaseFee),
+	}
+	*genspec.Config = *params.TestChainConfig
+	genspec.Config.Poi = &params.PoiConfig{Period: 1, Epoch: 30000}
+	for i, key := range keys {
+		addr := crypto.PubkeyToAddress(key.PublicKey)
+		copy(genspec.ExtraData[extraVanity+i*common.AddressLength:], addr[:])
+	}
+	engine := New(genspec.Config.Poi, rawdb.NewMemoryDatabase())
+
+	_, blocks, _ := core.GenerateChainWithGenesis(genspec, engine, 3, func(i int, block *core.BlockGen) {
+		block.SetDifficulty(diffInTurn)
+	})
+	for j, block := range blocks {
+		header := block.Header()
+		if j > 0 {
+			header.ParentHash = blocks[j-1].Hash()
+		}
+		header.Extra = make([]byte, extraVanity+extraSeal)
+
+		sig, _ := crypto.Sign(SealHash(header).Bytes(), keys[header.Number.Uint64()%uint64(len(keys))])
+		copy(header.Extra[len(header.Extra)-extraSeal:], sig)
+		blocks[j] = block.WithSeal(header)
+	}
+	chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, genspec, nil, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create test chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	attest := func(key *ecdsa.PrivateKey, number uint64) *Attestation {
+		signer := New(genspec.Config.Poi, rawdb.NewMemoryDatabase())
+		signer.Authorize(crypto.PubkeyToAddress(key.PublicKey), func(account accounts.Account, mimeType string, data []byte) ([]byte, error) {
+			return crypto.Sign(crypto.Keccak256(data), key)
+		})
+		att, err := signer.Attest(chain, chain.GetHeaderByNumber(number))
+		if err != nil {
+			t.Fatalf("failed to attest to block #%d: %v", number, err)
+		}
+		if _, err := signer.Attest(chain, chain.GetHeaderByNumber(number)); err != errAlreadyAttested {
+			t.Fatalf("repeated attestation error mismatch: have %v, want %v", err, errAlreadyAttested)
+		}
+		return att
+	}
+	finality := NewFinality(engine, chain)
+
+	// Outsiders may not attest
+	outsider, _ := crypto.GenerateKey()
+	sig, _ := crypto.Sign(crypto.Keccak256(AttestationRLP(2, blocks[1].Hash())), outsider)
+	if _, err := finality.Add(&Attestation{Number: 2, Hash: blocks[1].Hash(), Signature: sig}); err != errUnauthorizedSigner {
+		t.Fatalf("outsider attestation error mismatch: have %v, want %v", err, errUnauthorizedSigner)
+	}
+	// Two out of three signers make the block safe, but not final
+	for _, key := range keys[:2] {
+		if added, err := finality.Add(attest(key, 2)); !added || err != nil {
+			t.Fatalf("failed to add attestation: added %v, err %v", added, err)
+		}
+	}
+	if added, _ := finality.Add(attest(keys[0], 3)); !added {
+		t.Fatalf("failed to add attestation for the head")
+	}
+	if safe := chain.CurrentSafeBlock(); safe == nil || safe.Hash() != blocks[1].Hash() {
+		t.Errorf("safe block mismatch: have %v, want #2", safe)
+	}
+	if final := chain.CurrentFinalBlock(); final != nil {
+		t.Errorf("block finalized early: #%d", final.Number)
+	}
+	// The third signer finalizes the block, older attestations become stale
+	if added, err := finality.Add(attest(keys[2], 2)); !added || err != nil {
+		t.Fatalf("failed to add attestation: added %v, err %v", added, err)
+	}
+	if final := chain.CurrentFinalBlock(); final == nil || final.Hash() != blocks[1].Hash() {
+		t.Errorf("finalized block mismatch: have %v, want #2", final)
+	}
+	if _, err := finality.Add(attest(keys[1], 1)); err != errStaleAttestation {
+		t.Errorf("stale attestation error mismatch: have %v, want %v", err, errStaleAttestation)
+	}
+	if atts := finality.Attestations(blocks[2].Hash()); len(atts) != 1 {
+		t.Errorf("head attestation count mismatch: have %d, want 1", len(atts))
+	}
+}
+
+// newAttestationChain creates a single signer chain with two competing branches
+// forking after the first block, returning the signer key and both branches. The
+// fork is two blocks longer, with only its first two blocks imported, so that
+// importing the next two reorgs the chain onto it.
+func newAttestationChain(t *testing.T) (*core.BlockChain, *params.PoiConfig, *ecdsa.PrivateKey, []*types.Block, []*types.Block) {
+	key, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	genspec := &core.Genesis{
+		Config:    new(params.ChainConfig),
+		ExtraData: make([]byte, extraVanity+common.AddressLength+extraSeal),
+		BaseFee:   big.NewInt(params.InitialBaseFee),
+	}
+	*genspec.Config = *params.TestChainConfig
+	genspec.Config.Poi = &params.PoiConfig{Period: 1, Epoch: 30000}
+	copy(genspec.ExtraData[extraVanity:], addr[:])
+
+	engine := New(genspec.Config.Poi, rawdb.NewMemoryDatabase())
+
+	// Seal both branches, the fork differing from block 2 onwards in its vanity
+	branch := func(fork byte, n int) []*types.Block {
+		_, blocks, _ := core.GenerateChainWithGenesis(genspec, engine, n, func(i int, block *core.BlockGen) {
+			block.SetDifficulty(diffInTurn)
+		})
+		for j, block := range blocks {
+			header := block.Header()
+			header.Extra = make([]byte, extraVanity+extraSeal)
+			if j > 0 {
+				header.ParentHash = blocks[j-1].Hash()
+				header.Extra[0] = fork
+			}
+			sig, _ := crypto.Sign(SealHash(header).Bytes(), key)
+			copy(header.Extra[len(header.Extra)-extraSeal:], sig)
+			blocks[j] = block.WithSeal(header)
+		}
+		return blocks
+	}
+	canonical, fork := branch(0, 3), branch(1, 5)
+	if canonical[0].Hash() != fork[0].Hash() || canonical[1].Hash() == fork[1].Hash() {
+		t.Fatalf("branches don't fork after block #1")
+	}
+	chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, genspec, nil, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create test chain: %v", err)
+	}
+	if _, err := chain.InsertChain(canonical); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	if _, err := chain.InsertChain(fork[:2]); err != nil {
+		t.Fatalf("failed to insert fork: %v", err)
+	}
+	return chain, genspec.Config.Poi, key, canonical, fork
+}
+
+// newAttester creates an engine attesting with the given key on top of db.
+func newAttester(config *params.PoiConfig, db ethdb.Database, key *ecdsa.PrivateKey) *Poi {
+	engine := New(config, db)
+	engine.Authorize(crypto.PubkeyToAddress(key.PublicKey), func(account accounts.Account, mimeType string, data []byte) ([]byte, error) {
+		return crypto.Sign(crypto.Keccak256(data), key)
+	})
+	return engine
+}
+
+// Tests that the last attested block survives a restart, so a restarted signer
+// doesn't attest to the same or a lower height again.
+func TestAttestationRestart(t *testing.T) {
+	chain, config, key, blocks, _ := newAttestationChain(t)
+	defer chain.Stop()
+
+	db := rawdb.NewMemoryDatabase()
+	if _, err := newAttester(config, db, key).Attest(chain, blocks[1].Header()); err != nil {
+		t.Fatalf("failed to attest to block #2: %v", err)
+	}
+	restarted := newAttester(config, db, key)
+	for _, block := range blocks[:2] {
+		if _, err := restarted.Attest(chain, block.Header()); err != errAlreadyAttested {
+			t.Errorf("block #%d: attestation error mismatch: have %v, want %v", block.NumberU64(), err, errAlreadyAttested)
+		}
+	}
+	if _, err := restarted.Attest(chain, blocks[2].Header()); err != nil {
+		t.Errorf("failed to attest to block #3 after restart: %v", err)
+	}
+}
+
+// Tests that a signer moves on to the new canonical branch after a reorg, but
+// never attests twice at the same height nor to blocks conflicting with the
+// finalized block.
+func TestAttestationReorg(t *testing.T) {
+	chain, config, key, canonical, fork := newAttestationChain(t)
+	defer chain.Stop()
+
+	attester := newAttester(config, rawdb.NewMemoryDatabase(), key)
+	if _, err := attester.Attest(chain, canonical[1].Header()); err != nil {
+		t.Fatalf("failed to attest to block #2: %v", err)
+	}
+	if _, err := attester.Attest(chain, fork[1].Header()); err != errAlreadyAttested {
+		t.Errorf("fork attestation at same height error mismatch: have %v, want %v", err, errAlreadyAttested)
+	}
+	// Reorg onto the fork, attesting must resume on the new branch
+	if _, err := chain.InsertChain(fork[2:4]); err != nil {
+		t.Fatalf("failed to reorg onto fork: %v", err)
+	}
+	if head := chain.CurrentBlock(); head.Hash() != fork[3].Hash() {
+		t.Fatalf("head mismatch after reorg: have #%d [%x], want #%d [%x]", head.Number, head.Hash(), fork[3].Number(), fork[3].Hash())
+	}
+	if _, err := attester.Attest(chain, fork[2].Header()); err != nil {
+		t.Fatalf("failed to attest to fork block #3 after reorg: %v", err)
+	}
+	// Once the fork is finalized, blocks conflicting with it are refused
+	chain.SetFinalized(fork[2].Header())
+	if _, err := attester.Attest(chain, canonical[2].Header()); err != errAlreadyAttested {
+		t.Errorf("old branch attestation error mismatch: have %v, want %v", err, errAlreadyAttested)
+	}
+	other := newAttester(config, rawdb.NewMemoryDatabase(), key)
+	if _, err := other.Attest(chain, canonical[2].Header()); err != errConflictingAttestation {
+		t.Errorf("conflicting attestation error mismatch: have %v, want %v", err, errConflictingAttestation)
+	}
+	if _, err := attester.Attest(chain, fork[3].Header()); err != nil {
+		t.Errorf("failed to attest to finalized descendant #4: %v", err)
+	}
+}
+
+// Tests that attestations for side chain blocks count once a reorg makes them
+// canonical, and that attestations for blocks not yet imported are kept until
+// the blocks arrive.
+func TestFinalityReorg(t *testing.T) {
+	chain, config, key, _, fork := newAttestationChain(t)
+	defer chain.Stop()
+
+	finality := NewFinality(chain.Engine().(*Poi), chain)
+	attester := newAttester(config, rawdb.NewMemoryDatabase(), key)
+
+	// Attest to a side chain block, it mustn't be finalized while on the side
+	att, err := attester.Attest(chain, fork[1].Header())
+	if err != nil {
+		t.Fatalf("failed to attest to fork block #2: %v", err)
+	}
+	if added, err := finality.Add(att); !added || err != nil {
+		t.Fatalf("failed to add attestation: added %v, err %v", added, err)
+	}
+	if final := chain.CurrentFinalBlock(); final != nil {
+		t.Fatalf("side chain block finalized: #%d", final.Number)
+	}
+	// Reorg onto the fork, the attestation must be counted
+	if _, err := chain.InsertChain(fork[2:4]); err != nil {
+		t.Fatalf("failed to reorg onto fork: %v", err)
+	}
+	if verified := finality.Update(); len(verified) != 0 {
+		t.Errorf("verified attestation count mismatch: have %d, want 0", len(verified))
+	}
+	if final := chain.CurrentFinalBlock(); final == nil || final.Hash() != fork[1].Hash() {
+		t.Fatalf("finalized block mismatch after reorg: have %v, want #2", final)
+	}
+	// Attest to a block not yet imported, it must be kept until it arrives
+	sig, _ := crypto.Sign(crypto.Keccak256(AttestationRLP(5, fork[4].Hash())), key)
+	pending := &Attestation{Number: 5, Hash: fork[4].Hash(), Signature: sig}
+	if added, err := finality.Add(pending); added || err != nil {
+		t.Fatalf("pending attestation mismatch: added %v, err %v", added, err)
+	}
+	if atts := finality.Attestations(fork[4].Hash()); len(atts) != 0 {
+		t.Errorf("unverified attestation count mismatch: have %d, want 0", len(atts))
+	}
+	if _, err := chain.InsertChain(fork[4:]); err != nil {
+		t.Fatalf("failed to import fork block #5: %v", err)
+	}
+	if verified := finality.Update(); len(verified) != 1 || verified[0] != pending {
+		t.Errorf("verified attestations mismatch: have %v, want [%v]", verified, pending)
+	}
+	if final := chain.CurrentFinalBlock(); final == nil || final.Hash() != fork[4].Hash() {
+		t.Errorf("finalized block mismatch: have %v, want #5", final)
+	}
+}
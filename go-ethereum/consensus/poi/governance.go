@@ -0,0 +1,255 @@
+package poi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"golang.org/x/exp/slices"
+)
+
+// The governance contract keeps the signer set in its first two storage slots,
+// matching the layout of contracts/src/PoiGovernance.sol:
+//
+//	address[] signers;                 // slot 0
+//	mapping(address => uint64) weights; // slot 1
+const (
+	governanceSignersSlot = 0
+	governanceWeightsSlot = 1
+
+	maxGovernanceSigners = 256 // Maximum number of signers read from the governance contract
+
+	governanceEntryLength = common.AddressLength + 8 // Signer address and big endian weight
+)
+
+var (
+	// errGovernanceVote is returned if a header carries a signer vote while the
+	// signer set is managed by the governance contract.
+	errGovernanceVote = errors.New("signer vote under contract governance")
+
+	// errMissingGovernanceState is returned if the state needed to read the
+	// governance contract isn't available.
+	errMissingGovernanceState = errors.New("missing state to read governance contract")
+)
+
+// storageReader is the part of the state the governance contract is read from.
+type storageReader interface {
+	GetState(addr common.Address, hash common.Hash) common.Hash
+}
+
+// stateReader is implemented by chains that can provide the state of a block,
+// e.g. core.BlockChain, but not a header-only chain.
+type stateReader interface {
+	StateAt(root common.Hash) (*state.StateDB, error)
+}
+
+// governanceArraySlot returns the storage slot of the given index in a dynamic array
+// rooted at the given slot.
+func governanceArraySlot(slot uint64, index int) common.Hash {
+	base := crypto.Keccak256(common.BigToHash(new(big.Int).SetUint64(slot)).Bytes())
+	elem := new(big.Int).Add(new(big.Int).SetBytes(base), big.NewInt(int64(index)))
+	return common.BigToHash(elem)
+}
+
+// governanceMappingSlot returns the storage slot of the given key in a mapping
+// rooted at the given slot.
+func governanceMappingSlot(slot uint64, key common.Address) common.Hash {
+	return crypto.Keccak256Hash(common.BytesToHash(key[:]).Bytes(), common.BigToHash(new(big.Int).SetUint64(slot)).Bytes())
+}
+
+// GovernanceStorage returns the storage of a governance contract holding the
+// given signers and weights, to be placed into the genesis allocation of the
+// contract.
+func GovernanceStorage(signers []common.Address, weights []uint64) map[common.Hash]common.Hash {
+	storage := map[common.Hash]common.Hash{
+		common.BigToHash(big.NewInt(governanceSignersSlot)): common.BigToHash(big.NewInt(int64(len(signers)))),
+	}
+	for i, signer := range signers {
+		storage[governanceArraySlot(governanceSignersSlot, i)] = common.BytesToHash(signer[:])
+		if i < len(weights) && weights[i] != 0 {
+			storage[governanceMappingSlot(governanceWeightsSlot, signer)] = common.BigToHash(new(big.Int).SetUint64(weights[i]))
+		}
+	}
+	return storage
+}
+
+// readGovernance reads the signer set and weights from the governance contract,
+// sorted by signer address. Zero and duplicate signers are skipped, weights are
+// capped to fit the signer performance.
+func readGovernance(db storageReader, contract common.Address) ([]common.Address, []uint64) {
+	length := db.GetState(contract, common.BigToHash(big.NewInt(governanceSignersSlot))).Big()
+	if !length.IsUint64() || length.Uint64() > maxGovernanceSigners {
+		length.SetUint64(maxGovernanceSigners)
+	}
+	var (
+		signers = make([]common.Address, 0, length.Uint64())
+		seen    = make(map[common.Address]struct{})
+	)
+	for i := 0; i < int(length.Uint64()); i++ {
+		signer := common.BytesToAddress(db.GetState(contract, governanceArraySlot(governanceSignersSlot, i)).Bytes())
+		if _, ok := seen[signer]; ok || signer == (common.Address{}) {
+			continue
+		}
+		seen[signer] = struct{}{}
+		signers = append(signers, signer)
+	}
+	slices.SortFunc(signers, func(a, b common.Address) int {
+		return bytes.Compare(a[:], b[:])
+	})
+	weights := make([]uint64, len(signers))
+	for i, signer := range signers {
+		weight := db.GetState(contract, governanceMappingSlot(governanceWeightsSlot, signer)).Big()
+		if !weight.IsUint64() || weight.Uint64() > math.MaxInt64 {
+			weights[i] = math.MaxInt64
+		} else {
+			weights[i] = weight.Uint64()
+		}
+	}
+	return signers, weights
+}
+
+// encodeGovernanceCheckpoint packs a signer set and its weights into the format
+// carried in the extra-data of checkpoint blocks under contract governance.
+func encodeGovernanceCheckpoint(signers []common.Address, weights []uint64) []byte {
+	blob := make([]byte, len(signers)*governanceEntryLength)
+	for i, signer := range signers {
+		entry := blob[i*governanceEntryLength:]
+		copy(entry, signer[:])
+		binary.BigEndian.PutUint64(entry[common.AddressLength:], weights[i])
+	}
+	return blob
+}
+
+// checkpointSigners extracts the signer set (and weights under contract
+// governance) from the extra-data of a checkpoint header.
+func checkpointSigners(config *params.PoiConfig, header *types.Header) ([]common.Address, []uint64, error) {
+	if len(header.Extra) < extraVanity+extraSeal {
+		return nil, nil, errMissingSignature
+	}
+	blob := header.Extra[extraVanity : len(header.Extra)-extraSeal]
+	if config.Governance == nil {
+		if len(blob)%common.AddressLength != 0 {
+			return nil, nil, errInvalidCheckpointSigners
+		}
+		signers := make([]common.Address, len(blob)/common.AddressLength)
+		for i := range signers {
+			copy(signers[i][:], blob[i*common.AddressLength:])
+		}
+		return signers, nil, nil
+	}
+	if len(blob)%governanceEntryLength != 0 || len(blob)/governanceEntryLength > maxGovernanceSigners {
+		return nil, nil, errInvalidCheckpointSigners
+	}
+	var (
+		signers = make([]common.Address, len(blob)/governanceEntryLength)
+		weights = make([]uint64, len(signers))
+	)
+	for i := range signers {
+		entry := blob[i*governanceEntryLength:]
+		copy(signers[i][:], entry)
+		weights[i] = binary.BigEndian.Uint64(entry[common.AddressLength:])
+
+		if weights[i] > math.MaxInt64 {
+			return nil, nil, errInvalidCheckpointSigners
+		}
+		if i > 0 && bytes.Compare(signers[i-1][:], signers[i][:]) >= 0 {
+			return nil, nil, errInvalidCheckpointSigners
+		}
+	}
+	return signers, weights, nil
+}
+
+// governanceCheckpoint assembles the checkpoint signer list on top of the given
+// parent from the governance contract. If the contract holds no signers, the
+// current set is carried over to keep the chain alive.
+func (c *Poi) governanceCheckpoint(chain consensus.ChainHeaderReader, parent *types.Header, snap *Snapshot) ([]byte, error) {
+	reader, ok := chain.(stateReader)
+	if !ok {
+		return nil, errMissingGovernanceState
+	}
+	statedb, err := reader.StateAt(parent.Root)
+	if err != nil {
+		return nil, errMissingGovernanceState
+	}
+	signers, weights := readGovernance(statedb, *c.config.Governance)
+	if len(signers) == 0 {
+		signers = snap.signers()
+		weights = make([]uint64, len(signers))
+		for i, signer := range signers {
+			if performance := snap.Performance[signer]; performance > 0 {
+				weights[i] = uint64(performance)
+			}
+		}
+	}
+	return encodeGovernanceCheckpoint(signers, weights), nil
+}
+
+// verifyGovernance checks the signer list of a checkpoint header against the
+// governance contract in the state of its parent.
+func (c *Poi) verifyGovernance(chain consensus.ChainHeaderReader, header *types.Header, parent *types.Header, snap *Snapshot) error {
+	want, err := c.governanceCheckpoint(chain, parent, snap)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(header.Extra[extraVanity:len(header.Extra)-extraSeal], want) {
+		return errMismatchingCheckpointSigners
+	}
+	return nil
+}
+
+// VerifyHeaderState implements consensus.StateVerifier, checking the signer list
+// of checkpoint blocks against the governance contract once the state of the
+// parent block is available. Header verification can't always do it, as headers
+// are verified ahead of their parents being processed.
+func (c *Poi) VerifyHeaderState(chain consensus.ChainHeaderReader, header *types.Header) error {
+	number := header.Number.Uint64()
+	if c.config.Governance == nil || number == 0 || number%c.config.Epoch != 0 {
+		return nil
+	}
+	parent := chain.GetHeader(header.ParentHash, number-1)
+	if parent == nil {
+		return consensus.ErrUnknownAncestor
+	}
+	snap, err := c.snapshot(chain, number-1, header.ParentHash, nil)
+	if err != nil {
+		return err
+	}
+	return c.verifyGovernance(chain, header, parent, snap)
+}
+
+// applyGovernance replaces the signer set and performances of the snapshot with
+// the ones carried by a checkpoint header under contract governance.
+func (s *Snapshot) applyGovernance(signers []common.Address, weights []uint64, number uint64) {
+	keep := make(map[common.Address]struct{}, len(signers))
+	for _, signer := range signers {
+		keep[signer] = struct{}{}
+	}
+	for signer := range s.Signers {
+		if _, ok := keep[signer]; !ok {
+			s.deauthorize(signer, number)
+		}
+	}
+	for i, signer := range signers {
+		if _, ok := s.Signers[signer]; !ok {
+			s.Signers[signer] = struct{}{}
+			s.Health[signer] = Healthy
+		}
+		s.Performance[signer] = int64(weights[i])
+	}
+	// The signer list may have shrunk by more than one, drop all the recents
+	// that fell out of the window
+	limit := uint64(len(s.Signers)/2 + 1)
+	for seen := range s.Recents {
+		if seen+limit <= number {
+			delete(s.Recents, seen)
+		}
+	}
+}
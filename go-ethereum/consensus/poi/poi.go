@@ -27,6 +27,7 @@ import (
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/ethereum/go-ethereum/trie"
 	"golang.org/x/crypto/sha3"
+	"golang.org/x/exp/slices"
 )
 
 const (
@@ -51,6 +52,7 @@ var (
 
 	diffInTurn = big.NewInt(2) // Block difficulty for in-turn signatures
 	diffNoTurn = big.NewInt(1) // Block difficulty for out-of-turn signatures
+	diffBackup = big.NewInt(3) // Block difficulty for backup signatures (outweighs a late in-turn block)
 )
 
 // Various error messages to mark blocks invalid. These should be private to
@@ -82,10 +84,6 @@ var (
 	// to contain a 65 byte secp256k1 signature.
 	errMissingSignature = errors.New("extra-data 65 byte signature suffix missing")
 
-	// errExtraSigners is returned if non-checkpoint block contain signer data in
-	// their extra-data fields.
-	errExtraSigners = errors.New("non-checkpoint block contains extra signer list")
-
 	// errInvalidCheckpointSigners is returned if a checkpoint block contains an
 	// invalid list of signers (i.e. non divisible by 20 bytes).
 	errInvalidCheckpointSigners = errors.New("invalid signer list on checkpoint block")
@@ -94,8 +92,9 @@ var (
 	// list of signers different than the one the local node calculated.
 	errMismatchingCheckpointSigners = errors.New("mismatching signer list on checkpoint block")
 
-	// errInvalidMixDigest is returned if a block's mix digest is non-zero.
-	errInvalidMixDigest = errors.New("non-zero mix digest")
+	// errInvalidCheckpointStatusVote is returned if a checkpoint/epoch transition
+	// block carries a signer status vote in its mix digest.
+	errInvalidCheckpointStatusVote = errors.New("status vote in checkpoint block")
 
 	// errInvalidUncleHash is returned if a block contains an non-empty uncle list.
 	errInvalidUncleHash = errors.New("non empty uncle hash")
@@ -122,6 +121,14 @@ var (
 	// that already signed a header recently, thus is temporarily not allowed to.
 	errRecentlySigned = errors.New("recently signed")
 
+	// errUnauthorizedBackup is returned in backup mode if a header is signed out
+	// of turn by a signer other than the designated backup signer.
+	errUnauthorizedBackup = errors.New("unauthorized backup signer")
+
+	// errBackupTooEarly is returned if a backup header is sealed before the in-turn
+	// signer missed its slot by the backup timeout.
+	errBackupTooEarly = errors.New("backup signer sealed too early")
+
 	// errInvalidPerformance is returned if a performance value is negative.
 	errInvalidPerformance = errors.New("invalid performance value")
 )
@@ -163,15 +170,23 @@ type Poi struct {
 	recents    *lru.Cache[common.Hash, *Snapshot] // Snapshots for recent block to speed up reorgs
 	signatures *sigLRU                            // Signatures of recent blocks to speed up mining
 
-	proposals map[common.Address]bool // Current list of proposals we are pushing
+	proposals       map[common.Address]bool        // Current list of proposals we are pushing
+	statusProposals map[statusKey]uint64           // Current list of signer status changes we are pushing
+	deviceProposals map[common.Address]*deviceVote // Current list of device registry changes we are pushing
+	evidence        map[common.Hash]*Evidence      // Equivocation evidences pending inclusion
+	rotations       map[common.Hash]*Rotation      // Signer rotations pending inclusion
 
 	signer common.Address // Ethereum address of the signing key
 	signFn SignerFn       // Signer function to authorize hashes with
 	lock   sync.RWMutex   // Protects the signer and proposals fields
 
-	// Health monitoring
-	failures     map[common.Address]int       // Track consecutive failures per signer
-	failuresLock sync.RWMutex                // Protects the failures map
+	telemetryFn TelemetryFn // Statistics of the local node to seal into blocks
+
+	attestLock sync.Mutex // Serializes attestations so the last attested block is respected
+
+	// Transition from another engine, see NewTransition
+	fork      uint64      // First block sealed by PoI
+	carryOver carryOverFn // Signer set of the last block before the fork
 
 	// The fields below are for testing only
 	fakeDiff bool // Skip difficulty verifications
@@ -190,12 +205,15 @@ func New(config *params.PoiConfig, db ethdb.Database) *Poi {
 	signatures := lru.NewCache[common.Hash, common.Address](inmemorySignatures)
 
 	return &Poi{
-		config:     &conf,
-		db:         db,
-		recents:    recents,
-		signatures: signatures,
-		proposals:  make(map[common.Address]bool),
-		failures:   make(map[common.Address]int),
+		config:          &conf,
+		db:              db,
+		recents:         recents,
+		signatures:      signatures,
+		proposals:       make(map[common.Address]bool),
+		statusProposals: make(map[statusKey]uint64),
+		deviceProposals: make(map[common.Address]*deviceVote),
+		evidence:        make(map[common.Hash]*Evidence),
+		rotations:       make(map[common.Hash]*Rotation),
 	}
 }
 
@@ -257,6 +275,10 @@ func (c *Poi) verifyHeader(chain consensus.ChainHeaderReader, header *types.Head
 	if checkpoint && !bytes.Equal(header.Nonce[:], nonceDropVote) {
 		return errInvalidCheckpointVote
 	}
+	// Signer votes are disabled if the governance contract manages the signers
+	if c.config.Governance != nil && (header.Coinbase != (common.Address{}) || !bytes.Equal(header.Nonce[:], nonceDropVote)) {
+		return errGovernanceVote
+	}
 	// Check that the extra-data contains both the vanity and signature
 	if len(header.Extra) < extraVanity {
 		return errMissingVanity
@@ -264,17 +286,49 @@ func (c *Poi) verifyHeader(chain consensus.ChainHeaderReader, header *types.Head
 	if len(header.Extra) < extraVanity+extraSeal {
 		return errMissingSignature
 	}
-	// Ensure that the extra-data contains a signer list on checkpoint, but none otherwise
-	signersBytes := len(header.Extra) - extraVanity - extraSeal
-	if !checkpoint && signersBytes != 0 {
-		return errExtraSigners
+	// Ensure that the extra-data contains a signer list on checkpoint, but at most
+	// a well formed header extension otherwise
+	if !checkpoint {
+		ext, err := decodeExtension(header)
+		if err != nil {
+			return err
+		}
+		// Ensure that any equivocation evidence is sealed by a single signer and
+		// that any signer rotation is signed by the key handing over
+		if ext != nil {
+			for _, evidence := range ext.Evidence {
+				if evidence.Number() >= number {
+					return errInvalidEvidence
+				}
+				if _, err := evidence.offender(c.signatures); err != nil {
+					return err
+				}
+			}
+			if len(ext.Rotations) > 0 && c.config.Governance != nil {
+				return errGovernanceRotation
+			}
+			for _, rotation := range ext.Rotations {
+				if rotation.Number >= number {
+					return errInvalidRotation
+				}
+				if _, err := rotation.previous(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if checkpoint {
+		if _, _, err := checkpointSigners(c.config, header); err != nil {
+			return err
+		}
 	}
-	if checkpoint && signersBytes%common.AddressLength != 0 {
-		return errInvalidCheckpointSigners
+	// Ensure that the mix digest is either empty or carries a well formed status
+	// vote, zeroes enforced on checkpoints
+	if _, _, _, err := decodeStatusVote(header.MixDigest); err != nil {
+		return err
 	}
-	// Ensure that the mix digest is zero as we don't have fork protection currently
-	if header.MixDigest != (common.Hash{}) {
-		return errInvalidMixDigest
+	if checkpoint && header.MixDigest != (common.Hash{}) {
+		return errInvalidCheckpointStatusVote
 	}
 	// Ensure that the block doesn't contain any uncles which are meaningless in PoA
 	if header.UncleHash != uncleHash {
@@ -282,7 +336,11 @@ func (c *Poi) verifyHeader(chain consensus.ChainHeaderReader, header *types.Head
 	}
 	// Ensure that the block's difficulty is meaningful (may not be correct at this point)
 	if number > 0 {
-		if header.Difficulty == nil || (header.Difficulty.Cmp(diffInTurn) != 0 && header.Difficulty.Cmp(diffNoTurn) != 0) {
+		if header.Difficulty == nil || (header.Difficulty.Cmp(diffInTurn) != 0 && header.Difficulty.Cmp(diffNoTurn) != 0 && header.Difficulty.Cmp(diffBackup) != 0) {
+			return errInvalidDifficulty
+		}
+		// Backup difficulty is only meaningful if the backup process is enabled
+		if c.config.At(number).BackupTimeout == 0 && header.Difficulty.Cmp(diffBackup) == 0 {
 			return errInvalidDifficulty
 		}
 	}
@@ -333,7 +391,7 @@ func (c *Poi) verifyCascadingFields(chain consensus.ChainHeaderReader, header *t
 	if parent == nil || parent.Number.Uint64() != number-1 || parent.Hash() != header.ParentHash {
 		return consensus.ErrUnknownAncestor
 	}
-	if parent.Time+c.config.Period > header.Time {
+	if parent.Time+c.config.At(number).Period > header.Time {
 		return errInvalidTimestamp
 	}
 	// Verify that the gasUsed is <= gasLimit
@@ -357,8 +415,14 @@ func (c *Poi) verifyCascadingFields(chain consensus.ChainHeaderReader, header *t
 	if err != nil {
 		return err
 	}
-	// If the block is a checkpoint block, verify the signer list
-	if number%c.config.Epoch == 0 {
+	// If the block is a checkpoint block, verify the signer list. Under contract
+	// governance it can only be checked if the parent state is already available,
+	// otherwise it's checked by VerifyHeaderState before the block is processed.
+	if number%c.config.Epoch == 0 && c.config.Governance != nil {
+		if err := c.verifyGovernance(chain, header, parent, snap); err != nil && !errors.Is(err, errMissingGovernanceState) {
+			return err
+		}
+	} else if number%c.config.Epoch == 0 {
 		signers := make([]byte, len(snap.Signers)*common.AddressLength)
 		for i, signer := range snap.signers() {
 			copy(signers[i*common.AddressLength:], signer[:])
@@ -369,7 +433,7 @@ func (c *Poi) verifyCascadingFields(chain consensus.ChainHeaderReader, header *t
 		}
 	}
 	// All basic checks passed, verify the seal and return
-	return c.verifySeal(snap, header, parents)
+	return c.verifySeal(snap, header, parent)
 }
 
 // snapshot retrieves the authorization snapshot at a given point in time.
@@ -385,11 +449,38 @@ func (c *Poi) snapshot(chain consensus.ChainHeaderReader, number uint64, hash co
 			snap = s
 			break
 		}
+		// If PoI took over from another engine, start from the signer set of
+		// the last block sealed before the fork
+		if c.carryOver != nil && number+1 <= c.fork {
+			if number+1 < c.fork {
+				return nil, errBeforeFork
+			}
+			signers, recents, err := c.carryOver(chain, number, hash, parents)
+			if err != nil {
+				return nil, err
+			}
+			snap = newSnapshot(c.config, c.signatures, number, hash, signers)
+			for block, signer := range recents {
+				snap.Recents[block] = signer
+			}
+			snap.updateRotation(number)
+			log.Info("Carried over signers to poi", "number", number, "hash", hash, "signers", len(signers))
+			break
+		}
 		// If an on-disk checkpoint snapshot can be found, use that
 		if number%checkpointInterval == 0 {
-			if s, err := loadSnapshot(c.config, c.signatures, c.db, hash); err == nil {
+			if s, legacy, err := loadSnapshot(c.config, c.signatures, c.db, hash); err == nil {
 				log.Trace("Loaded voting snapshot from disk", "number", number, "hash", hash)
 				snap = s
+
+				// Rewrite snapshots still in the legacy encoding in the binary one
+				if legacy {
+					if err := snap.store(c.db); err != nil {
+						log.Warn("Failed to migrate legacy voting snapshot", "number", number, "hash", hash, "err", err)
+					} else {
+						log.Debug("Migrated legacy voting snapshot", "number", number, "hash", hash)
+					}
+				}
 				break
 			}
 		}
@@ -402,11 +493,15 @@ func (c *Poi) snapshot(chain consensus.ChainHeaderReader, number uint64, hash co
 			if checkpoint != nil {
 				hash := checkpoint.Hash()
 
-				signers := make([]common.Address, (len(checkpoint.Extra)-extraVanity-extraSeal)/common.AddressLength)
-				for i := 0; i < len(signers); i++ {
-					copy(signers[i][:], checkpoint.Extra[extraVanity+i*common.AddressLength:])
+				signers, weights, err := checkpointSigners(c.config, checkpoint)
+				if err != nil {
+					return nil, err
 				}
 				snap = newSnapshot(c.config, c.signatures, number, hash, signers)
+				for i, weight := range weights {
+					snap.Performance[signers[i]] = int64(weight)
+				}
+				snap.updateRotation(number)
 				if err := snap.store(c.db); err != nil {
 					return nil, err
 				}
@@ -463,10 +558,9 @@ func (c *Poi) VerifyUncles(chain consensus.ChainReader, block *types.Block) erro
 }
 
 // verifySeal checks whether the signature contained in the header satisfies the
-// consensus protocol requirements. The method accepts an optional list of parent
-// headers that aren't yet part of the local blockchain to generate the snapshots
-// from.
-func (c *Poi) verifySeal(snap *Snapshot, header *types.Header, parents []*types.Header) error {
+// consensus protocol requirements. The method accepts the parent header to check
+// the backup sealing window against.
+func (c *Poi) verifySeal(snap *Snapshot, header *types.Header, parent *types.Header) error {
 	// Verifying the genesis block is not supported
 	number := header.Number.Uint64()
 	if number == 0 {
@@ -490,6 +584,9 @@ func (c *Poi) verifySeal(snap *Snapshot, header *types.Header, parents []*types.
 	}
 	// Ensure that the difficulty corresponds to the turn-ness of the signer
 	if !c.fakeDiff {
+		if snap.backupMode(number) {
+			return c.verifyBackup(snap, header, parent, signer)
+		}
 		inturn := snap.inturn(header.Number.Uint64(), signer)
 		if inturn && header.Difficulty.Cmp(diffInTurn) != 0 {
 			return errWrongDifficulty
@@ -501,12 +598,46 @@ func (c *Poi) verifySeal(snap *Snapshot, header *types.Header, parents []*types.
 	return nil
 }
 
+// verifyBackup checks the turn-ness of the signer in backup mode: the in-turn
+// signer may always seal, the designated backup signer only once the in-turn one
+// missed its slot by the backup timeout, and nobody else unless there's no active
+// signer able to seal in-turn, in which case out-of-turn sealing is allowed.
+// Recovering signers may also seal out-of-turn, otherwise they could never seal
+// the blocks needed to recover.
+func (c *Poi) verifyBackup(snap *Snapshot, header *types.Header, parent *types.Header, signer common.Address) error {
+	number := header.Number.Uint64()
+
+	inturn, ok := snap.inturnSigner(number)
+	switch {
+	case !ok || snap.recovering(number, signer):
+		if header.Difficulty.Cmp(diffNoTurn) != 0 {
+			return errWrongDifficulty
+		}
+	case signer == inturn:
+		if header.Difficulty.Cmp(diffInTurn) != 0 {
+			return errWrongDifficulty
+		}
+	default:
+		if backup, ok := snap.GetBackupSigner(number, inturn); !ok || backup != signer {
+			return errUnauthorizedBackup
+		}
+		if header.Difficulty.Cmp(diffBackup) != 0 {
+			return errWrongDifficulty
+		}
+		if config := c.config.At(number); parent.Time+config.Period+config.BackupTimeout > header.Time {
+			return errBackupTooEarly
+		}
+	}
+	return nil
+}
+
 // Prepare implements consensus.Engine, preparing all the consensus fields of the
 // header for running the transactions on top.
 func (c *Poi) Prepare(chain consensus.ChainHeaderReader, header *types.Header) error {
 	// If the block isn't a checkpoint, cast a random vote (good enough for now)
 	header.Coinbase = common.Address{}
 	header.Nonce = types.BlockNonce{}
+	header.MixDigest = common.Hash{}
 
 	number := header.Number.Uint64()
 	// Assemble the voting snapshot to check which votes make sense
@@ -514,8 +645,8 @@ func (c *Poi) Prepare(chain consensus.ChainHeaderReader, header *types.Header) e
 	if err != nil {
 		return err
 	}
-	c.lock.RLock()
-	if number%c.config.Epoch != 0 {
+	c.lock.Lock()
+	if number%c.config.Epoch != 0 && c.config.Governance == nil {
 		// Gather all the proposals that make sense voting on
 		addresses := make([]common.Address, 0, len(c.proposals))
 		for address, authorize := range c.proposals {
@@ -532,14 +663,101 @@ func (c *Poi) Prepare(chain consensus.ChainHeaderReader, header *types.Header) e
 				copy(header.Nonce[:], nonceDropVote)
 			}
 		}
+		// Gather all the status proposals that make sense voting on. Health
+		// proposals are one-shot, drop them once they passed so that a signer
+		// that recovers later isn't voted unhealthy again right away.
+		keys := make([]statusKey, 0, len(c.statusProposals))
+		values := make(map[statusKey]uint64, len(c.statusProposals))
+		for key, value := range c.statusProposals {
+			if snap.validStatusVote(key.Kind, key.Address, value) {
+				keys, values[key] = append(keys, key), value
+			} else if key.Kind == statusHealth {
+				delete(c.statusProposals, key)
+			}
+		}
+		// Vote unhealthy the signers that missed too many in-turn slots in a row
+		// on the chain being extended, unless explicitly proposed otherwise
+		for _, signer := range snap.failing(number) {
+			key := statusKey{Kind: statusHealth, Address: signer}
+			if _, ok := values[key]; !ok && snap.validStatusVote(key.Kind, signer, uint64(Unhealthy)) {
+				keys, values[key] = append(keys, key), uint64(Unhealthy)
+			}
+		}
+		// If there's pending status proposals, cast a vote on one of them
+		if len(keys) > 0 {
+			key := keys[rand.Intn(len(keys))]
+			header.MixDigest = encodeStatusVote(key.Kind, key.Address, values[key])
+		}
+	}
+	// Gather all the device proposals that make sense voting on
+	var devices []*deviceVote
+	if number%c.config.Epoch != 0 {
+		for address, vote := range c.deviceProposals {
+			if snap.validDeviceVote(address, vote.Metadata, vote.Authorize) {
+				devices = append(devices, vote)
+			}
+		}
+		slices.SortFunc(devices, func(a, b *deviceVote) int {
+			return bytes.Compare(a.Address[:], b.Address[:])
+		})
+		if len(devices) > maxDeviceVotes {
+			devices = devices[:maxDeviceVotes]
+		}
+	}
+	// Gather all the pending evidences that can still be applied, dropping the
+	// ones that already have been or expired
+	var evidence []*Evidence
+	if number%c.config.Epoch != 0 {
+		for hash, ev := range c.evidence {
+			offender, err := ev.offender(c.signatures)
+			if err != nil || !snap.validEvidence(offender, ev.Number(), number) {
+				delete(c.evidence, hash)
+				continue
+			}
+			evidence = append(evidence, ev)
+		}
+		slices.SortFunc(evidence, func(a, b *Evidence) int {
+			if a.Number() != b.Number() {
+				return a.First.Number.Cmp(b.First.Number)
+			}
+			return bytes.Compare(a.Hash().Bytes(), b.Hash().Bytes())
+		})
+		if len(evidence) > maxEvidence {
+			evidence = evidence[:maxEvidence]
+		}
+	}
+	// Gather all the pending signer rotations that can still be applied, dropping
+	// the ones that already have been or expired
+	var rotations []*Rotation
+	if number%c.config.Epoch != 0 && c.config.Governance == nil {
+		for hash, rotation := range c.rotations {
+			previous, err := rotation.previous()
+			if err != nil || !snap.validRotation(previous, rotation, number) {
+				delete(c.rotations, hash)
+				continue
+			}
+			rotations = append(rotations, rotation)
+		}
+		slices.SortFunc(rotations, func(a, b *Rotation) int {
+			return bytes.Compare(a.Signer[:], b.Signer[:])
+		})
+		if len(rotations) > maxRotations {
+			rotations = rotations[:maxRotations]
+		}
 	}
 
+	// Assemble the telemetry of the local node if it's to be sealed
+	var report *telemetry
+	if c.config.Telemetry {
+		report = c.localTelemetry()
+	}
 	// Copy signer protected by mutex to avoid race condition
 	signer := c.signer
-	c.lock.RUnlock()
+	c.lock.Unlock()
 
 	// Set the correct difficulty
 	header.Difficulty = calcDifficulty(snap, signer)
+	backup := header.Difficulty.Cmp(diffBackup) == 0
 
 	// Ensure the extra data has all its components
 	if len(header.Extra) < extraVanity {
@@ -547,45 +765,81 @@ func (c *Poi) Prepare(chain consensus.ChainHeaderReader, header *types.Header) e
 	}
 	header.Extra = header.Extra[:extraVanity]
 
-	if number%c.config.Epoch == 0 {
+	parent := chain.GetHeader(header.ParentHash, number-1)
+	if parent == nil {
+		return consensus.ErrUnknownAncestor
+	}
+	if number%c.config.Epoch == 0 && c.config.Governance != nil {
+		signers, err := c.governanceCheckpoint(chain, parent, snap)
+		if err != nil {
+			return err
+		}
+		header.Extra = append(header.Extra, signers...)
+	} else if number%c.config.Epoch == 0 {
 		for _, signer := range snap.signers() {
 			header.Extra = append(header.Extra, signer[:]...)
 		}
+	} else {
+		ext, err := encodeExtension(&headerExtension{Version: extensionVersion, Devices: devices, Evidence: evidence, Telemetry: report, Rotations: rotations})
+		if err != nil {
+			return err
+		}
+		header.Extra = append(header.Extra, ext...)
 	}
 	header.Extra = append(header.Extra, make([]byte, extraSeal)...)
 
-	// Mix digest is reserved for now, set to empty
-	header.MixDigest = common.Hash{}
-
 	// Ensure the timestamp has the correct delay
-	parent := chain.GetHeader(header.ParentHash, number-1)
-	if parent == nil {
-		return consensus.ErrUnknownAncestor
+	config := c.config.At(number)
+	header.Time = parent.Time + config.Period
+	if backup {
+		// Backup signers may only seal once the in-turn signer missed its slot
+		header.Time += config.BackupTimeout
 	}
-	header.Time = parent.Time + c.config.Period
 	if header.Time < uint64(time.Now().Unix()) {
 		header.Time = uint64(time.Now().Unix())
 	}
 	return nil
 }
 
-// Finalize implements consensus.Engine. There is no post-transaction
-// consensus rules in poi, do nothing here.
+// Finalize implements consensus.Engine, crediting the block rewards of the
+// configured reward policy to the signer of the block.
+//
+// Note, the signer and the parent snapshot the rewards are derived from were
+// already resolved when verifying the header, so failing to do so here can't
+// happen for a verified block. As the method can't fail the block, it credits
+// nothing instead, which makes the block fail the state root check.
 func (c *Poi) Finalize(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, txs []*types.Transaction, uncles []*types.Header, withdrawals []*types.Withdrawal) {
-	// No block rewards in PoA, so the state remains as is
-	
-	// Monitor block production for health tracking
-	go c.monitorBlock(chain, header)
+	// Credit the block rewards to the signer of the block, if any are configured
+	if !c.hasRewards(header) {
+		return
+	}
+	signer, err := ecrecover(header, c.signatures)
+	if err != nil {
+		log.Error("Failed to recover signer for rewards", "number", header.Number, "hash", header.Hash(), "err", err)
+		return
+	}
+	if err := c.accumulateRewards(chain, header, state, signer); err != nil {
+		log.Error("Failed to credit block rewards", "number", header.Number, "hash", header.Hash(), "err", err)
+	}
 }
 
 // FinalizeAndAssemble implements consensus.Engine, ensuring no uncles are set,
-// nor block rewards given, and returns the final block.
+// crediting the block rewards to the local signer, and returns the final block.
 func (c *Poi) FinalizeAndAssemble(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, txs []*types.Transaction, uncles []*types.Header, receipts []*types.Receipt, withdrawals []*types.Withdrawal) (*types.Block, error) {
 	if len(withdrawals) > 0 {
 		return nil, errors.New("poi does not support withdrawals")
 	}
-	// Finalize block
-	c.Finalize(chain, header, state, txs, uncles, nil)
+	// Finalize block, crediting the rewards to the local signer as the header
+	// isn't sealed yet
+	if c.hasRewards(header) {
+		c.lock.RLock()
+		signer := c.signer
+		c.lock.RUnlock()
+
+		if err := c.accumulateRewards(chain, header, state, signer); err != nil {
+			return nil, err
+		}
+	}
 
 	// Assign the final state root to header.
 	header.Root = state.IntermediateRoot(chain.Config().IsEIP158(header.Number))
@@ -615,7 +869,7 @@ func (c *Poi) Seal(chain consensus.ChainHeaderReader, block *types.Block, result
 		return errUnknownBlock
 	}
 	// For 0-period chains, refuse to seal empty blocks (no reward but would spin sealing)
-	if c.config.Period == 0 && len(block.Transactions()) == 0 {
+	if c.config.At(number).Period == 0 && len(block.Transactions()) == 0 {
 		return errors.New("sealing paused while waiting for transactions")
 	}
 	// Don't hold the signer fields for the entire sealing procedure
@@ -632,12 +886,14 @@ func (c *Poi) Seal(chain consensus.ChainHeaderReader, block *types.Block, result
 		return errUnauthorizedSigner
 	}
 	// If we're amongst the recent signers, wait for the next block
-	for seen, recent := range snap.Recents {
-		if recent == signer {
-			// Signer is among recents, only wait if the current block doesn't shift it out
-			if limit := uint64(len(snap.Signers)/2 + 1); number < limit || seen > number-limit {
-				return errors.New("signed recently, must wait for others")
-			}
+	if snap.recentlySigned(number, signer) {
+		return errors.New("signed recently, must wait for others")
+	}
+	// In backup mode only the in-turn, the designated backup and recovering signers
+	// may seal
+	if snap.backupMode(number) && header.Difficulty.Cmp(diffNoTurn) == 0 && !snap.recovering(number, signer) {
+		if _, ok := snap.inturnSigner(number); ok {
+			return errors.New("neither in-turn nor backup signer, must wait for others")
 		}
 	}
 	// Sweet, the protocol permits us to sign the block, wait for our time
@@ -676,8 +932,9 @@ func (c *Poi) Seal(chain consensus.ChainHeaderReader, block *types.Block, result
 
 // CalcDifficulty is the difficulty adjustment algorithm. It returns the difficulty
 // that a new block should have:
-// * DIFF_NOTURN(2) if BLOCK_NUMBER % SIGNER_COUNT != SIGNER_INDEX
-// * DIFF_INTURN(1) if BLOCK_NUMBER % SIGNER_COUNT == SIGNER_INDEX
+// * DIFF_INTURN(2) if BLOCK_NUMBER % SIGNER_COUNT == SIGNER_INDEX
+// * DIFF_BACKUP(3) if backup mode is enabled and the signer is the designated backup
+// * DIFF_NOTURN(1) otherwise
 func (c *Poi) CalcDifficulty(chain consensus.ChainHeaderReader, time uint64, parent *types.Header) *big.Int {
 	snap, err := c.snapshot(chain, parent.Number.Uint64(), parent.Hash(), nil)
 	if err != nil {
@@ -690,12 +947,26 @@ func (c *Poi) CalcDifficulty(chain consensus.ChainHeaderReader, time uint64, par
 }
 
 func calcDifficulty(snap *Snapshot, signer common.Address) *big.Int {
-	if snap.inturn(snap.Number+1, signer) {
+	number := snap.Number + 1
+	inturn, ok := snap.inturnSigner(number)
+	if ok && inturn == signer {
 		return new(big.Int).Set(diffInTurn)
 	}
+	if ok && snap.backupMode(number) {
+		if backup, ok := snap.GetBackupSigner(number, inturn); ok && backup == signer {
+			return new(big.Int).Set(diffBackup)
+		}
+	}
 	return new(big.Int).Set(diffNoTurn)
 }
 
+// SealsOnDemand implements consensus.OnDemandSealer, returning whether empty
+// blocks are never sealed because the chain is configured with a zero period.
+// Overrides can't change a zero period, so it holds for the entire chain.
+func (c *Poi) SealsOnDemand() bool {
+	return c.config.Period == 0
+}
+
 // SealHash returns the hash of a block prior to it being sealed.
 func (c *Poi) SealHash(header *types.Header) common.Hash {
 	return SealHash(header)
@@ -774,62 +1045,136 @@ func encodeSigHeader(w io.Writer, header *types.Header) {
 	}
 }
 
-// recordFailure records a block production failure for a signer
-func (c *Poi) recordFailure(signer common.Address) {
-	c.failuresLock.Lock()
-	defer c.failuresLock.Unlock()
-	
-	c.failures[signer]++
-	
-	// Check if signer should be marked unhealthy
-	if c.config.HealthThreshold > 0 && c.failures[signer] >= c.config.HealthThreshold {
-		// Mark signer as unhealthy in all cached snapshots
-		c.recents.Range(func(hash common.Hash, snap *Snapshot) bool {
-			snap.MarkUnhealthy(signer)
-			return true
-		})
-		log.Warn("Signer marked unhealthy due to failures", "address", signer, "failures", c.failures[signer])
+// proposeStatus injects a new signer status proposal that the signer will
+// attempt to push through.
+func (c *Poi) proposeStatus(kind StatusKind, address common.Address, value uint64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.statusProposals[statusKey{Kind: kind, Address: address}] = value
+}
+
+// proposeDevice injects a new device registry proposal that the signer will
+// attempt to push through.
+func (c *Poi) proposeDevice(address common.Address, metadata common.Hash, authorize bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.deviceProposals[address] = &deviceVote{Address: address, Metadata: metadata, Authorize: authorize}
+}
+
+// discardDevice drops a currently running device registry proposal, stopping the
+// signer from casting further votes on it.
+func (c *Poi) discardDevice(address common.Address) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	delete(c.deviceProposals, address)
+}
+
+// submitEvidence verifies an equivocation evidence against the current chain
+// head and queues it up for inclusion in the next sealed blocks.
+func (c *Poi) submitEvidence(chain consensus.ChainHeaderReader, evidence *Evidence) (common.Hash, error) {
+	offender, err := evidence.offender(c.signatures)
+	if err != nil {
+		return common.Hash{}, err
 	}
+	header := chain.CurrentHeader()
+	snap, err := c.snapshot(chain, header.Number.Uint64(), header.Hash(), nil)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if !snap.validEvidence(offender, evidence.Number(), header.Number.Uint64()+1) {
+		return common.Hash{}, errStaleEvidence
+	}
+	log.Warn("Queued signer equivocation evidence", "signer", offender, "number", evidence.Number())
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	hash := evidence.Hash()
+	c.evidence[hash] = evidence
+	return hash, nil
 }
 
-// resetFailures resets the failure count for a signer
-func (c *Poi) resetFailures(signer common.Address) {
-	c.failuresLock.Lock()
-	defer c.failuresLock.Unlock()
-	
-	delete(c.failures, signer)
+// rotate signs a handover of the local signer's identity to a new key and queues
+// it up for inclusion in the blocks sealed by this node.
+func (c *Poi) rotate(chain consensus.ChainHeaderReader, signer common.Address) (*Rotation, error) {
+	// Don't hold the signer fields for the entire signing procedure
+	c.lock.RLock()
+	previous, signFn := c.signer, c.signFn
+	c.lock.RUnlock()
+
+	if signFn == nil {
+		return nil, errUnauthorizedSigner
+	}
+	header := chain.CurrentHeader()
+	number := header.Number.Uint64()
+
+	sig, err := signFn(accounts.Account{Address: previous}, accounts.MimetypePoiRotation, RotationRLP(signer, number))
+	if err != nil {
+		return nil, err
+	}
+	rotation := &Rotation{Signer: signer, Number: number, Signature: sig}
+	if _, err := c.submitRotation(chain, rotation); err != nil {
+		return nil, err
+	}
+	return rotation, nil
 }
 
-// monitorBlock monitors block production and updates health status
-func (c *Poi) monitorBlock(chain consensus.ChainHeaderReader, header *types.Header) {
-	// Get the expected in-turn signer for this block
-	snap, err := c.snapshot(chain, header.Number.Uint64()-1, header.ParentHash, nil)
+// submitRotation verifies a signer rotation and queues it up for inclusion in
+// the blocks sealed by this node.
+func (c *Poi) submitRotation(chain consensus.ChainHeaderReader, rotation *Rotation) (common.Hash, error) {
+	if c.config.Governance != nil {
+		return common.Hash{}, errGovernanceRotation
+	}
+	previous, err := rotation.previous()
 	if err != nil {
-		return
+		return common.Hash{}, err
 	}
-	
-	// Get who actually signed the block
-	signer, err := c.Author(header)
+	header := chain.CurrentHeader()
+	snap, err := c.snapshot(chain, header.Number.Uint64(), header.Hash(), nil)
 	if err != nil {
-		return
+		return common.Hash{}, err
 	}
-	
-	// Check if this was the in-turn signer
-	expectedSigners := snap.GetActiveSigners()
-	if len(expectedSigners) == 0 {
-		return
+	if !snap.validRotation(previous, rotation, header.Number.Uint64()+1) {
+		return common.Hash{}, errStaleRotation
 	}
-	
-	blockNumber := header.Number.Uint64()
-	expectedIndex := blockNumber % uint64(len(expectedSigners))
-	expectedSigner := expectedSigners[expectedIndex]
-	
-	// If the actual signer is not the expected in-turn signer, record a failure for the expected signer
-	if signer != expectedSigner {
-		c.recordFailure(expectedSigner)
-		log.Debug("In-turn signer missed block", "expected", expectedSigner, "actual", signer, "block", blockNumber)
-	} else {
-		// Reset failures for successful block production
-		c.resetFailures(signer)
+	log.Info("Queued signer rotation", "signer", previous, "new", rotation.Signer, "number", rotation.Number)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	hash := rotation.Hash()
+	c.rotations[hash] = rotation
+	return hash, nil
+}
+
+// RestrictsSenders implements consensus.TxValidator, returning whether sender
+// restriction is enabled at the block of the given header.
+func (c *Poi) RestrictsSenders(header *types.Header) bool {
+	return c.config.At(header.Number.Uint64()).RestrictSenders
+}
+
+// ValidateTxSender implements consensus.TxValidator, rejecting transactions from
+// senders that are neither registered devices nor authorized signers if sender
+// restriction is enabled.
+func (c *Poi) ValidateTxSender(chain consensus.ChainHeaderReader, header *types.Header, sender common.Address) error {
+	if !c.RestrictsSenders(header) {
+		return nil
 	}
+	number := header.Number.Uint64()
+	if number == 0 {
+		return nil
+	}
+	// Senders are checked against the registry of the parent, the one the block
+	// is built on top of
+	snap, err := c.snapshot(chain, number-1, header.ParentHash, nil)
+	if err != nil {
+		return err
+	}
+	if !snap.registered(sender) {
+		return errUnregisteredSender
+	}
+	return nil
 }
@@ -1,10 +1,14 @@
 package poi
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/params"
+	"golang.org/x/exp/slices"
 )
 
 // TestSignerHealth tests the health status management of signers
@@ -188,4 +192,227 @@ func TestGetBackupSigner(t *testing.T) {
 	if found {
 		t.Error("Should not find backup signer when all are unhealthy")
 	}
-}
\ No newline at end of file
+}
+// TestInturnSignerBackupMode tests that recently signed signers are skipped when
+// picking the in-turn and backup signers in backup mode
+func TestInturnSignerBackupMode(t *testing.T) {
+	signers := []common.Address{
+		common.HexToAddress("0x1111111111111111111111111111111111111111"),
+		common.HexToAddress("0x2222222222222222222222222222222222222222"),
+		common.HexToAddress("0x3333333333333333333333333333333333333333"),
+	}
+
+	config := &params.PoiConfig{
+		Period:        15,
+		Epoch:         30000,
+		BackupTimeout: 30,
+	}
+
+	snap := newSnapshot(config, nil, 0, common.Hash{}, signers)
+
+	// Without recent signers the rotation is plain round-robin
+	if inturn, _ := snap.inturnSigner(4); inturn != signers[1] {
+		t.Errorf("Expected in-turn signer %v, got %v", signers[1], inturn)
+	}
+	if backup, _ := snap.GetBackupSigner(4, signers[1]); backup != signers[2] {
+		t.Errorf("Expected backup signer %v, got %v", signers[2], backup)
+	}
+
+	// A signer that sealed the previous block as backup passes its turn on
+	snap.Number = 4
+	snap.Recents[4] = signers[2]
+	if inturn, _ := snap.inturnSigner(5); inturn != signers[0] {
+		t.Errorf("Expected in-turn signer %v, got %v", signers[0], inturn)
+	}
+	// ...and is not eligible to be the backup signer either
+	if backup, _ := snap.GetBackupSigner(5, signers[1]); backup != signers[0] {
+		t.Errorf("Expected backup signer %v, got %v", signers[0], backup)
+	}
+
+	// Without backup mode the rotation ignores recent signers
+	config.BackupTimeout = 0
+	if inturn, _ := snap.inturnSigner(5); inturn != signers[2] {
+		t.Errorf("Expected in-turn signer %v, got %v", signers[2], inturn)
+	}
+}
+
+// TestSchedule tests the projection of the upcoming in-turn and backup signers
+func TestSchedule(t *testing.T) {
+	signers := []common.Address{
+		common.HexToAddress("0x1111111111111111111111111111111111111111"),
+		common.HexToAddress("0x2222222222222222222222222222222222222222"),
+		common.HexToAddress("0x3333333333333333333333333333333333333333"),
+	}
+
+	config := &params.PoiConfig{
+		Period:        15,
+		Epoch:         30000,
+		BackupTimeout: 30,
+	}
+
+	snap := newSnapshot(config, nil, 0, common.Hash{}, signers)
+
+	// All signers healthy, the next signer in the rotation is the backup
+	slots := snap.schedule(4)
+	if len(slots) != 4 {
+		t.Fatalf("Expected 4 slots, got %d", len(slots))
+	}
+	for i, slot := range slots {
+		var (
+			number = uint64(i + 1)
+			inturn = signers[number%3]
+			backup = signers[(number+1)%3]
+		)
+		if slot.Number != number {
+			t.Errorf("Slot %d: expected number %d, got %d", i, number, slot.Number)
+		}
+		if slot.InTurn == nil || *slot.InTurn != inturn {
+			t.Errorf("Slot %d: expected in-turn signer %v, got %v", i, inturn, slot.InTurn)
+		}
+		if slot.Backup == nil || *slot.Backup != backup {
+			t.Errorf("Slot %d: expected backup signer %v, got %v", i, backup, slot.Backup)
+		}
+	}
+	// Projecting the schedule must not modify the snapshot
+	if snap.Number != 0 || len(snap.Recents) != 0 {
+		t.Errorf("Snapshot modified by schedule projection")
+	}
+
+	// Two active signers alternate, the other one always signed recently
+	snap.MarkUnhealthy(signers[0])
+	for i, slot := range snap.schedule(4)[1:] {
+		inturn := signers[1+int(slot.Number%2)]
+		if slot.InTurn == nil || *slot.InTurn != inturn {
+			t.Errorf("Slot %d: expected in-turn signer %v, got %v", i, inturn, slot.InTurn)
+		}
+		if slot.Backup != nil {
+			t.Errorf("Slot %d: expected no backup signer, got %v", i, *slot.Backup)
+		}
+	}
+}
+
+// TestWeightedSelection tests that in weighted selection mode the in-turn slots
+// are allotted proportionally to the signer performance
+func TestWeightedSelection(t *testing.T) {
+	signers := []common.Address{
+		common.HexToAddress("0x1111111111111111111111111111111111111111"),
+		common.HexToAddress("0x2222222222222222222222222222222222222222"),
+		common.HexToAddress("0x3333333333333333333333333333333333333333"),
+		common.HexToAddress("0x4444444444444444444444444444444444444444"),
+	}
+
+	config := &params.PoiConfig{
+		Period:            15,
+		Epoch:             100,
+		WeightedSelection: true,
+	}
+
+	snap := newSnapshot(config, nil, 0, common.Hash{}, signers)
+	snap.SetPerformance(signers[0], 300)
+	snap.SetPerformance(signers[1], 100)
+	snap.SetPerformance(signers[2], 100)
+
+	// Count the in-turn slots of every signer over a full rotation
+	snap.updateRotation(1)
+	rotation := snap.Rotation
+	if len(rotation) != weightedSlotsPerSigner*len(signers) {
+		t.Fatalf("Expected rotation of %d slots, got %d", weightedSlotsPerSigner*len(signers), len(rotation))
+	}
+	slots := make(map[common.Address]int)
+	for number := uint64(0); number < uint64(len(rotation)); number++ {
+		inturn, ok := snap.inturnSigner(number)
+		if !ok {
+			t.Fatalf("Block %d: no in-turn signer", number)
+		}
+		slots[inturn]++
+	}
+	// Performance 300:100:100:0 over 32 slots, signers without score get a single slot
+	expected := []int{19, 6, 6, 1}
+	for i, signer := range signers {
+		if slots[signer] != expected[i] {
+			t.Errorf("Signer %v: expected %d slots, got %d", signer, expected[i], slots[signer])
+		}
+	}
+	// Performance changes within an epoch don't reshuffle the schedule
+	snap.SetPerformance(signers[3], 500)
+	for number := uint64(0); number < uint64(len(rotation)); number++ {
+		if inturn, _ := snap.inturnSigner(number); inturn != rotation[number] {
+			t.Fatalf("Block %d: in-turn signer changed within the epoch", number)
+		}
+	}
+	snap.SetPerformance(signers[3], 0)
+
+	// The rotation is deterministic within an epoch and reshuffled across epochs
+	snap.updateRotation(99)
+	same := snap.Rotation
+	snap.updateRotation(100)
+	next := snap.Rotation
+	if !slices.Equal(rotation, same) {
+		t.Errorf("Rotation changed within an epoch")
+	}
+	if slices.Equal(rotation, next) {
+		t.Errorf("Rotation not reshuffled across epochs")
+	}
+
+	// Equal performances fall back to equal shares
+	snap = newSnapshot(config, nil, 0, common.Hash{}, signers)
+	snap.updateRotation(1)
+	slots = make(map[common.Address]int)
+	for _, signer := range snap.Rotation {
+		slots[signer]++
+	}
+	for _, signer := range signers {
+		if slots[signer] != weightedSlotsPerSigner {
+			t.Errorf("Signer %v: expected %d slots, got %d", signer, weightedSlotsPerSigner, slots[signer])
+		}
+	}
+}
+
+// TestWeightedSelectionFailures tests that in-turn slots the in-turn signer was
+// not allowed to seal, due to having signed recently, don't count as missed.
+func TestWeightedSelectionFailures(t *testing.T) {
+	accounts := newTesterAccountPool()
+	signers := []common.Address{accounts.address("A"), accounts.address("B")}
+
+	config := &params.PoiConfig{
+		Period:            15,
+		Epoch:             100,
+		WeightedSelection: true,
+		HealthThreshold:   1,
+	}
+	snap := newSnapshot(config, lru.NewCache[common.Hash, common.Address](16), 0, common.Hash{}, signers)
+	snap.updateRotation(0)
+
+	// Every block is sealed by its in-turn signer if allowed, by the other one if not
+	var forbidden int
+	for number := uint64(1); number < config.Epoch; number++ {
+		inturn, ok := snap.inturnSigner(number)
+		if !ok {
+			t.Fatalf("Block %d: no in-turn signer", number)
+		}
+		sealer, other := "A", "B"
+		if inturn != accounts.address("A") {
+			sealer, other = other, sealer
+		}
+		if snap.recentlySigned(number, inturn) {
+			sealer = other
+			forbidden++
+		}
+		header := &types.Header{Number: new(big.Int).SetUint64(number), Extra: make([]byte, extraVanity+extraSeal)}
+		accounts.sign(header, sealer)
+
+		var err error
+		if snap, err = snap.apply([]*types.Header{header}); err != nil {
+			t.Fatalf("Block %d: failed to apply header: %v", number, err)
+		}
+		if len(snap.Failures) != 0 {
+			t.Fatalf("Block %d: unexpected failures %v", number, snap.Failures)
+		}
+	}
+	if forbidden == 0 {
+		t.Fatalf("Expected the rotation to hand some signer consecutive slots")
+	}
+	if failing := snap.failing(snap.Number + 1); len(failing) != 0 {
+		t.Errorf("Expected no failing signers, got %v", failing)
+	}
+}
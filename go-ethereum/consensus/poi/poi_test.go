@@ -1,9 +1,14 @@
 package poi
 
 import (
+	"bytes"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
 	"math/big"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
@@ -11,6 +16,8 @@ import (
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"golang.org/x/exp/slices"
 )
 
 // This test case is a repro of an annoying bug that took us forever to catch.
@@ -107,3 +114,739 @@ func TestSealHash(t *testing.T) {
 		t.Errorf("have %x, want %x", have, want)
 	}
 }
+
+// Tests that in backup mode only the in-turn and the designated backup signer may
+// seal a block, and that the backup signer has to respect the backup timeout.
+func TestBackupSealing(t *testing.T) {
+	// Create three signers, sorted by address to match the active signer order
+	keys := make([]*ecdsa.PrivateKey, 3)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+	}
+	slices.SortFunc(keys, func(a, b *ecdsa.PrivateKey) int {
+		return crypto.PubkeyToAddress(a.PublicKey).Cmp(crypto.PubkeyToAddress(b.PublicKey))
+	})
+	type testerBlock struct {
+		signer     int      // Index of the signer sealing the block
+		difficulty *big.Int // Difficulty the signer claims
+		delay      uint64   // Seconds elapsed since the parent block
+	}
+	tests := []struct {
+		blocks  []testerBlock
+		failure error
+	}{
+		{
+			// In-turn signer sealing on time
+			blocks: []testerBlock{{signer: 1, difficulty: diffInTurn, delay: 1}},
+		}, {
+			// Backup signer sealing after the in-turn signer missed its slot
+			blocks: []testerBlock{{signer: 2, difficulty: diffBackup, delay: 6}},
+		}, {
+			// Backup signer sealing before the backup timeout elapsed
+			blocks:  []testerBlock{{signer: 2, difficulty: diffBackup, delay: 5}},
+			failure: errBackupTooEarly,
+		}, {
+			// Backup signer sealing with an out-of-turn difficulty
+			blocks:  []testerBlock{{signer: 2, difficulty: diffNoTurn, delay: 6}},
+			failure: errWrongDifficulty,
+		}, {
+			// Signer that is neither in-turn nor backup claiming to be the backup
+			blocks:  []testerBlock{{signer: 0, difficulty: diffBackup, delay: 6}},
+			failure: errUnauthorizedBackup,
+		}, {
+			// Signer that is neither in-turn nor backup sealing out-of-turn
+			blocks:  []testerBlock{{signer: 0, difficulty: diffNoTurn, delay: 6}},
+			failure: errUnauthorizedBackup,
+		}, {
+			// The in-turn slot passes on if the in-turn signer sealed recently as backup
+			blocks: []testerBlock{
+				{signer: 2, difficulty: diffBackup, delay: 6},
+				{signer: 0, difficulty: diffInTurn, delay: 1},
+			},
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			genspec := &core.Genesis{
+				Config:    new(params.ChainConfig),
+				ExtraData: make([]byte, extraVanity+len(keys)*common.AddressLength+extraSeal),
+				BaseFee:   big.NewInt(params.InitialBaseFee),
+			}
+			*genspec.Config = *params.TestChainConfig
+			genspec.Config.Poi = &params.PoiConfig{Period: 1, Epoch: 30000, BackupTimeout: 5}
+			for j, key := range keys {
+				addr := crypto.PubkeyToAddress(key.PublicKey)
+				copy(genspec.ExtraData[extraVanity+j*common.AddressLength:], addr[:])
+			}
+			engine := New(genspec.Config.Poi, rawdb.NewMemoryDatabase())
+
+			_, blocks, _ := core.GenerateChainWithGenesis(genspec, engine, len(tt.blocks), func(i int, block *core.BlockGen) {
+				block.SetDifficulty(diffInTurn)
+			})
+			for j, block := range blocks {
+				header := block.Header()
+				if j > 0 {
+					header.ParentHash = blocks[j-1].Hash()
+					header.Time = blocks[j-1].Time() + tt.blocks[j].delay
+				} else {
+					header.Time = genspec.Timestamp + tt.blocks[j].delay
+				}
+				header.Extra = make([]byte, extraVanity+extraSeal)
+				header.Difficulty = tt.blocks[j].difficulty
+
+				sig, _ := crypto.Sign(SealHash(header).Bytes(), keys[tt.blocks[j].signer])
+				copy(header.Extra[len(header.Extra)-extraSeal:], sig)
+				blocks[j] = block.WithSeal(header)
+			}
+			chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, genspec, nil, engine, vm.Config{}, nil, nil)
+			if err != nil {
+				t.Fatalf("failed to create test chain: %v", err)
+			}
+			defer chain.Stop()
+
+			if _, err := chain.InsertChain(blocks); err != tt.failure {
+				t.Errorf("failure mismatch: have %v, want %v", err, tt.failure)
+			}
+		})
+	}
+}
+
+// Tests that in backup mode an unhealthy signer may seal out-of-turn to recover
+// by sealing blocks, as it's never the in-turn or backup signer.
+func TestBackupRecovery(t *testing.T) {
+	// Create three signers, sorted by address to match the active signer order
+	keys := make([]*ecdsa.PrivateKey, 3)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+	}
+	slices.SortFunc(keys, func(a, b *ecdsa.PrivateKey) int {
+		return crypto.PubkeyToAddress(a.PublicKey).Cmp(crypto.PubkeyToAddress(b.PublicKey))
+	})
+	type testerBlock struct {
+		signer     int      // Index of the signer sealing the block
+		difficulty *big.Int // Difficulty the signer claims
+		delay      uint64   // Seconds elapsed since the parent block
+		unhealthy  bool     // Whether the block votes the last signer unhealthy
+	}
+	// Signers 1 and 0 vote signer 2 unhealthy, which then seals out-of-turn
+	// twice while signers 1 and 0 keep their in-turn slots
+	blocks := []testerBlock{
+		{signer: 1, difficulty: diffInTurn, delay: 1, unhealthy: true},
+		{signer: 0, difficulty: diffBackup, delay: 6, unhealthy: true},
+		{signer: 2, difficulty: diffNoTurn, delay: 1},
+		{signer: 0, difficulty: diffInTurn, delay: 1},
+		{signer: 2, difficulty: diffNoTurn, delay: 1},
+	}
+	tests := []struct {
+		recoveryBlocks uint64
+		failure        error
+		health         SignerHealth
+	}{
+		// Unhealthy signer recovering by sealing out-of-turn
+		{recoveryBlocks: 2, health: Healthy},
+		// Unhealthy signer sealing out-of-turn without block based recovery
+		{recoveryBlocks: 0, failure: errUnauthorizedBackup},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			genspec := &core.Genesis{
+				Config:    new(params.ChainConfig),
+				ExtraData: make([]byte, extraVanity+len(keys)*common.AddressLength+extraSeal),
+				BaseFee:   big.NewInt(params.InitialBaseFee),
+			}
+			*genspec.Config = *params.TestChainConfig
+			genspec.Config.Poi = &params.PoiConfig{Period: 1, Epoch: 30000, BackupTimeout: 5, RecoveryBlocks: tt.recoveryBlocks}
+			for j, key := range keys {
+				addr := crypto.PubkeyToAddress(key.PublicKey)
+				copy(genspec.ExtraData[extraVanity+j*common.AddressLength:], addr[:])
+			}
+			engine := New(genspec.Config.Poi, rawdb.NewMemoryDatabase())
+
+			_, chainBlocks, _ := core.GenerateChainWithGenesis(genspec, engine, len(blocks), func(i int, block *core.BlockGen) {
+				block.SetDifficulty(diffInTurn)
+			})
+			for j, block := range chainBlocks {
+				header := block.Header()
+				if j > 0 {
+					header.ParentHash = chainBlocks[j-1].Hash()
+					header.Time = chainBlocks[j-1].Time() + blocks[j].delay
+				} else {
+					header.Time = genspec.Timestamp + blocks[j].delay
+				}
+				header.Extra = make([]byte, extraVanity+extraSeal)
+				header.Difficulty = blocks[j].difficulty
+				if blocks[j].unhealthy {
+					header.MixDigest = encodeStatusVote(statusHealth, crypto.PubkeyToAddress(keys[2].PublicKey), uint64(Unhealthy))
+				}
+				sig, _ := crypto.Sign(SealHash(header).Bytes(), keys[blocks[j].signer])
+				copy(header.Extra[len(header.Extra)-extraSeal:], sig)
+				chainBlocks[j] = block.WithSeal(header)
+			}
+			chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, genspec, nil, engine, vm.Config{}, nil, nil)
+			if err != nil {
+				t.Fatalf("failed to create test chain: %v", err)
+			}
+			defer chain.Stop()
+
+			if _, err := chain.InsertChain(chainBlocks); err != tt.failure {
+				t.Fatalf("failure mismatch: have %v, want %v", err, tt.failure)
+			}
+			if tt.failure != nil {
+				return
+			}
+			head := chain.CurrentHeader()
+			snap, err := engine.snapshot(chain, head.Number.Uint64(), head.Hash(), nil)
+			if err != nil {
+				t.Fatalf("failed to retrieve snapshot: %v", err)
+			}
+			if have := snap.Health[crypto.PubkeyToAddress(keys[2].PublicKey)]; have != tt.health {
+				t.Errorf("health mismatch: have %d, want %d", have, tt.health)
+			}
+		})
+	}
+}
+
+// Tests that with sender restriction enabled only registered devices and
+// authorized signers may send transactions.
+func TestRestrictSenders(t *testing.T) {
+	var (
+		signerKey, _   = crypto.GenerateKey()
+		deviceKey, _   = crypto.GenerateKey()
+		outsiderKey, _ = crypto.GenerateKey()
+		signerAddr     = crypto.PubkeyToAddress(signerKey.PublicKey)
+		deviceAddr     = crypto.PubkeyToAddress(deviceKey.PublicKey)
+		outsiderAddr   = crypto.PubkeyToAddress(outsiderKey.PublicKey)
+		signer         = new(types.HomesteadSigner)
+	)
+	tests := []struct {
+		senders []*ecdsa.PrivateKey // Sender of the transaction in each block after the registration
+		failure bool
+	}{
+		{senders: []*ecdsa.PrivateKey{signerKey}},
+		{senders: []*ecdsa.PrivateKey{deviceKey}},
+		{senders: []*ecdsa.PrivateKey{deviceKey, outsiderKey}, failure: true},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			genspec := &core.Genesis{
+				Config:    new(params.ChainConfig),
+				ExtraData: make([]byte, extraVanity+common.AddressLength+extraSeal),
+				Alloc: map[common.Address]types.Account{
+					signerAddr:   {Balance: big.NewInt(10000000000000000)},
+					deviceAddr:   {Balance: big.NewInt(10000000000000000)},
+					outsiderAddr: {Balance: big.NewInt(10000000000000000)},
+				},
+				BaseFee: big.NewInt(params.InitialBaseFee),
+			}
+			*genspec.Config = *params.TestChainConfig
+			genspec.Config.Poi = &params.PoiConfig{Period: 1, Epoch: 30000, RestrictSenders: true}
+			copy(genspec.ExtraData[extraVanity:], signerAddr[:])
+
+			engine := New(genspec.Config.Poi, rawdb.NewMemoryDatabase())
+
+			// Register the device in the first block, then send the transactions
+			_, blocks, _ := core.GenerateChainWithGenesis(genspec, engine, 1+len(tt.senders), func(i int, block *core.BlockGen) {
+				block.SetDifficulty(diffInTurn)
+				if i == 0 {
+					return
+				}
+				key := tt.senders[i-1]
+				tx, _ := types.SignTx(types.NewTransaction(block.TxNonce(crypto.PubkeyToAddress(key.PublicKey)), common.Address{0x00}, new(big.Int), params.TxGas, block.BaseFee(), nil), signer, key)
+				block.AddTx(tx)
+			})
+			ext, _ := encodeExtension(&headerExtension{
+				Version: extensionVersion,
+				Devices: []*deviceVote{{Address: deviceAddr, Metadata: common.Hash{0x01}, Authorize: true}},
+			})
+			for j, block := range blocks {
+				header := block.Header()
+				if j > 0 {
+					header.ParentHash = blocks[j-1].Hash()
+				}
+				header.Extra = make([]byte, extraVanity+extraSeal)
+				if j == 0 {
+					header.Extra = append(append(make([]byte, extraVanity), ext...), make([]byte, extraSeal)...)
+				}
+				header.Difficulty = diffInTurn
+
+				sig, _ := crypto.Sign(SealHash(header).Bytes(), signerKey)
+				copy(header.Extra[len(header.Extra)-extraSeal:], sig)
+				blocks[j] = block.WithSeal(header)
+			}
+			chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, genspec, nil, engine, vm.Config{}, nil, nil)
+			if err != nil {
+				t.Fatalf("failed to create test chain: %v", err)
+			}
+			defer chain.Stop()
+
+			_, err = chain.InsertChain(blocks)
+			if tt.failure && !errors.Is(err, errUnregisteredSender) {
+				t.Errorf("failure mismatch: have %v, want %v", err, errUnregisteredSender)
+			}
+			if !tt.failure && err != nil {
+				t.Errorf("failed to insert chain: %v", err)
+			}
+		})
+	}
+}
+
+// Tests that the developer mode genesis can be sealed on top of by an engine
+// authorized with the developer account.
+func TestDeveloperGenesis(t *testing.T) {
+	var (
+		key, _ = crypto.GenerateKey()
+		addr   = crypto.PubkeyToAddress(key.PublicKey)
+		signer = new(types.HomesteadSigner)
+	)
+	genspec := core.DeveloperPoiGenesisBlock(0, 11_500_000, addr)
+	engine := New(genspec.Config.Poi, rawdb.NewMemoryDatabase())
+	engine.Authorize(addr, func(account accounts.Account, mimeType string, message []byte) ([]byte, error) {
+		return crypto.Sign(crypto.Keccak256(message), key)
+	})
+	chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, genspec, nil, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create test chain: %v", err)
+	}
+	defer chain.Stop()
+
+	_, blocks, _ := core.GenerateChainWithGenesis(genspec, engine, 1, func(i int, block *core.BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(block.TxNonce(addr), common.Address{0x00}, new(big.Int), params.TxGas, block.BaseFee(), nil), signer, key)
+		block.AddTx(tx)
+	})
+	header := blocks[0].Header()
+	if err := engine.Prepare(chain, header); err != nil {
+		t.Fatalf("failed to prepare header: %v", err)
+	}
+	if header.Difficulty.Cmp(diffInTurn) != 0 {
+		t.Fatalf("difficulty mismatch: have %v, want %v", header.Difficulty, diffInTurn)
+	}
+	results := make(chan *types.Block, 1)
+	if err := engine.Seal(chain, blocks[0].WithSeal(header), results, nil); err != nil {
+		t.Fatalf("failed to seal block: %v", err)
+	}
+	if _, err := chain.InsertChain([]*types.Block{<-results}); err != nil {
+		t.Fatalf("failed to insert sealed block: %v", err)
+	}
+}
+
+// Tests that pruning the snapshot store only keeps the recent snapshots and the
+// canonical checkpoint interval ones.
+func TestPruneSnapshots(t *testing.T) {
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		config  = &params.PoiConfig{Period: 1, Epoch: 30000}
+		signers = []common.Address{{0x01}}
+		head    = uint64(3 * checkpointInterval)
+	)
+	canonical := func(number uint64) common.Hash {
+		return common.BigToHash(new(big.Int).SetUint64(number + 1))
+	}
+	for number := uint64(0); number <= head; number++ {
+		rawdb.WriteCanonicalHash(db, canonical(number), number)
+	}
+	rawdb.WriteHeaderNumber(db, canonical(head), head)
+	rawdb.WriteHeadHeaderHash(db, canonical(head))
+
+	stored := map[common.Hash]bool{
+		canonical(0):                      true,  // genesis
+		canonical(checkpointInterval):     true,  // canonical checkpoint interval
+		canonical(2 * checkpointInterval): true,  // recent canonical checkpoint interval
+		canonical(30):                     false, // old epoch checkpoint
+		{0xff, 0x01}:                      false, // old side chain at checkpoint interval
+		canonical(head - 10):              true,  // recent epoch checkpoint
+		{0xff, 0x02}:                      true,  // recent side chain
+	}
+	numbers := map[common.Hash]uint64{
+		canonical(0):                      0,
+		canonical(checkpointInterval):     checkpointInterval,
+		canonical(2 * checkpointInterval): 2 * checkpointInterval,
+		canonical(30):                     30,
+		{0xff, 0x01}:                      checkpointInterval,
+		canonical(head - 10):              head - 10,
+		{0xff, 0x02}:                      head - 5,
+	}
+	for hash, number := range numbers {
+		if err := newSnapshot(config, nil, number, hash, signers).store(db); err != nil {
+			t.Fatalf("failed to store snapshot %x: %v", hash, err)
+		}
+	}
+	pruned, err := PruneSnapshots(db, checkpointInterval)
+	if err != nil {
+		t.Fatalf("failed to prune snapshots: %v", err)
+	}
+	if pruned != 2 {
+		t.Errorf("pruned snapshot count mismatch: have %d, want %d", pruned, 2)
+	}
+	for hash, keep := range stored {
+		if _, _, err := loadSnapshot(config, nil, db, hash); (err == nil) != keep {
+			t.Errorf("snapshot %d (%x): kept mismatch: have %v, want %v", numbers[hash], hash, err == nil, keep)
+		}
+	}
+}
+
+// Tests that under contract governance the checkpoint signer list is read from
+// the governance contract, verified against it and applied to the snapshot.
+func TestGovernance(t *testing.T) {
+	var (
+		signerKey, _ = crypto.GenerateKey()
+		joinerKey, _ = crypto.GenerateKey()
+		signerAddr   = crypto.PubkeyToAddress(signerKey.PublicKey)
+		joinerAddr   = crypto.PubkeyToAddress(joinerKey.PublicKey)
+		governance   = common.Address{0x0a}
+		signer       = new(types.HomesteadSigner)
+
+		// Governance contract stand-in storing calldata[32:64] at slot calldata[0:32]
+		code = common.FromHex("0x6020356000355500")
+	)
+	joined := []common.Address{signerAddr, joinerAddr}
+	slices.SortFunc(joined, func(a, b common.Address) int { return a.Cmp(b) })
+	weights := make([]uint64, len(joined))
+	for i, address := range joined {
+		weights[i] = map[common.Address]uint64{signerAddr: 1, joinerAddr: 7}[address]
+	}
+	tests := []struct {
+		checkpoint []byte // Signer list sealed into the checkpoint block
+		vote       bool   // Whether to cast a signer vote in the second block
+		failure    error
+	}{
+		{checkpoint: encodeGovernanceCheckpoint(joined, weights)},
+		{checkpoint: encodeGovernanceCheckpoint([]common.Address{signerAddr}, []uint64{1}), failure: errMismatchingCheckpointSigners},
+		{checkpoint: encodeGovernanceCheckpoint(joined, weights), vote: true, failure: errGovernanceVote},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			genspec := &core.Genesis{
+				Config:    new(params.ChainConfig),
+				ExtraData: append(append(make([]byte, extraVanity), encodeGovernanceCheckpoint([]common.Address{signerAddr}, []uint64{1})...), make([]byte, extraSeal)...),
+				Alloc: map[common.Address]types.Account{
+					signerAddr: {Balance: big.NewInt(10000000000000000)},
+					governance: {Code: code, Storage: GovernanceStorage([]common.Address{signerAddr}, []uint64{1})},
+				},
+				BaseFee: big.NewInt(params.InitialBaseFee),
+			}
+			*genspec.Config = *params.TestChainConfig
+			genspec.Config.Poi = &params.PoiConfig{Period: 1, Epoch: 4, Governance: &governance}
+
+			engine := New(genspec.Config.Poi, rawdb.NewMemoryDatabase())
+
+			// Add the joiner to the governance contract in the first block
+			_, blocks, _ := core.GenerateChainWithGenesis(genspec, engine, 4, func(i int, block *core.BlockGen) {
+				block.SetDifficulty(diffInTurn)
+				if i != 0 {
+					return
+				}
+				for _, slot := range [][2]common.Hash{
+					{common.BigToHash(big.NewInt(governanceSignersSlot)), common.BigToHash(big.NewInt(2))},
+					{governanceArraySlot(governanceSignersSlot, 1), common.BytesToHash(joinerAddr[:])},
+					{governanceMappingSlot(governanceWeightsSlot, joinerAddr), common.BigToHash(big.NewInt(7))},
+				} {
+					data := append(slot[0].Bytes(), slot[1].Bytes()...)
+					tx, _ := types.SignTx(types.NewTransaction(block.TxNonce(signerAddr), governance, new(big.Int), 100000, block.BaseFee(), data), signer, signerKey)
+					block.AddTx(tx)
+				}
+			})
+			for j, block := range blocks {
+				header := block.Header()
+				if j > 0 {
+					header.ParentHash = blocks[j-1].Hash()
+				}
+				header.Extra = make([]byte, extraVanity+extraSeal)
+				if header.Number.Uint64()%genspec.Config.Poi.Epoch == 0 {
+					header.Extra = append(append(make([]byte, extraVanity), tt.checkpoint...), make([]byte, extraSeal)...)
+				}
+				if j == 1 && tt.vote {
+					header.Coinbase = joinerAddr
+					copy(header.Nonce[:], nonceAuthVote)
+				}
+				header.Difficulty = diffInTurn
+
+				sig, _ := crypto.Sign(SealHash(header).Bytes(), signerKey)
+				copy(header.Extra[len(header.Extra)-extraSeal:], sig)
+				blocks[j] = block.WithSeal(header)
+			}
+			chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, genspec, nil, engine, vm.Config{}, nil, nil)
+			if err != nil {
+				t.Fatalf("failed to create test chain: %v", err)
+			}
+			defer chain.Stop()
+
+			if _, err := chain.InsertChain(blocks[:3]); err != nil {
+				if !errors.Is(err, tt.failure) {
+					t.Fatalf("failed to insert pre-checkpoint blocks: %v", err)
+				}
+				return
+			}
+			// The sealer must assemble the same checkpoint from the contract
+			header := &types.Header{ParentHash: blocks[2].Hash(), Number: big.NewInt(4), GasLimit: blocks[2].GasLimit()}
+			if err := engine.Prepare(chain, header); err != nil {
+				t.Fatalf("failed to prepare checkpoint: %v", err)
+			}
+			if want := encodeGovernanceCheckpoint(joined, weights); !bytes.Equal(header.Extra[extraVanity:len(header.Extra)-extraSeal], want) {
+				t.Errorf("prepared checkpoint mismatch: have %x, want %x", header.Extra[extraVanity:len(header.Extra)-extraSeal], want)
+			}
+			_, err = chain.InsertChain(blocks[3:])
+			if !errors.Is(err, tt.failure) {
+				t.Fatalf("failure mismatch: have %v, want %v", err, tt.failure)
+			}
+			if tt.failure != nil {
+				return
+			}
+			snap, err := engine.snapshot(chain, 4, blocks[3].Hash(), nil)
+			if err != nil {
+				t.Fatalf("failed to retrieve snapshot: %v", err)
+			}
+			if _, ok := snap.Signers[joinerAddr]; !ok || len(snap.Signers) != 2 {
+				t.Errorf("signers mismatch: have %v, want %v", snap.signers(), joined)
+			}
+			if snap.Performance[joinerAddr] != 7 || snap.Performance[signerAddr] != 1 {
+				t.Errorf("performance mismatch: have %v", snap.Performance)
+			}
+		})
+	}
+}
+
+// Tests that the reward policy mints the block reward and in-turn bonus to the
+// signer of each block and splits the base fee among the signers.
+func TestRewards(t *testing.T) {
+	keys := make([]*ecdsa.PrivateKey, 2)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+	}
+	slices.SortFunc(keys, func(a, b *ecdsa.PrivateKey) int {
+		return crypto.PubkeyToAddress(a.PublicKey).Cmp(crypto.PubkeyToAddress(b.PublicKey))
+	})
+	addrs := []common.Address{crypto.PubkeyToAddress(keys[0].PublicKey), crypto.PubkeyToAddress(keys[1].PublicKey)}
+
+	genspec := &core.Genesis{
+		Config:    new(params.ChainConfig),
+		ExtraData: append(append(append(make([]byte, extraVanity), addrs[0][:]...), addrs[1][:]...), make([]byte, extraSeal)...),
+		Alloc: map[common.Address]types.Account{
+			addrs[0]: {Balance: big.NewInt(10000000000000000)},
+		},
+		BaseFee: big.NewInt(params.InitialBaseFee),
+	}
+	*genspec.Config = *params.TestChainConfig
+	genspec.Config.Poi = &params.PoiConfig{
+		Period:       1,
+		Epoch:        30000,
+		BlockReward:  big.NewInt(1000),
+		InTurnBonus:  big.NewInt(500),
+		ShareBaseFee: true,
+	}
+	engine := New(genspec.Config.Poi, rawdb.NewMemoryDatabase())
+	engine.fakeDiff = true
+
+	// Signer 1 seals in-turn with a transaction burning base fee only, signer 0
+	// seals an empty block out-of-turn
+	var (
+		sealers = []int{1, 0}
+		diffs   = []*big.Int{diffInTurn, diffNoTurn}
+		fees    *big.Int
+	)
+	_, blocks, _ := core.GenerateChainWithGenesis(genspec, engine, len(sealers), func(i int, block *core.BlockGen) {
+		engine.Authorize(addrs[sealers[i]], nil)
+		block.SetCoinbase(common.Address{})
+		block.SetDifficulty(diffs[i])
+		if i == 0 {
+			tx, _ := types.SignTx(types.NewTransaction(block.TxNonce(addrs[0]), common.Address{0x00}, new(big.Int), params.TxGas, block.BaseFee(), nil), new(types.HomesteadSigner), keys[0])
+			block.AddTx(tx)
+			fees = new(big.Int).Mul(block.BaseFee(), big.NewInt(int64(params.TxGas)))
+		}
+	})
+	for j, block := range blocks {
+		header := block.Header()
+		if j > 0 {
+			header.ParentHash = blocks[j-1].Hash()
+		}
+		header.Extra = make([]byte, extraVanity+extraSeal)
+
+		sig, _ := crypto.Sign(SealHash(header).Bytes(), keys[sealers[j]])
+		copy(header.Extra[len(header.Extra)-extraSeal:], sig)
+		blocks[j] = block.WithSeal(header)
+	}
+	// Import with a fresh engine, crediting the rewards to the recovered signers
+	verifier := New(genspec.Config.Poi, rawdb.NewMemoryDatabase())
+	verifier.fakeDiff = true
+
+	chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, genspec, nil, verifier, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create test chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	statedb, err := chain.State()
+	if err != nil {
+		t.Fatalf("failed to retrieve state: %v", err)
+	}
+	share := new(big.Int).Div(fees, big.NewInt(2))
+	remainder := new(big.Int).Mod(fees, big.NewInt(2))
+
+	want := new(big.Int).Add(big.NewInt(10000000000000000+1000), share)
+	want.Sub(want, fees)
+	if have := statedb.GetBalance(addrs[0]).ToBig(); have.Cmp(want) != 0 {
+		t.Errorf("out-of-turn signer balance mismatch: have %v, want %v", have, want)
+	}
+	want = new(big.Int).Add(big.NewInt(1000+500), share)
+	want.Add(want, remainder)
+	if have := statedb.GetBalance(addrs[1]).ToBig(); have.Cmp(want) != 0 {
+		t.Errorf("in-turn signer balance mismatch: have %v, want %v", have, want)
+	}
+	// Without the parent snapshot the base fee can't be split, the header must be
+	// rejected by verification and nothing may be credited in some other way
+	orphan := types.CopyHeader(blocks[0].Header())
+	orphan.Number, orphan.ParentHash = big.NewInt(10), common.Hash{0xff}
+
+	if err := verifier.VerifyHeader(chain, orphan); err == nil {
+		t.Errorf("orphan header verified")
+	}
+	verifier.Finalize(chain, orphan, statedb, nil, nil, nil)
+	if have := statedb.GetBalance(addrs[1]).ToBig(); have.Cmp(want) != 0 {
+		t.Errorf("in-turn signer balance changed without parent snapshot: have %v, want %v", have, want)
+	}
+}
+
+// Tests that the local signer can hand its identity over to a new key and that
+// the handover is sealed into the next block it prepares.
+func TestRotate(t *testing.T) {
+	var (
+		key, _  = crypto.GenerateKey()
+		signer  = crypto.PubkeyToAddress(key.PublicKey)
+		next    = common.Address{0x01}
+		genspec = &core.Genesis{
+			Config:    new(params.ChainConfig),
+			ExtraData: append(append(make([]byte, extraVanity), signer[:]...), make([]byte, extraSeal)...),
+			BaseFee:   big.NewInt(params.InitialBaseFee),
+		}
+	)
+	*genspec.Config = *params.TestChainConfig
+	genspec.Config.Poi = &params.PoiConfig{Period: 1, Epoch: 30000}
+
+	engine := New(genspec.Config.Poi, rawdb.NewMemoryDatabase())
+	chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, genspec, nil, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create test chain: %v", err)
+	}
+	defer chain.Stop()
+
+	// Rotations can only be signed by an authorized node
+	if _, err := engine.rotate(chain, next); err != errUnauthorizedSigner {
+		t.Fatalf("unauthorized rotation error mismatch: have %v, want %v", err, errUnauthorizedSigner)
+	}
+	engine.Authorize(signer, func(account accounts.Account, mimeType string, data []byte) ([]byte, error) {
+		if mimeType != accounts.MimetypePoiRotation {
+			t.Errorf("mime type mismatch: have %s, want %s", mimeType, accounts.MimetypePoiRotation)
+		}
+		return crypto.Sign(crypto.Keccak256(data), key)
+	})
+	rotation, err := engine.rotate(chain, next)
+	if err != nil {
+		t.Fatalf("failed to rotate signer: %v", err)
+	}
+	if previous, err := rotation.previous(); err != nil || previous != signer {
+		t.Fatalf("rotation signer mismatch: have %x (%v), want %x", previous, err, signer)
+	}
+	// A key can't hand its identity over to itself
+	stale := &Rotation{Signer: signer, Number: 0}
+	stale.Signature, _ = crypto.Sign(crypto.Keccak256(RotationRLP(stale.Signer, stale.Number)), key)
+	if _, err := engine.submitRotation(chain, stale); err != errInvalidRotation {
+		t.Errorf("self rotation error mismatch: have %v, want %v", err, errInvalidRotation)
+	}
+	// The pending rotation is sealed into the next block
+	header := &types.Header{Number: big.NewInt(1), ParentHash: chain.Genesis().Hash()}
+	if err := engine.Prepare(chain, header); err != nil {
+		t.Fatalf("failed to prepare header: %v", err)
+	}
+	ext, err := decodeExtension(header)
+	if err != nil {
+		t.Fatalf("failed to decode header extension: %v", err)
+	}
+	if ext == nil || len(ext.Rotations) != 1 || ext.Rotations[0].Hash() != rotation.Hash() {
+		t.Fatalf("rotation not sealed into header: have %+v", ext)
+	}
+}
+
+// Tests that evidence headers the seal hash can't be computed of are rejected
+// instead of crashing the node, both in header extensions and over RPC.
+func TestMalformedEvidence(t *testing.T) {
+	var (
+		key, _  = crypto.GenerateKey()
+		signer  = crypto.PubkeyToAddress(key.PublicKey)
+		genspec = &core.Genesis{
+			Config:    new(params.ChainConfig),
+			ExtraData: append(append(make([]byte, extraVanity), signer[:]...), make([]byte, extraSeal)...),
+			BaseFee:   big.NewInt(params.InitialBaseFee),
+		}
+	)
+	*genspec.Config = *params.TestChainConfig
+	genspec.Config.Poi = &params.PoiConfig{Period: 1, Epoch: 30000}
+
+	engine := New(genspec.Config.Poi, rawdb.NewMemoryDatabase())
+	chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, genspec, nil, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create test chain: %v", err)
+	}
+	defer chain.Stop()
+
+	valid := func() *types.Header {
+		return &types.Header{Number: big.NewInt(1), Difficulty: diffInTurn, Extra: make([]byte, extraVanity+extraSeal)}
+	}
+	shortExtra := valid()
+	shortExtra.Extra = make([]byte, extraSeal-1)
+	withdrawals := valid()
+	withdrawals.WithdrawalsHash = &types.EmptyWithdrawalsHash
+	blobGas := valid()
+	blobGas.WithdrawalsHash, blobGas.BlobGasUsed, blobGas.ExcessBlobGas = &types.EmptyWithdrawalsHash, new(uint64), new(uint64)
+	beaconRoot := valid()
+	beaconRoot.WithdrawalsHash, beaconRoot.BlobGasUsed, beaconRoot.ExcessBlobGas = &types.EmptyWithdrawalsHash, new(uint64), new(uint64)
+	beaconRoot.ParentBeaconRoot = new(common.Hash)
+
+	for i, malformed := range []*types.Header{shortExtra, withdrawals, blobGas, beaconRoot} {
+		other := valid()
+		other.Time = 1
+
+		// Through the header extension of an imported header
+		blob, err := encodeExtension(&headerExtension{Version: extensionVersion, Evidence: []*Evidence{{First: other, Second: malformed}}})
+		if err != nil {
+			t.Fatalf("test %d: failed to encode extension: %v", i, err)
+		}
+		header := &types.Header{Number: big.NewInt(2), Extra: append(append(make([]byte, extraVanity), blob...), make([]byte, extraSeal)...)}
+		if _, err := decodeExtension(header); err != errInvalidEvidence {
+			t.Errorf("test %d: extension error mismatch: have %v, want %v", i, err, errInvalidEvidence)
+		}
+		// Through the RPC API
+		first, _ := rlp.EncodeToBytes(other)
+		second, _ := rlp.EncodeToBytes(malformed)
+		api := &API{chain: chain, poi: engine}
+		if _, err := api.SubmitEvidence(first, second); err != errInvalidEvidence {
+			t.Errorf("test %d: submission error mismatch: have %v, want %v", i, err, errInvalidEvidence)
+		}
+	}
+}
+
+// Tests that parameter overrides take effect at their block.
+func TestOverrides(t *testing.T) {
+	restrict := true
+	config := &params.PoiConfig{Period: 1, Epoch: 30000, BlockReward: big.NewInt(1000), Overrides: []*params.PoiOverride{
+		{Block: big.NewInt(10), BlockReward: big.NewInt(0), RestrictSenders: &restrict},
+	}}
+	engine := New(config, rawdb.NewMemoryDatabase())
+
+	for _, tt := range []struct {
+		number   int64
+		reward   uint64
+		restrict bool
+	}{
+		{1, 1000, false}, {9, 1000, false}, {10, 0, true}, {11, 0, true},
+	} {
+		header := &types.Header{Number: big.NewInt(tt.number), Difficulty: diffNoTurn}
+		if have := engine.blockReward(header); have.Uint64() != tt.reward {
+			t.Errorf("block %d: reward mismatch: have %v, want %d", tt.number, have, tt.reward)
+		}
+		if have := engine.RestrictsSenders(header); have != tt.restrict {
+			t.Errorf("block %d: sender restriction mismatch: have %v, want %v", tt.number, have, tt.restrict)
+		}
+	}
+}
@@ -0,0 +1,369 @@
+// Package poisim runs networks of PoI sealers in-process, connected over
+// simulated devp2p links, to test the consensus engine end to end.
+package poisim
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/beacon"
+	"github.com/ethereum/go-ethereum/consensus/poi"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/eth/downloader"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/p2p/simulations"
+	"github.com/ethereum/go-ethereum/p2p/simulations/adapters"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// serviceName is the name the PoI node service is registered with in the
+// simulation adapter.
+const serviceName = "poi"
+
+// errNodeDown is returned if an operation is attempted on a killed node.
+var errNodeDown = errors.New("node is down")
+
+// Config contains the parameters of a simulated PoI network.
+type Config struct {
+	Signers int               // Number of sealing nodes to run, all authorized at genesis
+	Poi     *params.PoiConfig // Consensus engine parameters of the chain
+}
+
+// Node is a single sealing node of a simulated network.
+type Node struct {
+	ID     enode.ID       // Identifier of the node in the simulation
+	Signer common.Address // Account the node seals blocks with
+
+	key   *ecdsa.PrivateKey
+	eth   *eth.Ethereum
+	delay atomic.Int64 // Time in nanoseconds to stall before signing a block
+	down  atomic.Bool  // Whether the node was killed
+}
+
+// Chain returns the blockchain of the node.
+func (n *Node) Chain() *core.BlockChain {
+	return n.eth.BlockChain()
+}
+
+// Head returns the current head header of the node.
+func (n *Node) Head() *types.Header {
+	return n.eth.BlockChain().CurrentHeader()
+}
+
+// Alive returns whether the node is still running.
+func (n *Node) Alive() bool {
+	return !n.down.Load()
+}
+
+// signData is the PoI signer callback of the node, stalling for the configured
+// delay before sealing to simulate a slow signer.
+func (n *Node) signData(account accounts.Account, mimeType string, data []byte) ([]byte, error) {
+	if delay := time.Duration(n.delay.Load()); delay > 0 {
+		time.Sleep(delay)
+	}
+	return crypto.Sign(crypto.Keccak256(data), n.key)
+}
+
+// Network is a set of PoI sealing nodes running in-process.
+type Network struct {
+	genesis *core.Genesis
+	sim     *simulations.Network
+	nodes   []*Node
+	byID    map[enode.ID]*Node
+
+	links map[[2]int]bool // Pairs of node indices currently connected
+	lock  sync.Mutex
+}
+
+// NewNetwork creates a simulated network of PoI signers. The nodes are sorted by
+// signer address, so node i is the in-turn signer of block i modulo the number
+// of signers until the signer set or its order changes. The network needs to be
+// started before it produces blocks.
+func NewNetwork(config *Config) (*Network, error) {
+	if config.Signers <= 0 {
+		return nil, errors.New("no signers configured")
+	}
+	if config.Poi == nil {
+		return nil, errors.New("missing poi config")
+	}
+	n := &Network{
+		byID:  make(map[enode.ID]*Node),
+		links: make(map[[2]int]bool),
+	}
+	confs := make([]*adapters.NodeConfig, config.Signers)
+	for i := range confs {
+		confs[i] = adapters.RandomNodeConfig()
+		confs[i].Lifecycles = []string{serviceName}
+	}
+	sort.Slice(confs, func(i, j int) bool {
+		a := crypto.PubkeyToAddress(confs[i].PrivateKey.PublicKey)
+		b := crypto.PubkeyToAddress(confs[j].PrivateKey.PublicKey)
+		return bytes.Compare(a[:], b[:]) < 0
+	})
+	// Assemble a genesis authorizing and funding all the signers
+	chainConfig := *params.AllPoiProtocolChanges
+	chainConfig.Poi = config.Poi
+
+	n.genesis = &core.Genesis{
+		Config:     &chainConfig,
+		GasLimit:   ethconfig.Defaults.Miner.GasCeil,
+		Difficulty: big.NewInt(1),
+		Alloc:      make(types.GenesisAlloc),
+		ExtraData:  make([]byte, 32),
+	}
+	for _, conf := range confs {
+		node := &Node{
+			ID:     conf.ID,
+			Signer: crypto.PubkeyToAddress(conf.PrivateKey.PublicKey),
+			key:    conf.PrivateKey,
+		}
+		n.nodes = append(n.nodes, node)
+		n.byID[node.ID] = node
+
+		n.genesis.ExtraData = append(n.genesis.ExtraData, node.Signer[:]...)
+		n.genesis.Alloc[node.Signer] = types.Account{Balance: new(big.Int).Lsh(big.NewInt(1), 128)}
+	}
+	n.genesis.ExtraData = append(n.genesis.ExtraData, make([]byte, crypto.SignatureLength)...)
+
+	// Create the simulated network with a PoI node per signer
+	adapter := adapters.NewSimAdapter(adapters.LifecycleConstructors{serviceName: n.newService})
+	n.sim = simulations.NewNetwork(adapter, &simulations.NetworkConfig{DefaultService: serviceName})
+
+	for _, conf := range confs {
+		if _, err := n.sim.NewNodeWithConfig(conf); err != nil {
+			n.sim.Shutdown()
+			return nil, err
+		}
+	}
+	return n, nil
+}
+
+// newService creates the Ethereum service of a simulated node.
+func (n *Network) newService(ctx *adapters.ServiceContext, stack *node.Node) (node.Lifecycle, error) {
+	node, ok := n.byID[ctx.Config.ID]
+	if !ok {
+		return nil, fmt.Errorf("unknown node %s", ctx.Config.ID)
+	}
+	config := ethconfig.Defaults
+	config.Genesis = n.genesis
+	config.NetworkId = n.genesis.Config.ChainID.Uint64()
+	config.SyncMode = downloader.FullSync
+	config.TxPool.NoLocals = true
+	config.Miner.Etherbase = node.Signer
+
+	backend, err := eth.New(stack, &config)
+	if err != nil {
+		return nil, err
+	}
+	node.eth = backend
+	return backend, nil
+}
+
+// Start boots all the nodes, connects them into a full mesh and starts sealing.
+func (n *Network) Start() error {
+	if err := n.sim.StartAll(); err != nil {
+		return err
+	}
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	for i := range n.nodes {
+		for j := i + 1; j < len(n.nodes); j++ {
+			if err := n.connect(i, j); err != nil {
+				return err
+			}
+		}
+	}
+	for _, node := range n.nodes {
+		engine, ok := node.eth.Engine().(*beacon.Beacon).InnerEngine().(*poi.Poi)
+		if !ok {
+			return errors.New("node is not running the poi engine")
+		}
+		engine.Authorize(node.Signer, node.signData)
+
+		// Nodes start at genesis together, there is nothing to sync from
+		node.eth.SetSynced()
+		node.eth.Miner().Start()
+	}
+	return nil
+}
+
+// Shutdown stops all the nodes of the network.
+func (n *Network) Shutdown() {
+	n.sim.Shutdown()
+}
+
+// Nodes returns the nodes of the network, sorted by signer address.
+func (n *Network) Nodes() []*Node {
+	return n.nodes
+}
+
+// Node returns the i-th node of the network.
+func (n *Network) Node(i int) *Node {
+	return n.nodes[i]
+}
+
+// Kill stops a node permanently, simulating a crashed signer.
+func (n *Network) Kill(i int) error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	node := n.nodes[i]
+	if !node.Alive() {
+		return errNodeDown
+	}
+	node.down.Store(true)
+	for link := range n.links {
+		if link[0] == i || link[1] == i {
+			delete(n.links, link)
+		}
+	}
+	return n.sim.Stop(node.ID)
+}
+
+// Delay makes a node stall for the given time before signing each block it
+// seals, simulating a slow signer. A zero delay restores normal operation.
+func (n *Network) Delay(i int, delay time.Duration) {
+	n.nodes[i].delay.Store(int64(delay))
+}
+
+// Partition splits the network into the given groups of node indices by
+// dropping all links between nodes in different groups. Nodes not listed in any
+// group are isolated.
+func (n *Network) Partition(groups ...[]int) error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	group := make(map[int]int)
+	for g, members := range groups {
+		for _, i := range members {
+			group[i] = g + 1
+		}
+	}
+	for link := range n.links {
+		if g, ok := group[link[0]]; ok && g == group[link[1]] {
+			continue
+		}
+		if err := n.disconnect(link[0], link[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Heal reconnects all the running nodes into a full mesh.
+func (n *Network) Heal() error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	for i := range n.nodes {
+		for j := i + 1; j < len(n.nodes); j++ {
+			if !n.nodes[i].Alive() || !n.nodes[j].Alive() || n.links[[2]int{i, j}] {
+				continue
+			}
+			if err := n.connect(i, j); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// connect links two nodes, i < j. The lock must be held.
+func (n *Network) connect(i, j int) error {
+	if err := n.sim.Connect(n.nodes[i].ID, n.nodes[j].ID); err != nil {
+		return err
+	}
+	n.links[[2]int{i, j}] = true
+	return nil
+}
+
+// disconnect drops the link between two nodes, i < j. The lock must be held.
+func (n *Network) disconnect(i, j int) error {
+	if err := n.sim.Disconnect(n.nodes[i].ID, n.nodes[j].ID); err != nil {
+		return err
+	}
+	delete(n.links, [2]int{i, j})
+	return nil
+}
+
+// WaitHeight waits until all the given nodes (all running ones if none given)
+// reach at least the given block number.
+func (n *Network) WaitHeight(number uint64, timeout time.Duration, nodes ...int) error {
+	return n.wait(timeout, func() error {
+		for _, node := range n.selectNodes(nodes) {
+			if head := node.Head().Number.Uint64(); head < number {
+				return fmt.Errorf("node %x at block %d, want %d", node.Signer, head, number)
+			}
+		}
+		return nil
+	})
+}
+
+// WaitSync waits until all the given nodes (all running ones if none given)
+// agree on the same head block.
+func (n *Network) WaitSync(timeout time.Duration, nodes ...int) error {
+	return n.wait(timeout, func() error {
+		var head *types.Header
+		for _, node := range n.selectNodes(nodes) {
+			if head == nil {
+				head = node.Head()
+				continue
+			}
+			if have := node.Head(); have.Hash() != head.Hash() {
+				return fmt.Errorf("node %x head #%d [%x] differs from #%d [%x]", node.Signer, have.Number, have.Hash().Bytes()[:4], head.Number, head.Hash().Bytes()[:4])
+			}
+		}
+		return nil
+	})
+}
+
+// selectNodes returns the running nodes amongst the given indices, or all the
+// running nodes if none given.
+func (n *Network) selectNodes(indices []int) []*Node {
+	var nodes []*Node
+	if len(indices) == 0 {
+		for _, node := range n.nodes {
+			if node.Alive() {
+				nodes = append(nodes, node)
+			}
+		}
+		return nodes
+	}
+	for _, i := range indices {
+		if n.nodes[i].Alive() {
+			nodes = append(nodes, n.nodes[i])
+		}
+	}
+	return nodes
+}
+
+// wait polls the condition until it's met or the timeout expires, returning the
+// last failure in the latter case.
+func (n *Network) wait(timeout time.Duration, cond func() error) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		err := cond()
+		if err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timeout: %w", err)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
@@ -0,0 +1,188 @@
+package poisim
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/consensus/poi"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// diffBackup is the difficulty of blocks sealed by the backup signer.
+const diffBackup = 3
+
+// newTestNetwork creates and starts a simulated network, shutting it down when
+// the test finishes.
+func newTestNetwork(t *testing.T, signers int, config *params.PoiConfig) *Network {
+	if testing.Short() {
+		t.Skip("skipping multi-node simulation in short mode")
+	}
+	net, err := NewNetwork(&Config{Signers: signers, Poi: config})
+	if err != nil {
+		t.Fatalf("failed to create network: %v", err)
+	}
+	t.Cleanup(net.Shutdown)
+
+	if err := net.Start(); err != nil {
+		t.Fatalf("failed to start network: %v", err)
+	}
+	return net
+}
+
+// sealers returns the signers of the canonical blocks in the given range.
+func sealers(t *testing.T, node *Node, from, to uint64) []*types.Header {
+	var headers []*types.Header
+	for number := from; number <= to; number++ {
+		header := node.Chain().GetHeaderByNumber(number)
+		if header == nil {
+			t.Fatalf("missing canonical header #%d", number)
+		}
+		headers = append(headers, header)
+	}
+	return headers
+}
+
+// Tests that a network of healthy signers keeps producing blocks, every node
+// ends up on the same chain and no signer seals more often than allowed.
+func TestLiveness(t *testing.T) {
+	net := newTestNetwork(t, 3, &params.PoiConfig{Period: 1, Epoch: 30000})
+
+	if err := net.WaitHeight(6, 30*time.Second); err != nil {
+		t.Fatalf("network stalled: %v", err)
+	}
+	if err := net.WaitSync(10 * time.Second); err != nil {
+		t.Fatalf("nodes didn't converge: %v", err)
+	}
+	engine := poi.New(net.genesis.Config.Poi, nil)
+
+	headers := sealers(t, net.Node(0), 1, 6)
+	for i, header := range headers {
+		signer, err := engine.Author(header)
+		if err != nil {
+			t.Fatalf("block #%d: failed to recover signer: %v", header.Number, err)
+		}
+		if i > 0 {
+			if prev, _ := engine.Author(headers[i-1]); prev == signer {
+				t.Errorf("block #%d: signer %x sealed consecutive blocks", header.Number, signer)
+			}
+		}
+	}
+}
+
+// Tests that the chain keeps going through the slots of a crashed signer by the
+// designated backup signer sealing them once the backup timeout passes.
+func TestBackupSealing(t *testing.T) {
+	net := newTestNetwork(t, 3, &params.PoiConfig{Period: 1, Epoch: 30000, BackupTimeout: 1})
+
+	if err := net.WaitHeight(2, 30*time.Second); err != nil {
+		t.Fatalf("network stalled: %v", err)
+	}
+	if err := net.Kill(0); err != nil {
+		t.Fatalf("failed to kill node: %v", err)
+	}
+	start := net.Node(1).Head().Number.Uint64()
+	if err := net.WaitHeight(start+6, 60*time.Second); err != nil {
+		t.Fatalf("network stalled after signer crash: %v", err)
+	}
+	if err := net.WaitSync(10 * time.Second); err != nil {
+		t.Fatalf("nodes didn't converge: %v", err)
+	}
+	var backups int
+	for _, header := range sealers(t, net.Node(1), start+1, start+6) {
+		if header.Difficulty.Uint64() == diffBackup {
+			backups++
+		}
+	}
+	if backups == 0 {
+		t.Errorf("no backup blocks sealed in place of the crashed signer")
+	}
+}
+
+// Tests that a slow signer doesn't stall the chain, its slots being taken over
+// by the backup signer, and that all nodes agree on the outcome.
+func TestDelayedSigner(t *testing.T) {
+	net := newTestNetwork(t, 3, &params.PoiConfig{Period: 1, Epoch: 30000, BackupTimeout: 1})
+	net.Delay(0, 4*time.Second)
+
+	if err := net.WaitHeight(9, 60*time.Second); err != nil {
+		t.Fatalf("network stalled: %v", err)
+	}
+	net.Delay(0, 0)
+	if err := net.WaitSync(20 * time.Second); err != nil {
+		t.Fatalf("nodes didn't converge: %v", err)
+	}
+	var backups int
+	for _, header := range sealers(t, net.Node(1), 1, 9) {
+		if header.Difficulty.Uint64() == diffBackup {
+			backups++
+		}
+	}
+	if backups == 0 {
+		t.Errorf("no backup blocks sealed in place of the slow signer")
+	}
+}
+
+// Tests that after a network partition heals, the minority side reorgs onto the
+// heavier chain of the majority side.
+func TestPartitionForkChoice(t *testing.T) {
+	net := newTestNetwork(t, 5, &params.PoiConfig{Period: 1, Epoch: 30000})
+
+	if err := net.WaitHeight(2, 30*time.Second); err != nil {
+		t.Fatalf("network stalled: %v", err)
+	}
+	if err := net.Partition([]int{0, 1, 2}, []int{3, 4}); err != nil {
+		t.Fatalf("failed to partition network: %v", err)
+	}
+	// The majority side keeps sealing, the minority one stalls on the recent
+	// signer limit after a few blocks
+	split := net.Node(0).Head().Number.Uint64()
+	if err := net.WaitHeight(split+6, 60*time.Second, 0, 1, 2); err != nil {
+		t.Fatalf("majority stalled: %v", err)
+	}
+	if head := net.Node(3).Head().Number.Uint64(); head >= split+6 {
+		t.Errorf("minority progressed too far: have #%d, majority at #%d", head, split+6)
+	}
+	majority := net.Node(0).Chain().GetHeaderByNumber(split + 6)
+
+	if err := net.Heal(); err != nil {
+		t.Fatalf("failed to heal network: %v", err)
+	}
+	if err := net.WaitHeight(split+8, 60*time.Second); err != nil {
+		t.Fatalf("network stalled after healing: %v", err)
+	}
+	if err := net.WaitSync(20 * time.Second); err != nil {
+		t.Fatalf("nodes didn't converge: %v", err)
+	}
+	for i, node := range net.Nodes() {
+		if hash := node.Chain().GetCanonicalHash(majority.Number.Uint64()); hash != majority.Hash() {
+			t.Errorf("node %d: canonical block #%d mismatch: have %x, want %x", i, majority.Number, hash, majority.Hash())
+		}
+	}
+}
+
+// Tests that the signers attest to the blocks they see and every node advances
+// its finalized block from the gossiped attestations.
+func TestFinalization(t *testing.T) {
+	net := newTestNetwork(t, 3, &params.PoiConfig{Period: 1, Epoch: 30000})
+
+	if err := net.WaitHeight(6, 30*time.Second); err != nil {
+		t.Fatalf("network stalled: %v", err)
+	}
+	err := net.wait(20*time.Second, func() error {
+		for i, node := range net.Nodes() {
+			final := node.Chain().CurrentFinalBlock()
+			if final == nil || final.Number.Uint64() == 0 {
+				return fmt.Errorf("node %d: no finalized block", i)
+			}
+			if hash := node.Chain().GetCanonicalHash(final.Number.Uint64()); hash != final.Hash() {
+				return fmt.Errorf("node %d: finalized block #%d not canonical", i, final.Number)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("blocks not finalized: %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package poi
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// PruneSnapshots deletes the PoI snapshots stored in the database that are more
+// than depth blocks below the current head and aren't needed any more to avoid
+// replaying headers from genesis. Snapshots at canonical checkpoint intervals are
+// kept, everything else (side chains, epoch checkpoints) is dropped. The number
+// of deleted snapshots is returned.
+func PruneSnapshots(db ethdb.Database, depth uint64) (int, error) {
+	head := rawdb.ReadHeadHeaderHash(db)
+	if head == (common.Hash{}) {
+		return 0, errors.New("missing head header")
+	}
+	number := rawdb.ReadHeaderNumber(db, head)
+	if number == nil {
+		return 0, errors.New("missing head header number")
+	}
+	if *number < depth {
+		return 0, nil
+	}
+	limit := *number - depth
+
+	var (
+		it     = db.NewIterator(rawdb.PoiSnapshotPrefix, nil)
+		batch  = db.NewBatch()
+		pruned int
+	)
+	defer it.Release()
+
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(rawdb.PoiSnapshotPrefix)+common.HashLength {
+			continue
+		}
+		snap, _, err := decodeSnapshot(it.Value())
+		if err != nil {
+			log.Warn("Skipping undecodable PoI snapshot", "key", common.Bytes2Hex(key), "err", err)
+			continue
+		}
+		if snap.Number > limit {
+			continue
+		}
+		if snap.Number%checkpointInterval == 0 && rawdb.ReadCanonicalHash(db, snap.Number) == snap.Hash {
+			continue
+		}
+		if err := batch.Delete(key); err != nil {
+			return pruned, err
+		}
+		pruned++
+
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return pruned, err
+			}
+			batch.Reset()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return pruned, err
+	}
+	if err := batch.Write(); err != nil {
+		return pruned, err
+	}
+	return pruned, nil
+}
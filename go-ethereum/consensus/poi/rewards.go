@@ -0,0 +1,73 @@
+package poi
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/holiman/uint256"
+)
+
+// hasRewards returns whether the reward policy in effect at the given header
+// credits anything to the signers.
+func (c *Poi) hasRewards(header *types.Header) bool {
+	config := c.config.At(header.Number.Uint64())
+	return config.BlockReward != nil || config.InTurnBonus != nil || config.ShareBaseFee
+}
+
+// blockReward returns the amount minted to the signer of the given header, the
+// in-turn bonus being added on top of the block reward for in-turn blocks.
+func (c *Poi) blockReward(header *types.Header) *uint256.Int {
+	config := c.config.At(header.Number.Uint64())
+
+	reward := new(uint256.Int)
+	if config.BlockReward != nil && config.BlockReward.Sign() > 0 {
+		reward.SetFromBig(config.BlockReward)
+	}
+	if config.InTurnBonus != nil && config.InTurnBonus.Sign() > 0 && header.Difficulty.Cmp(diffInTurn) == 0 {
+		bonus, _ := uint256.FromBig(config.InTurnBonus)
+		reward.Add(reward, bonus)
+	}
+	return reward
+}
+
+// accumulateRewards credits the block reward to the signer of the header and,
+// if enabled, splits the base fee paid in the block evenly among the active
+// signers instead of burning it. The remainder of the split goes to the signer
+// of the block. Everything is derived from the header and the parent snapshot,
+// so all nodes end up with the same state. If the parent snapshot can't be
+// retrieved, nothing is credited and the error is returned, as crediting the
+// fees some other way would make the state depend on the local node.
+func (c *Poi) accumulateRewards(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, signer common.Address) error {
+	reward := c.blockReward(header)
+
+	if c.config.At(header.Number.Uint64()).ShareBaseFee && header.BaseFee != nil && header.GasUsed > 0 {
+		fees, _ := uint256.FromBig(new(big.Int).Mul(header.BaseFee, new(big.Int).SetUint64(header.GasUsed)))
+
+		snap, err := c.snapshot(chain, header.Number.Uint64()-1, header.ParentHash, nil)
+		if err != nil {
+			return err
+		}
+		signers := snap.GetActiveSigners()
+		if len(signers) == 0 {
+			signers = snap.signers()
+		}
+		if len(signers) == 0 {
+			reward.Add(reward, fees)
+		} else {
+			share := new(uint256.Int).Div(fees, uint256.NewInt(uint64(len(signers))))
+			if !share.IsZero() {
+				for _, account := range signers {
+					state.AddBalance(account, share)
+				}
+			}
+			reward.Add(reward, new(uint256.Int).Mod(fees, uint256.NewInt(uint64(len(signers)))))
+		}
+	}
+	if !reward.IsZero() {
+		state.AddBalance(signer, reward)
+	}
+	return nil
+}
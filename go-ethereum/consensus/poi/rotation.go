@@ -0,0 +1,227 @@
+package poi
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+const (
+	// maxRotations is the maximum number of signer rotations a single header may
+	// carry.
+	maxRotations = 4
+
+	// rotationTag separates the signed handover from any other signed message.
+	rotationTag = "poi-rotation"
+)
+
+var (
+	// errInvalidRotation is returned if a signer rotation is malformed or its
+	// signature can't be recovered.
+	errInvalidRotation = errors.New("invalid signer rotation")
+
+	// errStaleRotation is returned if a signer rotation is submitted that can't
+	// be applied anymore, because it expired, the old key is not a signer or the
+	// new one already is.
+	errStaleRotation = errors.New("stale signer rotation")
+
+	// errGovernanceRotation is returned if a header carries a signer rotation
+	// while the signer set is managed by the governance contract.
+	errGovernanceRotation = errors.New("signer rotation under contract governance")
+)
+
+// Successor is the key a signer handed its identity over to, along with the block
+// the handover was applied in. It's kept for an epoch, so that evidence of an
+// offence committed with the old key still punishes the signer.
+type Successor struct {
+	Signer common.Address `json:"signer"` // Key that took over the identity
+	Number uint64         `json:"number"` // Block number the handover was applied in
+}
+
+// Rotation is the handover of a signer's identity to a new key, signed by the
+// key being replaced. Once applied, the new key takes over the health,
+// performance and recent blocks of the old one.
+type Rotation struct {
+	Signer    common.Address `json:"signer"`    // New key taking over the identity
+	Number    uint64         `json:"number"`    // Block number the handover was signed at, expiring it after an epoch
+	Signature []byte         `json:"signature"` // Signature of the old key over the handover
+}
+
+// RotationRLP returns the rlp bytes which need to be signed by the old key to
+// hand its identity over to a new one. The signature is made over the keccak256
+// hash of the bytes.
+func RotationRLP(signer common.Address, number uint64) []byte {
+	blob, err := rlp.EncodeToBytes([]interface{}{rotationTag, signer, number})
+	if err != nil {
+		panic("can't encode: " + err.Error())
+	}
+	return blob
+}
+
+// Hash returns the hash identifying the rotation.
+func (r *Rotation) Hash() common.Hash {
+	return crypto.Keccak256Hash(RotationRLP(r.Signer, r.Number), r.Signature)
+}
+
+// sanityCheck verifies the parts of the rotation that can be checked without
+// recovering the signature.
+func (r *Rotation) sanityCheck() error {
+	if r.Signer == (common.Address{}) || len(r.Signature) != crypto.SignatureLength {
+		return errInvalidRotation
+	}
+	return nil
+}
+
+// previous verifies the rotation and returns the key handing its identity over.
+func (r *Rotation) previous() (common.Address, error) {
+	if err := r.sanityCheck(); err != nil {
+		return common.Address{}, err
+	}
+	pubkey, err := crypto.Ecrecover(crypto.Keccak256(RotationRLP(r.Signer, r.Number)), r.Signature)
+	if err != nil {
+		return common.Address{}, errInvalidRotation
+	}
+	var signer common.Address
+	copy(signer[:], crypto.Keccak256(pubkey[1:])[12:])
+
+	if signer == r.Signer {
+		return common.Address{}, errInvalidRotation
+	}
+	return signer, nil
+}
+
+// validRotation returns whether a verified rotation from the given key may still
+// be applied on top of the snapshot by a header of the given number. Rotations
+// expire after an epoch and the new key can't already be a signer. Keys that
+// handed their identity over within the last epoch can neither do so again, nor
+// take one over, so a rotation can't be replayed if the old key is re-added.
+func (s *Snapshot) validRotation(previous common.Address, rotation *Rotation, number uint64) bool {
+	if _, ok := s.Signers[previous]; !ok {
+		return false
+	}
+	if _, ok := s.Signers[rotation.Signer]; ok {
+		return false
+	}
+	if _, ok := s.Successors[previous]; ok {
+		return false
+	}
+	if _, ok := s.Successors[rotation.Signer]; ok {
+		return false
+	}
+	return rotation.Number < number && rotation.Number+s.config.Epoch > number
+}
+
+// rotate hands the identity of a signer over to a new key: its authorization,
+// status, recent blocks and the votes it cast move over, as do the votes cast
+// on it. Votes on the new key are discarded, it's a signer now.
+func (s *Snapshot) rotate(previous common.Address, signer common.Address, number uint64) {
+	// Discard any votes around the new key before it takes over
+	for i := 0; i < len(s.Votes); i++ {
+		if s.Votes[i].Address == signer {
+			s.Votes = append(s.Votes[:i], s.Votes[i+1:]...)
+			i--
+		}
+	}
+	delete(s.Tally, signer)
+	s.dropStatusVotes(func(vote *StatusVote) bool {
+		return vote.Address == signer
+	})
+	// Move the authorization and the status of the signer over
+	delete(s.Signers, previous)
+	s.Signers[signer] = struct{}{}
+
+	if health, ok := s.Health[previous]; ok {
+		s.Health[signer] = health
+		delete(s.Health, previous)
+	}
+	if perf, ok := s.Performance[previous]; ok {
+		s.Performance[signer] = perf
+		delete(s.Performance, previous)
+	}
+	if recovery, ok := s.Recoveries[previous]; ok {
+		s.Recoveries[signer] = recovery
+		delete(s.Recoveries, previous)
+	}
+	if failures, ok := s.Failures[previous]; ok {
+		s.Failures[signer] = failures
+		delete(s.Failures, previous)
+	}
+	if offence, ok := s.Slashed[previous]; ok {
+		s.Slashed[signer] = offence
+		delete(s.Slashed, previous)
+	}
+	s.Successors[previous] = Successor{Signer: signer, Number: number}
+
+	for i, slot := range s.Rotation {
+		if slot == previous {
+			s.Rotation[i] = signer
+		}
+	}
+	// The new key may not seal any sooner than the old one could have
+	for number, recent := range s.Recents {
+		if recent == previous {
+			s.Recents[number] = signer
+		}
+	}
+	// Carry over the votes cast by and on the signer. Votes are shared between
+	// snapshot copies, so they're replaced instead of modified.
+	for i, vote := range s.Votes {
+		if vote.Signer == previous || vote.Address == previous {
+			moved := *vote
+			if moved.Signer == previous {
+				moved.Signer = signer
+			}
+			if moved.Address == previous {
+				moved.Address = signer
+			}
+			s.Votes[i] = &moved
+		}
+	}
+	if tally, ok := s.Tally[previous]; ok {
+		s.Tally[signer] = tally
+		delete(s.Tally, previous)
+	}
+	for i, vote := range s.StatusVotes {
+		if vote.Signer == previous || vote.Address == previous {
+			moved := *vote
+			if moved.Signer == previous {
+				moved.Signer = signer
+			}
+			if moved.Address == previous {
+				moved.Address = signer
+			}
+			s.StatusVotes[i] = &moved
+		}
+	}
+	for i, vote := range s.DeviceVotes {
+		if vote.Signer == previous {
+			moved := *vote
+			moved.Signer = signer
+			s.DeviceVotes[i] = &moved
+		}
+	}
+}
+
+// successor resolves the key currently holding the identity of the signer that
+// used the given key at the given height, following the handovers applied since.
+func (s *Snapshot) successor(signer common.Address, number uint64) common.Address {
+	for {
+		successor, ok := s.Successors[signer]
+		if !ok || number >= successor.Number {
+			return signer
+		}
+		signer = successor.Signer
+	}
+}
+
+// pruneSuccessors forgets about handovers no rotation or evidence can refer to
+// anymore.
+func (s *Snapshot) pruneSuccessors(number uint64) {
+	for previous, successor := range s.Successors {
+		if successor.Number+s.config.Epoch <= number {
+			delete(s.Successors, previous)
+		}
+	}
+}
@@ -2,7 +2,8 @@ package poi
 
 import (
 	"bytes"
-	"encoding/json"
+	"encoding/binary"
+	"math/big"
 	"sort"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/ethereum/go-ethereum/common/lru"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
@@ -42,6 +44,13 @@ const (
 	Unhealthy SignerHealth = 1
 )
 
+// Recovery tracks the progress of an unhealthy signer towards becoming healthy
+// again.
+type Recovery struct {
+	Since  uint64 `json:"since"`  // Timestamp of the block the signer was marked unhealthy in
+	Sealed uint64 `json:"sealed"` // Number of blocks sealed since being marked unhealthy
+}
+
 // Snapshot is the state of the authorization voting at a given point in time.
 type Snapshot struct {
 	config   *params.PoiConfig // Consensus engine parameters to fine tune behavior
@@ -53,8 +62,16 @@ type Snapshot struct {
 	Recents     map[uint64]common.Address     `json:"recents"`     // Set of recent signers for spam protections
 	Votes       []*Vote                       `json:"votes"`       // List of votes cast in chronological order
 	Tally       map[common.Address]Tally      `json:"tally"`       // Current vote tally to avoid recalculating
+	StatusVotes []*StatusVote                 `json:"statusVotes"` // List of signer status votes cast in chronological order
 	Health      map[common.Address]SignerHealth `json:"health"`      // Health status of each signer
 	Performance map[common.Address]int64      `json:"performance"` // Performance metric for each signer
+	Recoveries  map[common.Address]Recovery   `json:"recoveries"`  // Recovery progress of each unhealthy signer
+	Devices     map[common.Address]Device     `json:"devices"`     // Device identity registry
+	DeviceVotes []*DeviceVote                 `json:"deviceVotes"` // List of device votes cast in chronological order
+	Slashed     map[common.Address]uint64     `json:"slashed"`     // Height of the last punished equivocation of each signer
+	Failures    map[common.Address]int        `json:"failures"`    // Consecutive in-turn slots missed by each signer
+	Rotation    []common.Address              `json:"rotation"`    // Weighted rotation of the epoch, fixed at its checkpoint
+	Successors  map[common.Address]Successor  `json:"successors"`  // Keys that handed their identity over within the last epoch
 }
 
 // newSnapshot creates a new snapshot with the specified startup parameters. This
@@ -71,6 +88,11 @@ func newSnapshot(config *params.PoiConfig, sigcache *sigLRU, number uint64, hash
 		Tally:       make(map[common.Address]Tally),
 		Health:      make(map[common.Address]SignerHealth),
 		Performance: make(map[common.Address]int64),
+		Recoveries:  make(map[common.Address]Recovery),
+		Devices:     make(map[common.Address]Device),
+		Slashed:     make(map[common.Address]uint64),
+		Failures:    make(map[common.Address]int),
+		Successors:  make(map[common.Address]Successor),
 	}
 	for _, signer := range signers {
 		snap.Signers[signer] = struct{}{}
@@ -80,25 +102,55 @@ func newSnapshot(config *params.PoiConfig, sigcache *sigLRU, number uint64, hash
 	return snap
 }
 
-// loadSnapshot loads an existing snapshot from the database.
-func loadSnapshot(config *params.PoiConfig, sigcache *sigLRU, db ethdb.Database, hash common.Hash) (*Snapshot, error) {
+// loadSnapshot loads an existing snapshot from the database. The returned flag
+// reports whether the snapshot is still in the legacy JSON encoding, it's up to
+// the caller to store it again in the binary one.
+func loadSnapshot(config *params.PoiConfig, sigcache *sigLRU, db ethdb.Database, hash common.Hash) (*Snapshot, bool, error) {
 	blob, err := db.Get(append(rawdb.PoiSnapshotPrefix, hash[:]...))
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
-	snap := new(Snapshot)
-	if err := json.Unmarshal(blob, snap); err != nil {
-		return nil, err
+	snap, legacy, err := decodeSnapshot(blob)
+	if err != nil {
+		return nil, false, err
 	}
 	snap.config = config
 	snap.sigcache = sigcache
 
-	return snap, nil
+	// Snapshots stored before health tracking was introduced lack the maps
+	if snap.Health == nil {
+		snap.Health = make(map[common.Address]SignerHealth)
+		for signer := range snap.Signers {
+			snap.Health[signer] = Healthy
+		}
+	}
+	if snap.Performance == nil {
+		snap.Performance = make(map[common.Address]int64)
+	}
+	if snap.Recoveries == nil {
+		snap.Recoveries = make(map[common.Address]Recovery)
+	}
+	if snap.Devices == nil {
+		snap.Devices = make(map[common.Address]Device)
+	}
+	if snap.Slashed == nil {
+		snap.Slashed = make(map[common.Address]uint64)
+	}
+	if snap.Failures == nil {
+		snap.Failures = make(map[common.Address]int)
+	}
+	if snap.Successors == nil {
+		snap.Successors = make(map[common.Address]Successor)
+	}
+	if snap.Rotation == nil {
+		snap.updateRotation(snap.Number)
+	}
+	return snap, legacy, nil
 }
 
 // store inserts the snapshot into the database.
 func (s *Snapshot) store(db ethdb.Database) error {
-	blob, err := json.Marshal(s)
+	blob, err := encodeSnapshot(s)
 	if err != nil {
 		return err
 	}
@@ -116,8 +168,16 @@ func (s *Snapshot) copy() *Snapshot {
 		Recents:     make(map[uint64]common.Address),
 		Votes:       make([]*Vote, len(s.Votes)),
 		Tally:       make(map[common.Address]Tally),
+		StatusVotes: make([]*StatusVote, len(s.StatusVotes)),
 		Health:      make(map[common.Address]SignerHealth),
 		Performance: make(map[common.Address]int64),
+		Recoveries:  make(map[common.Address]Recovery),
+		Devices:     make(map[common.Address]Device),
+		DeviceVotes: make([]*DeviceVote, len(s.DeviceVotes)),
+		Slashed:     make(map[common.Address]uint64),
+		Failures:    make(map[common.Address]int),
+		Rotation:    slices.Clone(s.Rotation),
+		Successors:  make(map[common.Address]Successor),
 	}
 	for signer := range s.Signers {
 		cpy.Signers[signer] = struct{}{}
@@ -134,7 +194,24 @@ func (s *Snapshot) copy() *Snapshot {
 	for address, perf := range s.Performance {
 		cpy.Performance[address] = perf
 	}
+	for address, recovery := range s.Recoveries {
+		cpy.Recoveries[address] = recovery
+	}
+	for address, device := range s.Devices {
+		cpy.Devices[address] = device
+	}
+	for offender, offence := range s.Slashed {
+		cpy.Slashed[offender] = offence
+	}
+	for address, failures := range s.Failures {
+		cpy.Failures[address] = failures
+	}
+	for previous, successor := range s.Successors {
+		cpy.Successors[previous] = successor
+	}
 	copy(cpy.Votes, s.Votes)
+	copy(cpy.StatusVotes, s.StatusVotes)
+	copy(cpy.DeviceVotes, s.DeviceVotes)
 
 	return cpy
 }
@@ -183,6 +260,97 @@ func (s *Snapshot) uncast(address common.Address, authorize bool) bool {
 	return true
 }
 
+// statusValue returns the current value of the given attribute of a signer in
+// the same representation that status votes carry it.
+func (s *Snapshot) statusValue(kind StatusKind, address common.Address) uint64 {
+	switch kind {
+	case statusHealth:
+		return uint64(s.Health[address])
+	case statusPerformance:
+		return uint64(s.Performance[address])
+	}
+	return 0
+}
+
+// validStatusVote returns whether it makes sense to cast the specified status
+// vote in the given snapshot context (e.g. don't try to mark an already unhealthy
+// signer unhealthy).
+func (s *Snapshot) validStatusVote(kind StatusKind, address common.Address, value uint64) bool {
+	if _, ok := s.Signers[address]; !ok {
+		return false
+	}
+	return s.statusValue(kind, address) != value
+}
+
+// statusTally returns the number of votes cast for setting the given attribute
+// of a signer to the specified value.
+func (s *Snapshot) statusTally(kind StatusKind, address common.Address, value uint64) int {
+	var votes int
+	for _, vote := range s.StatusVotes {
+		if vote.Kind == kind && vote.Address == address && vote.Value == value {
+			votes++
+		}
+	}
+	return votes
+}
+
+// setStatus updates the given attribute of a signer to the voted value. The time
+// is the timestamp of the header the vote passed in, starting the recovery of a
+// signer marked unhealthy.
+func (s *Snapshot) setStatus(kind StatusKind, address common.Address, value uint64, time uint64) {
+	switch kind {
+	case statusHealth:
+		s.Health[address] = SignerHealth(value)
+		if SignerHealth(value) == Unhealthy {
+			// Start counting afresh, the signer may recover and fail again later
+			delete(s.Failures, address)
+			s.Recoveries[address] = Recovery{Since: time}
+		} else {
+			delete(s.Recoveries, address)
+		}
+	case statusPerformance:
+		s.Performance[address] = int64(value)
+	}
+}
+
+// dropStatusVotes discards all the status votes matching the filter.
+func (s *Snapshot) dropStatusVotes(drop func(vote *StatusVote) bool) {
+	for i := 0; i < len(s.StatusVotes); i++ {
+		if drop(s.StatusVotes[i]) {
+			s.StatusVotes = append(s.StatusVotes[:i], s.StatusVotes[i+1:]...)
+			i--
+		}
+	}
+}
+
+// applyStatusVote tallies up a status vote cast by a signer and updates the
+// voted attribute once a majority of the signers agree on the new value.
+func (s *Snapshot) applyStatusVote(signer common.Address, header *types.Header, kind StatusKind, address common.Address, value uint64) {
+	number := header.Number.Uint64()
+
+	// Header authorized, discard any previous status vote from the signer
+	s.dropStatusVotes(func(vote *StatusVote) bool {
+		return vote.Signer == signer && vote.Kind == kind && vote.Address == address
+	})
+	if !s.validStatusVote(kind, address, value) {
+		return
+	}
+	s.StatusVotes = append(s.StatusVotes, &StatusVote{
+		Signer:  signer,
+		Block:   number,
+		Address: address,
+		Kind:    kind,
+		Value:   value,
+	})
+	// If the vote passed, update the status and discard votes around it
+	if s.statusTally(kind, address, value) > len(s.Signers)/2 {
+		s.setStatus(kind, address, value, header.Time)
+		s.dropStatusVotes(func(vote *StatusVote) bool {
+			return vote.Kind == kind && vote.Address == address
+		})
+	}
+}
+
 // apply creates a new authorization snapshot by applying the given headers to
 // the original one.
 func (s *Snapshot) apply(headers []*types.Header) (*Snapshot, error) {
@@ -207,12 +375,23 @@ func (s *Snapshot) apply(headers []*types.Header) (*Snapshot, error) {
 		logged = time.Now()
 	)
 	for i, header := range headers {
-		// Remove any votes on checkpoint blocks
+		// Resolve the signer expected to seal the block in-turn before the recent
+		// signers of the parent are touched
 		number := header.Number.Uint64()
+		inturn, hasInturn := snap.inturnSigner(number)
+		allowed := !snap.recentlySigned(number, inturn)
+
+		// Remove any votes on checkpoint blocks
 		if number%s.config.Epoch == 0 {
 			snap.Votes = nil
 			snap.Tally = make(map[common.Address]Tally)
+			snap.StatusVotes = nil
+			snap.DeviceVotes = nil
 		}
+		// Forget about offences no evidence can be applied for anymore
+		snap.pruneSlashed(number)
+		snap.pruneSuccessors(number)
+
 		// Delete the oldest signer from the recent list to allow it signing again
 		if limit := uint64(len(snap.Signers)/2 + 1); number >= limit {
 			delete(snap.Recents, number-limit)
@@ -232,6 +411,11 @@ func (s *Snapshot) apply(headers []*types.Header) (*Snapshot, error) {
 		}
 		snap.Recents[number] = signer
 
+		// Count a missed slot against the in-turn signer if someone else sealed,
+		// unless the in-turn signer wasn't allowed to seal the block at all
+		if hasInturn && allowed {
+			snap.trackFailure(inturn, signer)
+		}
 		// Header authorized, discard any previous votes from the signer
 		for i, vote := range snap.Votes {
 			if vote.Signer == signer && vote.Address == header.Coinbase {
@@ -261,6 +445,65 @@ func (s *Snapshot) apply(headers []*types.Header) (*Snapshot, error) {
 				Authorize: authorize,
 			})
 		}
+		// Return any unhealthy signer that recovered to the active signer pool
+		snap.recover(number, signer, header.Time)
+
+		// Tally up the status vote carried in the mix digest, if any
+		kind, target, value, err := decodeStatusVote(header.MixDigest)
+		if err != nil {
+			return nil, err
+		}
+		if kind != statusNone {
+			snap.applyStatusVote(signer, header, kind, target, value)
+		}
+		// Tally up the device votes, punish the equivocations, hand signer keys
+		// over and track the telemetry carried in the header extension, if any
+		if number%s.config.Epoch != 0 {
+			ext, err := decodeExtension(header)
+			if err != nil {
+				return nil, err
+			}
+			if ext != nil {
+				for _, vote := range ext.Devices {
+					snap.castDevice(signer, number, vote)
+				}
+				for _, evidence := range ext.Evidence {
+					offender, err := evidence.offender(s.sigcache)
+					if err != nil {
+						return nil, err
+					}
+					if snap.validEvidence(offender, evidence.Number(), number) {
+						snap.slash(offender, evidence.Number(), header)
+					}
+				}
+				for _, rotation := range ext.Rotations {
+					previous, err := rotation.previous()
+					if err != nil {
+						return nil, err
+					}
+					if snap.validRotation(previous, rotation, number) {
+						snap.rotate(previous, rotation.Signer, number)
+						if previous == signer {
+							signer = rotation.Signer
+						}
+					}
+				}
+				// Contract governance sets the performance as signer weights
+				if ext.Telemetry != nil && s.config.Telemetry && s.config.Governance == nil {
+					if _, ok := snap.Signers[signer]; ok {
+						snap.applyTelemetry(signer, ext.Telemetry)
+					}
+				}
+			}
+		}
+		// Under contract governance, checkpoints replace the list of signers
+		if number%s.config.Epoch == 0 && s.config.Governance != nil {
+			signers, weights, err := checkpointSigners(s.config, header)
+			if err != nil {
+				return nil, err
+			}
+			snap.applyGovernance(signers, weights, number)
+		}
 		// If the vote passed, update the list of signers
 		if tally := snap.Tally[header.Coinbase]; tally.Votes > len(snap.Signers)/2 {
 			if tally.Authorize {
@@ -268,26 +511,7 @@ func (s *Snapshot) apply(headers []*types.Header) (*Snapshot, error) {
 				snap.Health[header.Coinbase] = Healthy       // New signers start as healthy
 				snap.Performance[header.Coinbase] = 0        // New signers start with 0 performance
 			} else {
-				delete(snap.Signers, header.Coinbase)
-				delete(snap.Health, header.Coinbase)         // Remove health tracking
-				delete(snap.Performance, header.Coinbase)    // Remove performance tracking
-
-				// Signer list shrunk, delete any leftover recent caches
-				if limit := uint64(len(snap.Signers)/2 + 1); number >= limit {
-					delete(snap.Recents, number-limit)
-				}
-				// Discard any previous votes the deauthorized signer cast
-				for i := 0; i < len(snap.Votes); i++ {
-					if snap.Votes[i].Signer == header.Coinbase {
-						// Uncast the vote from the cached tally
-						snap.uncast(snap.Votes[i].Address, snap.Votes[i].Authorize)
-
-						// Uncast the vote from the chronological list
-						snap.Votes = append(snap.Votes[:i], snap.Votes[i+1:]...)
-
-						i--
-					}
-				}
+				snap.deauthorize(header.Coinbase, number)
 			}
 			// Discard any previous votes around the just changed account
 			for i := 0; i < len(snap.Votes); i++ {
@@ -298,6 +522,10 @@ func (s *Snapshot) apply(headers []*types.Header) (*Snapshot, error) {
 			}
 			delete(snap.Tally, header.Coinbase)
 		}
+		// Fix the weighted rotation of the new epoch on checkpoint blocks
+		if number%s.config.Epoch == 0 {
+			snap.updateRotation(number)
+		}
 		// If we're taking too much time (ecrecover), notify the user once a while
 		if time.Since(logged) > 8*time.Second {
 			log.Info("Reconstructing voting history", "processed", i, "total", len(headers), "elapsed", common.PrettyDuration(time.Since(start)))
@@ -313,6 +541,150 @@ func (s *Snapshot) apply(headers []*types.Header) (*Snapshot, error) {
 	return snap, nil
 }
 
+// trackFailure counts a missed in-turn slot against the in-turn signer of a block
+// sealed by someone else, and resets the count of an in-turn signer that sealed
+// its own slot.
+func (s *Snapshot) trackFailure(inturn common.Address, signer common.Address) {
+	if inturn == signer {
+		delete(s.Failures, signer)
+		return
+	}
+	s.Failures[inturn]++
+}
+
+// failing returns the healthy signers that missed at least HealthThreshold
+// in-turn slots in a row, sorted by address. They are due to be voted unhealthy
+// in the block at the given height.
+func (s *Snapshot) failing(number uint64) []common.Address {
+	threshold := s.config.At(number).HealthThreshold
+	if threshold <= 0 {
+		return nil
+	}
+	var signers []common.Address
+	for _, signer := range sortedKeys(s.Failures) {
+		if s.Failures[signer] >= threshold && s.Health[signer] == Healthy {
+			signers = append(signers, signer)
+		}
+	}
+	return signers
+}
+
+// deauthorize removes a signer from the authorized set along with all the state
+// tracked about it and all the votes it cast.
+func (s *Snapshot) deauthorize(signer common.Address, number uint64) {
+	delete(s.Signers, signer)
+	delete(s.Health, signer)      // Remove health tracking
+	delete(s.Performance, signer) // Remove performance tracking
+	delete(s.Recoveries, signer)  // Remove recovery tracking
+	delete(s.Failures, signer)    // Remove failure tracking
+
+	// Signer list shrunk, delete any leftover recent caches
+	if limit := uint64(len(s.Signers)/2 + 1); number >= limit {
+		delete(s.Recents, number-limit)
+	}
+	// Discard any previous votes the deauthorized signer cast
+	for i := 0; i < len(s.Votes); i++ {
+		if s.Votes[i].Signer == signer {
+			// Uncast the vote from the cached tally
+			s.uncast(s.Votes[i].Address, s.Votes[i].Authorize)
+
+			// Uncast the vote from the chronological list
+			s.Votes = append(s.Votes[:i], s.Votes[i+1:]...)
+
+			i--
+		}
+	}
+	// Discard any status votes cast by or about the deauthorized signer
+	s.dropStatusVotes(func(vote *StatusVote) bool {
+		return vote.Signer == signer || vote.Address == signer
+	})
+	// Discard any device votes the deauthorized signer cast
+	s.dropDeviceVotes(func(vote *DeviceVote) bool {
+		return vote.Signer == signer
+	})
+}
+
+// recover advances the recovery of the unhealthy signers after the block at the
+// given height, sealed by the given signer at the given time, marking healthy
+// every signer that has been unhealthy for at least the recovery period or that
+// sealed the configured number of blocks since. The rule only depends on the
+// headers, so every node returns the same signers to the active pool at the same
+// height.
+func (s *Snapshot) recover(number uint64, sealer common.Address, time uint64) {
+	config := s.config.At(number)
+	for signer := range s.Signers {
+		if s.IsHealthy(signer) {
+			continue
+		}
+		recovery, ok := s.Recoveries[signer]
+		if !ok {
+			// Unhealthy signer without recovery tracking, start it now
+			recovery = Recovery{Since: time}
+		}
+		if signer == sealer {
+			recovery.Sealed++
+		}
+		if (config.RecoveryPeriod > 0 && time >= recovery.Since+config.RecoveryPeriod) ||
+			(config.RecoveryBlocks > 0 && recovery.Sealed >= config.RecoveryBlocks) {
+			s.Health[signer] = Healthy
+			delete(s.Recoveries, signer)
+
+			// Discard any pending health votes, they were cast in a different context
+			s.dropStatusVotes(func(vote *StatusVote) bool {
+				return vote.Kind == statusHealth && vote.Address == signer
+			})
+			log.Debug("Signer recovered to healthy", "address", signer, "sealed", recovery.Sealed, "since", recovery.Since)
+			continue
+		}
+		s.Recoveries[signer] = recovery
+	}
+}
+
+// recovering returns whether a signer is unhealthy and can recover by sealing
+// the block at the given height. In backup mode such signers may seal out-of-turn,
+// as they're neither in-turn nor backup signers.
+func (s *Snapshot) recovering(number uint64, signer common.Address) bool {
+	if _, ok := s.Signers[signer]; !ok {
+		return false
+	}
+	return s.config.At(number).RecoveryBlocks > 0 && !s.IsHealthy(signer)
+}
+
+// Slot is the expected in-turn and backup signer of a single block.
+type Slot struct {
+	Number uint64          `json:"number"` // Block number of the slot
+	InTurn *common.Address `json:"inturn"` // Signer expected to seal the block, nil if none can
+	Backup *common.Address `json:"backup"` // Signer allowed to seal as backup, nil if none can
+}
+
+// schedule projects the in-turn and backup signers of the next count blocks on
+// top of the snapshot, assuming that every block is sealed by its in-turn signer
+// and no votes are cast in the meantime.
+func (s *Snapshot) schedule(count uint64) []*Slot {
+	snap := s.copy()
+
+	slots := make([]*Slot, 0, count)
+	for i := uint64(0); i < count; i++ {
+		number := snap.Number + 1
+
+		slot := &Slot{Number: number}
+		if inturn, ok := snap.inturnSigner(number); ok {
+			slot.InTurn = &inturn
+			if backup, ok := snap.GetBackupSigner(number, inturn); ok {
+				slot.Backup = &backup
+			}
+			// Shift the recent signers as if the in-turn signer sealed the block
+			if limit := uint64(len(snap.Signers)/2 + 1); number >= limit {
+				delete(snap.Recents, number-limit)
+			}
+			snap.Recents[number] = inturn
+		}
+		slots = append(slots, slot)
+		snap.Number = number
+	}
+	return slots
+}
+
 // signers retrieves the list of authorized signers in ascending order.
 func (s *Snapshot) signers() []common.Address {
 	sigs := make([]common.Address, 0, len(s.Signers))
@@ -325,11 +697,138 @@ func (s *Snapshot) signers() []common.Address {
 
 // inturn returns if a signer at a given block height is in-turn or not.
 func (s *Snapshot) inturn(number uint64, signer common.Address) bool {
-	signers, offset := s.GetActiveSigners(), 0
-	for offset < len(signers) && signers[offset] != signer {
-		offset++
+	inturn, ok := s.inturnSigner(number)
+	return ok && inturn == signer
+}
+
+// backupMode returns whether the no-turn backup process is enabled at the given
+// height, replacing random out-of-turn sealing with a single designated backup
+// signer.
+func (s *Snapshot) backupMode(number uint64) bool {
+	return s.config.At(number).BackupTimeout > 0
+}
+
+// recentlySigned returns whether a signer is amongst the recent signers that are
+// not allowed to seal the block at the given height.
+func (s *Snapshot) recentlySigned(number uint64, signer common.Address) bool {
+	for seen, recent := range s.Recents {
+		if recent == signer {
+			if limit := uint64(len(s.Signers)/2 + 1); number < limit || seen > number-limit {
+				return true
+			}
+		}
 	}
-	return (number % uint64(len(signers))) == uint64(offset)
+	return false
+}
+
+// inturnSigner returns the signer expected to seal the block at the given height
+// by rotating over the sorted active signers (or the weighted rotation if weighted
+// selection is enabled). In backup mode, signers that are not allowed to seal due
+// to having signed recently are skipped, otherwise the chain would stall on every
+// slot following a backup block. The second return value is false if there is no
+// signer able to seal in-turn.
+func (s *Snapshot) inturnSigner(number uint64) (common.Address, bool) {
+	signers := s.GetActiveSigners()
+	if s.config.WeightedSelection {
+		signers = s.weightedSigners(signers)
+	}
+	if len(signers) == 0 {
+		return common.Address{}, false
+	}
+	offset := int(number % uint64(len(signers)))
+	if !s.backupMode(number) {
+		return signers[offset], true
+	}
+	for i := 0; i < len(signers); i++ {
+		signer := signers[(offset+i)%len(signers)]
+		if !s.recentlySigned(number, signer) {
+			return signer, true
+		}
+	}
+	return common.Address{}, false
+}
+
+// weightedSlotsPerSigner is the average number of slots each active signer gets
+// in a weighted rotation, bounding the rotation length while keeping the slot
+// shares reasonably close to the performance ratios.
+const weightedSlotsPerSigner = 8
+
+// weightedSigners filters the weighted rotation of the epoch down to the given
+// active signers. Active signers missing from the rotation, since they were
+// authorized after the checkpoint, get a single slot each at the end of it.
+func (s *Snapshot) weightedSigners(signers []common.Address) []common.Address {
+	active := make(map[common.Address]bool, len(signers))
+	for _, signer := range signers {
+		active[signer] = false
+	}
+	rotation := make([]common.Address, 0, len(s.Rotation)+len(signers))
+	for _, signer := range s.Rotation {
+		if _, ok := active[signer]; ok {
+			rotation = append(rotation, signer)
+			active[signer] = true
+		}
+	}
+	for _, signer := range signers {
+		if !active[signer] {
+			rotation = append(rotation, signer)
+		}
+	}
+	return rotation
+}
+
+// updateRotation expands the authorized signers into the weighted rotation of
+// the epoch containing the given block. Every signer is allotted a number of
+// slots proportional to its performance (at least one, signers without any score
+// count as having a score of one) and the slots are shuffled with a seed derived
+// from the epoch number, so that the schedule only depends on the snapshot. The
+// rotation is only recomputed on checkpoints, so telemetry moving the scores
+// doesn't reshuffle the schedule every block.
+//
+// Note, a signer can't seal more often than the recent signer limit allows, so
+// its effective share is capped at 1/(len(signers)/2+1) of the blocks.
+func (s *Snapshot) updateRotation(number uint64) {
+	s.Rotation = nil
+	if !s.config.WeightedSelection || len(s.Signers) == 0 {
+		return
+	}
+	signers := s.signers()
+
+	// Normalize the performances into slot counts, using big integers to avoid
+	// overflowing on large scores
+	var (
+		weights = make([]*big.Int, len(signers))
+		total   = new(big.Int)
+		slots   = big.NewInt(int64(weightedSlotsPerSigner * len(signers)))
+	)
+	for i, signer := range signers {
+		weights[i] = big.NewInt(s.GetPerformance(signer))
+		if weights[i].Sign() <= 0 {
+			weights[i].SetInt64(1)
+		}
+		total.Add(total, weights[i])
+	}
+	var rotation []common.Address
+	for i, signer := range signers {
+		count := new(big.Int).Mul(weights[i], slots)
+		count.Div(count, total)
+		if count.Sign() == 0 {
+			count.SetInt64(1)
+		}
+		for j := int64(0); j < count.Int64(); j++ {
+			rotation = append(rotation, signer)
+		}
+	}
+	// Shuffle the slots deterministically with a keccak chain seeded by the epoch
+	var epoch [8]byte
+	binary.BigEndian.PutUint64(epoch[:], number/s.config.Epoch)
+	seed := crypto.Keccak256Hash(epoch[:])
+
+	for i := len(rotation) - 1; i > 0; i-- {
+		seed = crypto.Keccak256Hash(seed[:])
+		j := binary.BigEndian.Uint64(seed[:8]) % uint64(i+1)
+		rotation[i], rotation[j] = rotation[j], rotation[i]
+	}
+	s.Rotation = rotation
 }
 
 // MarkHealthy marks a signer as healthy
@@ -406,7 +905,8 @@ func (s *Snapshot) GetActiveSigners() []common.Address {
 
 // GetBackupSigner returns the next backup signer for the given block number
 // based on the sorted active signers pool. If the in-turn signer is not available,
-// this method returns the next healthy signer in the sorted list.
+// this method returns the next healthy signer in the sorted list. Signers that
+// signed recently are skipped as they would not be allowed to seal the block.
 func (s *Snapshot) GetBackupSigner(number uint64, inTurnSigner common.Address) (common.Address, bool) {
 	activeSigners := s.GetActiveSigners()
 	if len(activeSigners) == 0 {
@@ -422,12 +922,14 @@ func (s *Snapshot) GetBackupSigner(number uint64, inTurnSigner common.Address) (
 		}
 	}
 
-	// If in-turn signer is not in active list (unhealthy), start from beginning
-	if inTurnIndex == -1 {
-		return activeSigners[0], true
+	// Return the next eligible signer in the sorted list (wrap around if needed).
+	// If in-turn signer is not in active list (unhealthy), start from beginning.
+	for i := 1; i <= len(activeSigners); i++ {
+		signer := activeSigners[(inTurnIndex+i)%len(activeSigners)]
+		if signer == inTurnSigner || s.recentlySigned(number, signer) {
+			continue
+		}
+		return signer, true
 	}
-
-	// Return the next signer in the sorted list (wrap around if needed)
-	nextIndex := (inTurnIndex + 1) % len(activeSigners)
-	return activeSigners[nextIndex], true
+	return common.Address{}, false
 }
@@ -5,9 +5,11 @@ import (
 	"crypto/ecdsa"
 	"fmt"
 	"math/big"
+	"strings"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -78,14 +80,50 @@ type testerVote struct {
 	auth       bool
 	checkpoint []string
 	newbatch   bool
+	status     StatusKind       // Kind of signer status vote carried in the mix digest
+	target     string           // Signer the status vote is cast on
+	value      uint64           // Proposed value of the status vote
+	devices    []testerDevice   // Device votes carried in the header extension
+	evidence   []testerEvidence // Equivocation evidences carried in the header extension
+	telemetry  *telemetry       // Node telemetry carried in the header extension
+	rotations  []testerRotation // Signer key handovers carried in the header extension
+	extension  []byte           // Raw header extension, overriding the device votes and evidences
+}
+
+// testerEvidence represents two conflicting headers sealed at the same height.
+type testerEvidence struct {
+	signer string
+	second string // Signer of the second header, if different from the first
+	number uint64
+}
+
+// testerRotation represents a handover of a signer's identity to a new key,
+// signed by the old one.
+type testerRotation struct {
+	from   string
+	to     string
+	number uint64
+}
+
+// testerDevice represents a single device vote carried in a header extension.
+type testerDevice struct {
+	device   string
+	metadata string // Attested metadata, hashed into the vote
+	auth     bool
 }
 
 type poiTest struct {
-	epoch   uint64
-	signers []string
-	votes   []testerVote
-	results []string
-	failure error
+	epoch          uint64
+	recoveryPeriod uint64 // Seconds after which an unhealthy signer recovers
+	recoveryBlocks uint64 // Blocks an unhealthy signer has to seal to recover
+	telemetry      bool   // Whether performance is derived from the sealed telemetry
+	signers        []string
+	votes          []testerVote
+	results        []string
+	health         map[string]SignerHealth // Expected health of the signers, if checked
+	performance    map[string]int64        // Expected performance of the signers, if checked
+	devices        map[string]string       // Expected device registry with metadata, if checked
+	failure        error
 }
 
 // Tests that Poi signer voting is evaluated correctly for various simple and
@@ -371,6 +409,579 @@ func TestPoi(t *testing.T) {
 	}
 }
 
+// Tests that unhealthy Poi signers recover deterministically after the recovery
+// period elapsed or after sealing enough blocks. Note, the chain maker spaces the
+// blocks 10 seconds apart.
+func TestPoiRecovery(t *testing.T) {
+	tests := []poiTest{
+		{
+			// No recovery configured, an unhealthy signer stays unhealthy
+			signers: []string{"A", "B"},
+			votes: []testerVote{
+				{signer: "A", status: statusHealth, target: "A", value: uint64(Unhealthy)},
+				{signer: "B", status: statusHealth, target: "A", value: uint64(Unhealthy)},
+				{signer: "A"},
+				{signer: "B"},
+				{signer: "A"},
+			},
+			results: []string{"A", "B"},
+			health:  map[string]SignerHealth{"A": Unhealthy, "B": Healthy},
+		}, {
+			// Recovery period not yet elapsed
+			recoveryPeriod: 25,
+			signers:        []string{"A"},
+			votes: []testerVote{
+				{signer: "A", status: statusHealth, target: "A", value: uint64(Unhealthy)},
+				{signer: "A"},
+				{signer: "A"},
+			},
+			results: []string{"A"},
+			health:  map[string]SignerHealth{"A": Unhealthy},
+		}, {
+			// Recovery period elapsed
+			recoveryPeriod: 25,
+			signers:        []string{"A"},
+			votes: []testerVote{
+				{signer: "A", status: statusHealth, target: "A", value: uint64(Unhealthy)},
+				{signer: "A"},
+				{signer: "A"},
+				{signer: "A"},
+			},
+			results: []string{"A"},
+			health:  map[string]SignerHealth{"A": Healthy},
+		}, {
+			// Recovery period elapses even if the unhealthy signer seals nothing
+			recoveryPeriod: 15,
+			signers:        []string{"A", "B", "C"},
+			votes: []testerVote{
+				{signer: "A", status: statusHealth, target: "C", value: uint64(Unhealthy)},
+				{signer: "B", status: statusHealth, target: "C", value: uint64(Unhealthy)},
+				{signer: "A"},
+				{signer: "B"},
+			},
+			results: []string{"A", "B", "C"},
+			health:  map[string]SignerHealth{"C": Healthy},
+		}, {
+			// Not enough blocks sealed by the unhealthy signer
+			recoveryBlocks: 2,
+			signers:        []string{"A", "B", "C"},
+			votes: []testerVote{
+				{signer: "A", status: statusHealth, target: "C", value: uint64(Unhealthy)},
+				{signer: "B", status: statusHealth, target: "C", value: uint64(Unhealthy)},
+				{signer: "C"},
+				{signer: "A"},
+				{signer: "B"},
+			},
+			results: []string{"A", "B", "C"},
+			health:  map[string]SignerHealth{"C": Unhealthy},
+		}, {
+			// Enough blocks sealed by the unhealthy signer
+			recoveryBlocks: 2,
+			signers:        []string{"A", "B", "C"},
+			votes: []testerVote{
+				{signer: "A", status: statusHealth, target: "C", value: uint64(Unhealthy)},
+				{signer: "B", status: statusHealth, target: "C", value: uint64(Unhealthy)},
+				{signer: "C"},
+				{signer: "A"},
+				{signer: "C"},
+			},
+			results: []string{"A", "B", "C"},
+			health:  map[string]SignerHealth{"C": Healthy},
+		}, {
+			// The block marking a signer unhealthy doesn't count towards recovery
+			recoveryBlocks: 1,
+			signers:        []string{"A"},
+			votes: []testerVote{
+				{signer: "A", status: statusHealth, target: "A", value: uint64(Unhealthy)},
+			},
+			results: []string{"A"},
+			health:  map[string]SignerHealth{"A": Unhealthy},
+		}, {
+			// Whichever recovery condition is met first wins
+			recoveryPeriod: 1000,
+			recoveryBlocks: 1,
+			signers:        []string{"A"},
+			votes: []testerVote{
+				{signer: "A", status: statusHealth, target: "A", value: uint64(Unhealthy)},
+				{signer: "A"},
+			},
+			results: []string{"A"},
+			health:  map[string]SignerHealth{"A": Healthy},
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprint(i), tt.run)
+	}
+}
+
+// Tests that Poi signer status votes (health and performance) are tallied
+// from the headers and applied identically on every node.
+func TestPoiStatusVotes(t *testing.T) {
+	tests := []poiTest{
+		{
+			// Single signer, marking itself unhealthy passes immediately
+			signers: []string{"A"},
+			votes: []testerVote{
+				{signer: "A", status: statusHealth, target: "A", value: uint64(Unhealthy)},
+			},
+			results: []string{"A"},
+			health:  map[string]SignerHealth{"A": Unhealthy},
+		}, {
+			// Two signers, a single health vote is not enough
+			signers: []string{"A", "B"},
+			votes: []testerVote{
+				{signer: "A", status: statusHealth, target: "B", value: uint64(Unhealthy)},
+			},
+			results: []string{"A", "B"},
+			health:  map[string]SignerHealth{"A": Healthy, "B": Healthy},
+		}, {
+			// Three signers, two of them agreeing marks the third unhealthy
+			signers: []string{"A", "B", "C"},
+			votes: []testerVote{
+				{signer: "A", status: statusHealth, target: "C", value: uint64(Unhealthy)},
+				{signer: "B", status: statusHealth, target: "C", value: uint64(Unhealthy)},
+			},
+			results: []string{"A", "B", "C"},
+			health:  map[string]SignerHealth{"A": Healthy, "B": Healthy, "C": Unhealthy},
+		}, {
+			// Three signers, an unhealthy signer can be voted healthy again
+			signers: []string{"A", "B", "C"},
+			votes: []testerVote{
+				{signer: "A", status: statusHealth, target: "C", value: uint64(Unhealthy)},
+				{signer: "B", status: statusHealth, target: "C", value: uint64(Unhealthy)},
+				{signer: "C", status: statusHealth, target: "C", value: uint64(Healthy)},
+				{signer: "A", status: statusHealth, target: "C", value: uint64(Healthy)},
+			},
+			results: []string{"A", "B", "C"},
+			health:  map[string]SignerHealth{"C": Healthy},
+		}, {
+			// Three signers, performance only changes on an agreed value
+			signers: []string{"A", "B", "C"},
+			votes: []testerVote{
+				{signer: "A", status: statusPerformance, target: "B", value: 100},
+				{signer: "B", status: statusPerformance, target: "B", value: 50},
+				{signer: "C", status: statusPerformance, target: "B", value: 100},
+			},
+			results:     []string{"A", "B", "C"},
+			performance: map[string]int64{"A": 0, "B": 100, "C": 0},
+		}, {
+			// Three signers, re-voting replaces the previous vote of the signer
+			signers: []string{"A", "B", "C"},
+			votes: []testerVote{
+				{signer: "A", status: statusPerformance, target: "B", value: 100},
+				{signer: "B"},
+				{signer: "A", status: statusPerformance, target: "B", value: 50},
+				{signer: "C", status: statusPerformance, target: "B", value: 100},
+			},
+			results:     []string{"A", "B", "C"},
+			performance: map[string]int64{"B": 0},
+		}, {
+			// Status votes are discarded on checkpoint blocks
+			epoch:   3,
+			signers: []string{"A", "B", "C"},
+			votes: []testerVote{
+				{signer: "A", status: statusPerformance, target: "B", value: 100},
+				{signer: "B"},
+				{signer: "C", checkpoint: []string{"A", "B", "C"}},
+				{signer: "A", status: statusPerformance, target: "B", value: 100},
+			},
+			results:     []string{"A", "B", "C"},
+			performance: map[string]int64{"B": 0},
+		}, {
+			// Status votes on non-signers are ignored
+			signers: []string{"A"},
+			votes: []testerVote{
+				{signer: "A", status: statusPerformance, target: "B", value: 100},
+			},
+			results:     []string{"A"},
+			performance: map[string]int64{"B": 0},
+		}, {
+			// Dropping a signer discards its status votes
+			signers: []string{"A", "B", "C"},
+			votes: []testerVote{
+				{signer: "C", status: statusPerformance, target: "A", value: 100},
+				{signer: "A", voted: "C", auth: false},
+				{signer: "B", voted: "C", auth: false},
+				{signer: "A", status: statusPerformance, target: "A", value: 100},
+			},
+			results:     []string{"A", "B"},
+			performance: map[string]int64{"A": 0},
+		}, {
+			// Malformed status votes are rejected
+			signers: []string{"A"},
+			votes: []testerVote{
+				{signer: "A", status: statusHealth, target: "A", value: 2},
+			},
+			failure: errInvalidStatusVote,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprint(i), tt.run)
+	}
+}
+
+// Tests that device identity votes carried in header extensions are tallied
+// into the device registry correctly.
+func TestPoiDeviceVotes(t *testing.T) {
+	tests := []poiTest{
+		{
+			// Single signer, registering a device passes immediately
+			signers: []string{"A"},
+			votes: []testerVote{
+				{signer: "A", devices: []testerDevice{{device: "D", metadata: "sensor", auth: true}}},
+			},
+			results: []string{"A"},
+			devices: map[string]string{"D": "sensor"},
+		}, {
+			// Two signers, a single device vote is not enough
+			signers: []string{"A", "B"},
+			votes: []testerVote{
+				{signer: "A", devices: []testerDevice{{device: "D", metadata: "sensor", auth: true}}},
+			},
+			results: []string{"A", "B"},
+			devices: map[string]string{},
+		}, {
+			// Two signers, registering multiple devices at once
+			signers: []string{"A", "B"},
+			votes: []testerVote{
+				{signer: "A", devices: []testerDevice{{device: "D", metadata: "sensor", auth: true}, {device: "E", metadata: "meter", auth: true}}},
+				{signer: "B", devices: []testerDevice{{device: "D", metadata: "sensor", auth: true}, {device: "E", metadata: "meter", auth: true}}},
+			},
+			results: []string{"A", "B"},
+			devices: map[string]string{"D": "sensor", "E": "meter"},
+		}, {
+			// Two signers, votes on different metadata don't add up
+			signers: []string{"A", "B"},
+			votes: []testerVote{
+				{signer: "A", devices: []testerDevice{{device: "D", metadata: "sensor", auth: true}}},
+				{signer: "B", devices: []testerDevice{{device: "D", metadata: "meter", auth: true}}},
+			},
+			results: []string{"A", "B"},
+			devices: map[string]string{},
+		}, {
+			// Two signers, re-attesting a device with new metadata and revoking another
+			signers: []string{"A", "B"},
+			votes: []testerVote{
+				{signer: "A", devices: []testerDevice{{device: "D", metadata: "sensor", auth: true}, {device: "E", metadata: "meter", auth: true}}},
+				{signer: "B", devices: []testerDevice{{device: "D", metadata: "sensor", auth: true}, {device: "E", metadata: "meter", auth: true}}},
+				{signer: "A", devices: []testerDevice{{device: "D", metadata: "sensor-v2", auth: true}, {device: "E"}}},
+				{signer: "B", devices: []testerDevice{{device: "D", metadata: "sensor-v2", auth: true}, {device: "E"}}},
+			},
+			results: []string{"A", "B"},
+			devices: map[string]string{"D": "sensor-v2"},
+		}, {
+			// Device votes are reset on checkpoint blocks
+			epoch:   3,
+			signers: []string{"A", "B", "C"},
+			votes: []testerVote{
+				{signer: "A", devices: []testerDevice{{device: "D", metadata: "sensor", auth: true}}},
+				{signer: "B"},
+				{signer: "C", checkpoint: []string{"A", "B", "C"}},
+				{signer: "A"},
+				{signer: "B", devices: []testerDevice{{device: "D", metadata: "sensor", auth: true}}},
+			},
+			results: []string{"A", "B", "C"},
+			devices: map[string]string{},
+		}, {
+			// Deauthorizing a signer discards its device votes
+			signers: []string{"A", "B", "C"},
+			votes: []testerVote{
+				{signer: "A", devices: []testerDevice{{device: "D", metadata: "sensor", auth: true}}},
+				{signer: "B", voted: "A", auth: false},
+				{signer: "C", voted: "A", auth: false},
+				{signer: "B", devices: []testerDevice{{device: "D", metadata: "sensor", auth: true}}},
+			},
+			results: []string{"B", "C"},
+			devices: map[string]string{},
+		}, {
+			// Malformed header extensions are rejected
+			signers: []string{"A"},
+			votes: []testerVote{
+				{signer: "A", extension: []byte{0x01, 0x02}},
+			},
+			failure: errInvalidExtension,
+		}, {
+			// Multiple votes on the same device in a header are rejected
+			signers: []string{"A"},
+			votes: []testerVote{
+				{signer: "A", devices: []testerDevice{{device: "D", metadata: "sensor", auth: true}, {device: "D"}}},
+			},
+			failure: errInvalidDeviceVotes,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprint(i), tt.run)
+	}
+}
+
+// Tests that equivocation evidences carried in header extensions punish the
+// offending signers correctly.
+func TestPoiEvidence(t *testing.T) {
+	tests := []poiTest{
+		{
+			// Equivocating signer is deauthorized
+			signers: []string{"A", "B", "C"},
+			votes: []testerVote{
+				{signer: "A"},
+				{signer: "B", evidence: []testerEvidence{{signer: "A", number: 1}}},
+			},
+			results: []string{"B", "C"},
+		}, {
+			// Evidence against an already deauthorized signer is ignored
+			signers: []string{"A", "B", "C"},
+			votes: []testerVote{
+				{signer: "A"},
+				{signer: "B", evidence: []testerEvidence{{signer: "A", number: 1}}},
+				{signer: "C", evidence: []testerEvidence{{signer: "A", number: 1}}},
+			},
+			results: []string{"B", "C"},
+		}, {
+			// Deauthorizing a signer discards its votes
+			signers: []string{"A", "B", "C"},
+			votes: []testerVote{
+				{signer: "A", voted: "D", auth: true},
+				{signer: "B", evidence: []testerEvidence{{signer: "A", number: 1}}},
+				{signer: "C", voted: "D", auth: true},
+			},
+			results: []string{"B", "C"},
+		}, {
+			// The last signer is marked unhealthy instead of deauthorized
+			signers: []string{"A"},
+			votes: []testerVote{
+				{signer: "A"},
+				{signer: "A", evidence: []testerEvidence{{signer: "A", number: 1}}},
+			},
+			results: []string{"A"},
+			health:  map[string]SignerHealth{"A": Unhealthy},
+		}, {
+			// An offence is only punished once
+			recoveryBlocks: 1,
+			signers:        []string{"A"},
+			votes: []testerVote{
+				{signer: "A"},
+				{signer: "A", evidence: []testerEvidence{{signer: "A", number: 1}}},
+				{signer: "A", evidence: []testerEvidence{{signer: "A", number: 1}}},
+			},
+			results: []string{"A"},
+			health:  map[string]SignerHealth{"A": Healthy},
+		}, {
+			// Evidence expires after an epoch
+			epoch:   3,
+			signers: []string{"A"},
+			votes: []testerVote{
+				{signer: "A"},
+				{signer: "A"},
+				{signer: "A", checkpoint: []string{"A"}},
+				{signer: "A", evidence: []testerEvidence{{signer: "A", number: 1}}},
+			},
+			results: []string{"A"},
+			health:  map[string]SignerHealth{"A": Healthy},
+		}, {
+			// Evidence against a non-signer is ignored
+			signers: []string{"A", "B"},
+			votes: []testerVote{
+				{signer: "A"},
+				{signer: "B", evidence: []testerEvidence{{signer: "C", number: 1}}},
+			},
+			results: []string{"A", "B"},
+		}, {
+			// Headers sealed by different signers are no evidence
+			signers: []string{"A", "B"},
+			votes: []testerVote{
+				{signer: "A"},
+				{signer: "B", evidence: []testerEvidence{{signer: "A", second: "B", number: 1}}},
+			},
+			failure: errInvalidEvidence,
+		}, {
+			// Evidence of an offence in the future is rejected
+			signers: []string{"A", "B"},
+			votes: []testerVote{
+				{signer: "A", evidence: []testerEvidence{{signer: "B", number: 1}}},
+			},
+			failure: errInvalidEvidence,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprint(i), tt.run)
+	}
+}
+
+// Tests that the telemetry sealed into the header extension moves the performance
+// of its signer deterministically when enabled.
+func TestPoiTelemetry(t *testing.T) {
+	var (
+		ideal = &telemetry{Version: "Geth/v1.13.15", Processing: 0, Peers: 50}
+		slow  = &telemetry{Version: "Geth/v1.13.15", Processing: 100_000, Peers: 0}
+	)
+	tests := []poiTest{
+		{
+			// Single signer, a single ideal report moves the performance an
+			// eighth of the way towards the full score
+			telemetry: true,
+			signers:   []string{"A"},
+			votes: []testerVote{
+				{signer: "A", telemetry: ideal},
+			},
+			results:     []string{"A"},
+			performance: map[string]int64{"A": 125},
+		}, {
+			// Single signer, consecutive reports are averaged
+			telemetry: true,
+			signers:   []string{"A"},
+			votes: []testerVote{
+				{signer: "A", telemetry: ideal},
+				{signer: "A", telemetry: ideal},
+			},
+			results:     []string{"A"},
+			performance: map[string]int64{"A": 234},
+		}, {
+			// Two signers, every report only affects its own signer
+			telemetry: true,
+			signers:   []string{"A", "B"},
+			votes: []testerVote{
+				{signer: "A", telemetry: ideal},
+				{signer: "B", telemetry: slow},
+				{signer: "A"},
+			},
+			results:     []string{"A", "B"},
+			performance: map[string]int64{"A": 125, "B": 31},
+		}, {
+			// Telemetry disabled, reports are carried but ignored
+			signers: []string{"A"},
+			votes: []testerVote{
+				{signer: "A", telemetry: ideal},
+			},
+			results:     []string{"A"},
+			performance: map[string]int64{"A": 0},
+		}, {
+			// Oversized node versions are rejected
+			telemetry: true,
+			signers:   []string{"A"},
+			votes: []testerVote{
+				{signer: "A", telemetry: &telemetry{Version: strings.Repeat("x", maxTelemetryVersion+1)}},
+			},
+			failure: errInvalidTelemetry,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprint(i), tt.run)
+	}
+}
+
+// Tests that signer rotations hand the identity of a signer over to a new key,
+// carrying over its status, recent blocks and votes.
+func TestPoiRotations(t *testing.T) {
+	tests := []poiTest{
+		{
+			// Two signers, a rotation replaces the old key with the new one
+			signers: []string{"A", "B"},
+			votes: []testerVote{
+				{signer: "A", rotations: []testerRotation{{from: "A", to: "C"}}},
+				{signer: "B"},
+				{signer: "C"},
+			},
+			results: []string{"B", "C"},
+		}, {
+			// Two signers, the new key inherits the recent blocks of the old one
+			signers: []string{"A", "B"},
+			votes: []testerVote{
+				{signer: "A", rotations: []testerRotation{{from: "A", to: "C"}}},
+				{signer: "C"},
+			},
+			failure: errRecentlySigned,
+		}, {
+			// Single signer, the status carries over and the telemetry of the
+			// rotating block isn't mistaken for a report
+			telemetry: true,
+			signers:   []string{"A"},
+			votes: []testerVote{
+				{signer: "A", status: statusPerformance, target: "A", value: 5},
+				{signer: "A", rotations: []testerRotation{{from: "A", to: "B", number: 1}}},
+				{signer: "B"},
+			},
+			results:     []string{"B"},
+			health:      map[string]SignerHealth{"B": Healthy},
+			performance: map[string]int64{"B": 5},
+		}, {
+			// Three signers, votes cast by the old key still count
+			signers: []string{"A", "B", "C"},
+			votes: []testerVote{
+				{signer: "A", voted: "D", auth: true},
+				{signer: "B", rotations: []testerRotation{{from: "A", to: "E"}}},
+				{signer: "C", voted: "D", auth: true},
+			},
+			results: []string{"B", "C", "D", "E"},
+		}, {
+			// Two signers, rotating onto an existing signer is ignored
+			signers: []string{"A", "B"},
+			votes: []testerVote{
+				{signer: "A", rotations: []testerRotation{{from: "A", to: "B"}}},
+			},
+			results: []string{"A", "B"},
+		}, {
+			// Two signers, rotations signed by non-signers are ignored
+			signers: []string{"A", "B"},
+			votes: []testerVote{
+				{signer: "A", rotations: []testerRotation{{from: "C", to: "D"}}},
+			},
+			results: []string{"A", "B"},
+		}, {
+			// Three signers, a rotation can't be replayed after re-adding the old key
+			signers: []string{"A", "B", "C"},
+			votes: []testerVote{
+				{signer: "A", rotations: []testerRotation{{from: "A", to: "D"}}},
+				{signer: "B", voted: "D", auth: false},
+				{signer: "C", voted: "D", auth: false},
+				{signer: "B", voted: "A", auth: true},
+				{signer: "C", voted: "A", auth: true},
+				{signer: "B", rotations: []testerRotation{{from: "A", to: "D"}}},
+			},
+			results: []string{"A", "B", "C"},
+		}, {
+			// Three signers, evidence of an offence of the old key slashes the new one
+			signers: []string{"A", "B", "C"},
+			votes: []testerVote{
+				{signer: "A"},
+				{signer: "B", rotations: []testerRotation{{from: "A", to: "D"}}},
+				{signer: "C", evidence: []testerEvidence{{signer: "A", number: 1}}},
+			},
+			results: []string{"B", "C"},
+		}, {
+			// Three signers, evidence of an offence of the old key after the
+			// handover doesn't touch the new one
+			signers: []string{"A", "B", "C"},
+			votes: []testerVote{
+				{signer: "A", rotations: []testerRotation{{from: "A", to: "D"}}},
+				{signer: "B"},
+				{signer: "C", evidence: []testerEvidence{{signer: "A", number: 2}}},
+			},
+			results: []string{"B", "C", "D"},
+		}, {
+			// Single signer, a punished offence stays punished after a handover
+			recoveryBlocks: 1,
+			signers:        []string{"A"},
+			votes: []testerVote{
+				{signer: "A"},
+				{signer: "A", evidence: []testerEvidence{{signer: "A", number: 1}}},
+				{signer: "A", rotations: []testerRotation{{from: "A", to: "B", number: 1}}},
+				{signer: "B", evidence: []testerEvidence{{signer: "A", number: 1}}},
+			},
+			results: []string{"B"},
+			health:  map[string]SignerHealth{"B": Healthy},
+		}, {
+			// Rotations signed at or after the including block are rejected
+			signers: []string{"A", "B"},
+			votes: []testerVote{
+				{signer: "A", rotations: []testerRotation{{from: "B", to: "C", number: 1}}},
+			},
+			failure: errInvalidRotation,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprint(i), tt.run)
+	}
+}
+
 func (tt *poiTest) run(t *testing.T) {
 	// Create the account pool and generate the initial set of signers
 	accounts := newTesterAccountPool()
@@ -398,8 +1009,11 @@ func (tt *poiTest) run(t *testing.T) {
 	// Assemble a chain of headers from the cast votes
 	config := *params.TestChainConfig
 	config.Poi = &params.PoiConfig{
-		Period: 1,
-		Epoch:  tt.epoch,
+		Period:         1,
+		Epoch:          tt.epoch,
+		RecoveryPeriod: tt.recoveryPeriod,
+		RecoveryBlocks: tt.recoveryBlocks,
+		Telemetry:      tt.telemetry,
 	}
 	genesis.Config = &config
 
@@ -428,6 +1042,41 @@ func (tt *poiTest) run(t *testing.T) {
 			accounts.checkpoint(header, auths)
 		}
 		header.Difficulty = diffInTurn // Ignored, we just need a valid number
+		if vote := tt.votes[j]; vote.status != statusNone {
+			header.MixDigest = encodeStatusVote(vote.status, accounts.address(vote.target), vote.value)
+		}
+		if ext := tt.votes[j].extension; ext != nil {
+			header.Extra = append(append(make([]byte, extraVanity), ext...), make([]byte, extraSeal)...)
+		}
+		if vote := tt.votes[j]; vote.devices != nil || vote.evidence != nil || vote.telemetry != nil || vote.rotations != nil {
+			ext := &headerExtension{Version: extensionVersion, Telemetry: vote.telemetry}
+			for _, device := range vote.devices {
+				vote := &deviceVote{Address: accounts.address(device.device), Authorize: device.auth}
+				if device.metadata != "" {
+					vote.Metadata = crypto.Keccak256Hash([]byte(device.metadata))
+				}
+				ext.Devices = append(ext.Devices, vote)
+			}
+			for _, evidence := range vote.evidence {
+				first := &types.Header{Number: new(big.Int).SetUint64(evidence.number), Difficulty: diffInTurn, Time: 1, Extra: make([]byte, extraVanity+extraSeal)}
+				second := &types.Header{Number: new(big.Int).SetUint64(evidence.number), Difficulty: diffInTurn, Time: 2, Extra: make([]byte, extraVanity+extraSeal)}
+				accounts.sign(first, evidence.signer)
+				if evidence.second != "" {
+					accounts.sign(second, evidence.second)
+				} else {
+					accounts.sign(second, evidence.signer)
+				}
+				ext.Evidence = append(ext.Evidence, &Evidence{First: first, Second: second})
+			}
+			for _, rotation := range vote.rotations {
+				accounts.address(rotation.from)
+				signer := accounts.address(rotation.to)
+				sig, _ := crypto.Sign(crypto.Keccak256(RotationRLP(signer, rotation.number)), accounts.accounts[rotation.from])
+				ext.Rotations = append(ext.Rotations, &Rotation{Signer: signer, Number: rotation.number, Signature: sig})
+			}
+			blob, _ := encodeExtension(ext)
+			header.Extra = append(append(make([]byte, extraVanity), blob...), make([]byte, extraSeal)...)
+		}
 
 		// Generate the signature, embed it into the header and the block
 		accounts.sign(header, tt.votes[j].signer)
@@ -489,4 +1138,105 @@ func (tt *poiTest) run(t *testing.T) {
 			t.Fatalf("signer %d: signer mismatch: have %x, want %x", j, result[j], signers[j])
 		}
 	}
+	// Verify the final signer statuses against the expected ones
+	for signer, health := range tt.health {
+		if have := snap.Health[accounts.address(signer)]; have != health {
+			t.Errorf("signer %s: health mismatch: have %d, want %d", signer, have, health)
+		}
+	}
+	for signer, performance := range tt.performance {
+		if have := snap.Performance[accounts.address(signer)]; have != performance {
+			t.Errorf("signer %s: performance mismatch: have %d, want %d", signer, have, performance)
+		}
+	}
+	// Verify the final device registry against the expected one
+	if tt.devices != nil {
+		if len(snap.Devices) != len(tt.devices) {
+			t.Errorf("device count mismatch: have %d, want %d", len(snap.Devices), len(tt.devices))
+		}
+		for device, metadata := range tt.devices {
+			have, ok := snap.Devices[accounts.address(device)]
+			if !ok {
+				t.Errorf("device %s: not registered", device)
+				continue
+			}
+			if want := crypto.Keccak256Hash([]byte(metadata)); have.Metadata != want {
+				t.Errorf("device %s: metadata mismatch: have %x, want %x", device, have.Metadata, want)
+			}
+		}
+	}
+}
+
+// Tests that missed in-turn slots are counted in the snapshots derived from the
+// chain, so that a reorg to a chain without the misses un-counts them.
+func TestPoiFailures(t *testing.T) {
+	var (
+		accounts = newTesterAccountPool()
+		names    = []string{"A", "B", "C"}
+		signers  = make([]common.Address, len(names))
+		config   = &params.PoiConfig{Period: 1, Epoch: 30000, HealthThreshold: 2}
+	)
+	for i, name := range names {
+		signers[i] = accounts.address(name)
+	}
+	base := newSnapshot(config, lru.NewCache[common.Hash, common.Address](inmemorySignatures), 0, common.Hash{}, signers)
+
+	// extend seals n blocks on top of the snapshot, letting the in-turn signer
+	// seal its slot unless it's the skipped one
+	extend := func(snap *Snapshot, n int, skip common.Address) *Snapshot {
+		parent := snap.Hash
+		for i := 0; i < n; i++ {
+			number := snap.Number + 1
+			inturn, _ := snap.inturnSigner(number)
+
+			var sealer string
+			for _, name := range names {
+				signer := accounts.address(name)
+				if signer == skip || snap.recentlySigned(number, signer) {
+					continue
+				}
+				if sealer == "" || signer == inturn {
+					sealer = name
+				}
+			}
+			header := &types.Header{
+				Number:     new(big.Int).SetUint64(number),
+				ParentHash: parent,
+				Time:       number,
+				Extra:      make([]byte, extraVanity+extraSeal),
+			}
+			accounts.sign(header, sealer)
+
+			var err error
+			if snap, err = snap.apply([]*types.Header{header}); err != nil {
+				t.Fatalf("failed to apply block %d: %v", number, err)
+			}
+			parent = header.Hash()
+		}
+		return snap
+	}
+	skipped := signers[0]
+
+	// Skip a signer on one chain, its misses are counted until it's due to be
+	// voted unhealthy
+	failing := extend(base, 6, skipped)
+	if have := failing.Failures[skipped]; have != 2 {
+		t.Errorf("failures mismatch: have %d, want 2", have)
+	}
+	if have := failing.failing(failing.Number + 1); len(have) != 1 || have[0] != skipped {
+		t.Errorf("failing signers mismatch: have %x, want [%x]", have, skipped)
+	}
+	// Reorg to a chain where every signer sealed its slots, nothing is counted
+	healthy := extend(base, 6, common.Address{})
+	if len(healthy.Failures) != 0 {
+		t.Errorf("failures not un-counted: have %v", healthy.Failures)
+	}
+	if len(base.Failures) != 0 {
+		t.Errorf("parent snapshot modified: have %v", base.Failures)
+	}
+	// Sealing its own slot again resets the count of the skipped signer
+	recovered := extend(failing, 3, common.Address{})
+	if have, ok := recovered.Failures[skipped]; ok {
+		t.Errorf("failures not reset: have %d", have)
+	}
 }
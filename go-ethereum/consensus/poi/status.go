@@ -0,0 +1,82 @@
+package poi
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// StatusKind identifies which signer attribute a status vote is trying to change.
+type StatusKind uint8
+
+const (
+	statusNone        StatusKind = 0 // Header carries no status vote
+	statusHealth      StatusKind = 1 // Vote on the health of a signer
+	statusPerformance StatusKind = 2 // Vote on the performance of a signer
+)
+
+// errInvalidStatusVote is returned if a header's mix digest cannot be decoded
+// into a well formed signer status vote.
+var errInvalidStatusVote = errors.New("invalid signer status vote")
+
+// StatusVote represents a single vote that an authorized signer made to modify
+// the health or performance of another signer. Status votes are carried in the
+// mix digest of the sealed header so that every node replays them the same way.
+type StatusVote struct {
+	Signer  common.Address `json:"signer"`  // Authorized signer that cast this vote
+	Block   uint64         `json:"block"`   // Block number the vote was cast in (expire old votes)
+	Address common.Address `json:"address"` // Signer being voted on to change its status
+	Kind    StatusKind     `json:"kind"`    // Attribute of the signer the vote changes
+	Value   uint64         `json:"value"`   // New value of the attribute being proposed
+}
+
+// statusKey identifies a single attribute of a single signer.
+type statusKey struct {
+	Kind    StatusKind
+	Address common.Address
+}
+
+// encodeStatusVote packs a status vote into a header mix digest. The layout is:
+//
+//	[0]     status kind
+//	[1:21]  address of the signer being voted on
+//	[21:24] reserved, must be zero
+//	[24:32] big endian value of the vote
+func encodeStatusVote(kind StatusKind, address common.Address, value uint64) common.Hash {
+	var digest common.Hash
+	digest[0] = byte(kind)
+	copy(digest[1:1+common.AddressLength], address[:])
+	binary.BigEndian.PutUint64(digest[24:], value)
+	return digest
+}
+
+// decodeStatusVote unpacks a status vote from a header mix digest. An all zero
+// digest decodes into statusNone.
+func decodeStatusVote(digest common.Hash) (StatusKind, common.Address, uint64, error) {
+	if digest == (common.Hash{}) {
+		return statusNone, common.Address{}, 0, nil
+	}
+	var (
+		kind    = StatusKind(digest[0])
+		address = common.BytesToAddress(digest[1 : 1+common.AddressLength])
+		value   = binary.BigEndian.Uint64(digest[24:])
+	)
+	if digest[21] != 0 || digest[22] != 0 || digest[23] != 0 {
+		return statusNone, common.Address{}, 0, errInvalidStatusVote
+	}
+	switch kind {
+	case statusHealth:
+		if value != uint64(Healthy) && value != uint64(Unhealthy) {
+			return statusNone, common.Address{}, 0, errInvalidStatusVote
+		}
+	case statusPerformance:
+		if value > math.MaxInt64 {
+			return statusNone, common.Address{}, 0, errInvalidStatusVote
+		}
+	default:
+		return statusNone, common.Address{}, 0, errInvalidStatusVote
+	}
+	return kind, address, value, nil
+}
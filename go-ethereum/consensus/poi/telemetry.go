@@ -0,0 +1,94 @@
+package poi
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+const (
+	maxTelemetryVersion = 64 // Maximum length of the node version carried in the telemetry
+
+	telemetryScore      = 1000          // Performance sample of a signer with ideal telemetry
+	telemetryProcessing = 100_000       // Block processing time in microseconds halving the processing score
+	telemetryMaxPeers   = 25            // Peer count earning the full connectivity score
+	telemetrySmoothing  = 8             // Number of samples the reported performance is averaged over
+	telemetryMaxTime    = 3_600_000_000 // Processing time in microseconds above which samples are capped
+)
+
+// errInvalidTelemetry is returned if the telemetry carried in a header extension
+// exceeds its size limits.
+var errInvalidTelemetry = errors.New("invalid telemetry in header extension")
+
+// TelemetryFn is a callback reporting the live statistics of the local node to
+// seal into the telemetry of its blocks: the time it took to process the last
+// imported block and the number of connected peers.
+type TelemetryFn func() (processing time.Duration, peers int)
+
+// telemetry is the self-reported state of the node of a signer, as carried in
+// the header extension of the blocks it seals.
+type telemetry struct {
+	Version    string // Client version of the signer's node
+	Processing uint64 // Processing time of the last imported block in microseconds
+	Peers      uint64 // Number of peers the signer's node is connected to
+}
+
+// sanityCheck verifies that the telemetry doesn't exceed its size limits.
+func (t *telemetry) sanityCheck() error {
+	if len(t.Version) > maxTelemetryVersion {
+		return errInvalidTelemetry
+	}
+	return nil
+}
+
+// score converts the telemetry into a performance sample between 0 and
+// telemetryScore. Half of it is earned by processing blocks fast, the other half
+// by staying well connected.
+func (t *telemetry) score() int64 {
+	processing := t.Processing
+	if processing > telemetryMaxTime {
+		processing = telemetryMaxTime
+	}
+	peers := t.Peers
+	if peers > telemetryMaxPeers {
+		peers = telemetryMaxPeers
+	}
+	speed := telemetryScore / 2 * telemetryProcessing / (telemetryProcessing + processing)
+	connectivity := telemetryScore / 2 * peers / telemetryMaxPeers
+
+	return int64(speed + connectivity)
+}
+
+// SetTelemetry sets the callback reporting the statistics of the local node to
+// seal into the blocks it creates if telemetry is enabled.
+func (c *Poi) SetTelemetry(fn TelemetryFn) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.telemetryFn = fn
+}
+
+// localTelemetry assembles the telemetry of the local node to seal into a new
+// block. The lock must be held.
+func (c *Poi) localTelemetry() *telemetry {
+	version := params.VersionWithMeta
+	if len(version) > maxTelemetryVersion {
+		version = version[:maxTelemetryVersion]
+	}
+	t := &telemetry{Version: version}
+	if c.telemetryFn != nil {
+		processing, peers := c.telemetryFn()
+		t.Processing, t.Peers = uint64(processing.Microseconds()), uint64(peers)
+	}
+	return t
+}
+
+// applyTelemetry moves the performance of a signer towards the score of the
+// telemetry it sealed, averaging it over the last few reports so that a single
+// slow block doesn't reshuffle the signer pool.
+func (s *Snapshot) applyTelemetry(signer common.Address, t *telemetry) {
+	perf := s.Performance[signer]
+	s.Performance[signer] = perf + (t.score()-perf)/telemetrySmoothing
+}
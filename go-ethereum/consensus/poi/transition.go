@@ -0,0 +1,226 @@
+package poi
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/clique"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"golang.org/x/exp/slices"
+)
+
+// errBeforeFork is returned if a PoI snapshot is requested for a block sealed by
+// the engine PoI took over from.
+var errBeforeFork = errors.New("block before the poi fork")
+
+// carryOverFn resolves the signers and recent signers PoI starts with from the
+// engine it takes over from, at the last block before the fork.
+type carryOverFn func(chain consensus.ChainHeaderReader, number uint64, hash common.Hash, parents []*types.Header) ([]common.Address, map[uint64]common.Address, error)
+
+// Transition is a consensus engine sealing with Clique before the PoI fork block
+// and with PoI from the fork block on. The signer set and recent signers of the
+// last Clique block carry over to PoI.
+type Transition struct {
+	clique *clique.Clique
+	poi    *Poi
+	fork   uint64
+}
+
+// NewTransition creates a consensus engine switching from Clique to PoI at the
+// given block number, which must be positive.
+func NewTransition(fork uint64, cliqueEngine *clique.Clique, poiEngine *Poi) *Transition {
+	poiEngine.fork = fork
+	poiEngine.carryOver = func(chain consensus.ChainHeaderReader, number uint64, hash common.Hash, parents []*types.Header) ([]common.Address, map[uint64]common.Address, error) {
+		snap, err := cliqueEngine.Snapshot(chain, number, hash, parents)
+		if err != nil {
+			return nil, nil, err
+		}
+		signers := make([]common.Address, 0, len(snap.Signers))
+		for signer := range snap.Signers {
+			signers = append(signers, signer)
+		}
+		slices.SortFunc(signers, func(a, b common.Address) int {
+			return bytes.Compare(a[:], b[:])
+		})
+		return signers, snap.Recents, nil
+	}
+	return &Transition{clique: cliqueEngine, poi: poiEngine, fork: fork}
+}
+
+// isPoi returns whether the block of the given number is sealed by PoI.
+func (t *Transition) isPoi(number *big.Int) bool {
+	return number.Uint64() >= t.fork
+}
+
+// engine returns the engine sealing the block of the given number.
+func (t *Transition) engine(number *big.Int) consensus.Engine {
+	if t.isPoi(number) {
+		return t.poi
+	}
+	return t.clique
+}
+
+// Clique returns the engine sealing the blocks before the fork.
+func (t *Transition) Clique() *clique.Clique {
+	return t.clique
+}
+
+// Poi returns the engine sealing the blocks from the fork on.
+func (t *Transition) Poi() *Poi {
+	return t.poi
+}
+
+// Authorize injects a private key into both engines to mint new blocks with.
+func (t *Transition) Authorize(signer common.Address, signFn SignerFn) {
+	t.clique.Authorize(signer, clique.SignerFn(signFn))
+	t.poi.Authorize(signer, signFn)
+}
+
+// Author implements consensus.Engine, returning the account that sealed the
+// block with the engine of its height.
+func (t *Transition) Author(header *types.Header) (common.Address, error) {
+	return t.engine(header.Number).Author(header)
+}
+
+// VerifyHeader implements consensus.Engine, checking the header against the
+// rules of the engine of its height.
+func (t *Transition) VerifyHeader(chain consensus.ChainHeaderReader, header *types.Header) error {
+	return t.engine(header.Number).VerifyHeader(chain, header)
+}
+
+// VerifyHeaders implements consensus.Engine, verifying a batch of headers that
+// may span the fork. Headers after the fork are verified with all the preceding
+// headers of the batch as parents, so PoI can carry over the Clique signers of
+// headers not yet in the database.
+func (t *Transition) VerifyHeaders(chain consensus.ChainHeaderReader, headers []*types.Header) (chan<- struct{}, <-chan error) {
+	split := len(headers)
+	for i, header := range headers {
+		if t.isPoi(header.Number) {
+			split = i
+			break
+		}
+	}
+	if split == 0 {
+		return t.poi.VerifyHeaders(chain, headers)
+	}
+	if split == len(headers) {
+		return t.clique.VerifyHeaders(chain, headers)
+	}
+	var (
+		abort   = make(chan struct{})
+		results = make(chan error, len(headers))
+	)
+	go func() {
+		cliqueAbort, cliqueResults := t.clique.VerifyHeaders(chain, headers[:split])
+		defer close(cliqueAbort)
+
+		for i := 0; i < split; i++ {
+			select {
+			case <-abort:
+				return
+			case err := <-cliqueResults:
+				results <- err
+			}
+		}
+		for i := split; i < len(headers); i++ {
+			err := t.poi.verifyHeader(chain, headers[i], headers[:i])
+
+			select {
+			case <-abort:
+				return
+			case results <- err:
+			}
+		}
+	}()
+	return abort, results
+}
+
+// VerifyUncles implements consensus.Engine, always returning an error for any
+// uncles as neither engine permits uncles.
+func (t *Transition) VerifyUncles(chain consensus.ChainReader, block *types.Block) error {
+	return t.engine(block.Number()).VerifyUncles(chain, block)
+}
+
+// Prepare implements consensus.Engine, preparing the consensus fields of the
+// header with the engine of its height.
+func (t *Transition) Prepare(chain consensus.ChainHeaderReader, header *types.Header) error {
+	return t.engine(header.Number).Prepare(chain, header)
+}
+
+// Finalize implements consensus.Engine, delegating to the engine of the height.
+func (t *Transition) Finalize(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, txs []*types.Transaction, uncles []*types.Header, withdrawals []*types.Withdrawal) {
+	t.engine(header.Number).Finalize(chain, header, state, txs, uncles, withdrawals)
+}
+
+// FinalizeAndAssemble implements consensus.Engine, delegating to the engine of
+// the height.
+func (t *Transition) FinalizeAndAssemble(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, txs []*types.Transaction, uncles []*types.Header, receipts []*types.Receipt, withdrawals []*types.Withdrawal) (*types.Block, error) {
+	return t.engine(header.Number).FinalizeAndAssemble(chain, header, state, txs, uncles, receipts, withdrawals)
+}
+
+// Seal implements consensus.Engine, sealing the block with the engine of its
+// height.
+func (t *Transition) Seal(chain consensus.ChainHeaderReader, block *types.Block, results chan<- *types.Block, stop <-chan struct{}) error {
+	return t.engine(block.Number()).Seal(chain, block, results, stop)
+}
+
+// SealHash implements consensus.Engine, returning the hash of a block prior to
+// it being sealed by the engine of its height.
+func (t *Transition) SealHash(header *types.Header) common.Hash {
+	return t.engine(header.Number).SealHash(header)
+}
+
+// CalcDifficulty implements consensus.Engine, returning the difficulty the next
+// block should have according to the engine sealing it.
+func (t *Transition) CalcDifficulty(chain consensus.ChainHeaderReader, time uint64, parent *types.Header) *big.Int {
+	return t.engine(new(big.Int).Add(parent.Number, common.Big1)).CalcDifficulty(chain, time, parent)
+}
+
+// APIs implements consensus.Engine, returning the user facing RPC APIs of both
+// engines.
+func (t *Transition) APIs(chain consensus.ChainHeaderReader) []rpc.API {
+	return append(t.clique.APIs(chain), t.poi.APIs(chain)...)
+}
+
+// Close implements consensus.Engine, terminating both engines.
+func (t *Transition) Close() error {
+	if err := t.clique.Close(); err != nil {
+		return err
+	}
+	return t.poi.Close()
+}
+
+// RestrictsSenders implements consensus.TxValidator, returning whether the
+// senders of the blocks after the fork are restricted.
+func (t *Transition) RestrictsSenders(header *types.Header) bool {
+	return t.isPoi(header.Number) && t.poi.RestrictsSenders(header)
+}
+
+// ValidateTxSender implements consensus.TxValidator, restricting the senders of
+// the blocks after the fork if PoI is configured to.
+func (t *Transition) ValidateTxSender(chain consensus.ChainHeaderReader, header *types.Header, sender common.Address) error {
+	if !t.isPoi(header.Number) {
+		return nil
+	}
+	return t.poi.ValidateTxSender(chain, header, sender)
+}
+
+// VerifyHeaderState implements consensus.StateVerifier, checking the blocks
+// after the fork against the parent state if PoI needs to.
+func (t *Transition) VerifyHeaderState(chain consensus.ChainHeaderReader, header *types.Header) error {
+	if !t.isPoi(header.Number) {
+		return nil
+	}
+	return t.poi.VerifyHeaderState(chain, header)
+}
+
+// SealsOnDemand implements consensus.OnDemandSealer. Without knowing the height
+// of the next block, empty blocks are only skipped if neither engine seals them.
+func (t *Transition) SealsOnDemand() bool {
+	return t.clique.SealsOnDemand() && t.poi.SealsOnDemand()
+}
@@ -0,0 +1,120 @@
+package poi
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/clique"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"golang.org/x/exp/slices"
+)
+
+// Tests that a Clique chain switches over to PoI at the fork block, carrying the
+// signer set voted in under Clique and its recent signers over to PoI.
+func TestTransition(t *testing.T) {
+	keys := make([]*ecdsa.PrivateKey, 2)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+	}
+	slices.SortFunc(keys, func(a, b *ecdsa.PrivateKey) int {
+		return crypto.PubkeyToAddress(a.PublicKey).Cmp(crypto.PubkeyToAddress(b.PublicKey))
+	})
+	addrs := []common.Address{crypto.PubkeyToAddress(keys[0].PublicKey), crypto.PubkeyToAddress(keys[1].PublicKey)}
+
+	tests := []struct {
+		signers []int // Signers of the blocks after genesis, the fork being at block 3
+		failure error
+	}{
+		{
+			// Signer 0 votes in signer 1 under Clique, both seal under PoI
+			signers: []int{0, 1, 0, 1, 0},
+		}, {
+			// Signer 1 sealed the last Clique block, it's still recent under PoI
+			signers: []int{0, 1, 1},
+			failure: errRecentlySigned,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			// Start a Clique chain with signer 0 alone, switching to PoI at block 3
+			genspec := &core.Genesis{
+				Config:    new(params.ChainConfig),
+				ExtraData: append(append(make([]byte, extraVanity), addrs[0][:]...), make([]byte, extraSeal)...),
+				BaseFee:   big.NewInt(params.InitialBaseFee),
+			}
+			*genspec.Config = *params.TestChainConfig
+			genspec.Config.Clique = &params.CliqueConfig{Period: 1, Epoch: 30000}
+			genspec.Config.Poi = &params.PoiConfig{Period: 1, Epoch: 30000}
+			genspec.Config.PoiBlock = big.NewInt(3)
+
+			poiEngine := New(genspec.Config.Poi, rawdb.NewMemoryDatabase())
+			poiEngine.fakeDiff = true
+			engine := NewTransition(3, clique.New(genspec.Config.Clique, rawdb.NewMemoryDatabase()), poiEngine)
+
+			_, blocks, _ := core.GenerateChainWithGenesis(genspec, engine, len(tt.signers), func(i int, block *core.BlockGen) {
+				block.SetDifficulty(diffInTurn)
+				if i == 0 {
+					block.SetCoinbase(addrs[1])
+				} else {
+					block.SetCoinbase(common.Address{})
+				}
+			})
+			for j, block := range blocks {
+				header := block.Header()
+				if j > 0 {
+					header.ParentHash = blocks[j-1].Hash()
+				}
+				header.Extra = make([]byte, extraVanity+extraSeal)
+
+				if engine.isPoi(header.Number) {
+					header.Difficulty = diffInTurn
+				} else {
+					// Clique is in-turn for the signer at the block number modulo
+					// the signer count, signer 1 joining in the first block
+					signers := 2
+					if j == 0 {
+						copy(header.Nonce[:], nonceAuthVote)
+						signers = 1
+					}
+					header.Difficulty = big.NewInt(1)
+					if header.Number.Uint64()%uint64(signers) == uint64(tt.signers[j]) {
+						header.Difficulty = big.NewInt(2)
+					}
+				}
+				sig, _ := crypto.Sign(engine.SealHash(header).Bytes(), keys[tt.signers[j]])
+				copy(header.Extra[len(header.Extra)-extraSeal:], sig)
+				blocks[j] = block.WithSeal(header)
+			}
+			chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, genspec, nil, engine, vm.Config{}, nil, nil)
+			if err != nil {
+				t.Fatalf("failed to create test chain: %v", err)
+			}
+			defer chain.Stop()
+
+			if _, err := chain.InsertChain(blocks); err != tt.failure {
+				t.Fatalf("failure mismatch: have %v, want %v", err, tt.failure)
+			}
+			if tt.failure != nil {
+				return
+			}
+			head := blocks[len(blocks)-1]
+			snap, err := poiEngine.snapshot(chain, head.NumberU64(), head.Hash(), nil)
+			if err != nil {
+				t.Fatalf("failed to retrieve snapshot: %v", err)
+			}
+			if signers := snap.signers(); len(signers) != 2 || signers[0] != addrs[0] || signers[1] != addrs[1] {
+				t.Errorf("signers mismatch: have %v, want %v", signers, addrs)
+			}
+			if _, err := poiEngine.snapshot(chain, 1, blocks[0].Hash(), nil); err != errBeforeFork {
+				t.Errorf("pre-fork snapshot error mismatch: have %v, want %v", err, errBeforeFork)
+			}
+		})
+	}
+}
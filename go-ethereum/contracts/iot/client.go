@@ -0,0 +1,303 @@
+// Package iot implements a device-side client of the IoTDataTracker contract,
+// letting Go gateways register devices, publish sensor readings and follow the
+// sensor data recorded on chain.
+package iot
+
+//go:generate go run ../../cmd/abigen --abi ../../../contracts/build/IoTDataTracker.abi.json --bin ../../../contracts/build/IoTDataTracker.bin --pkg contract --type IoTDataTracker --out contract/iotdatatracker.go
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/contracts/iot/contract"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	// DefaultBatchSize is the default maximum number of sensor readings
+	// published in a single updateMultipleSensors transaction.
+	DefaultBatchSize = 16
+
+	// DefaultGasMargin is the default percentage added to the gas estimates of
+	// sensor updates. Estimates run against the latest block, and if the last
+	// update of a sensor was mined in it, rewriting its timestamp looks free.
+	DefaultGasMargin = 10
+)
+
+var (
+	// ErrReverted is returned if a transaction sent by the client was mined but
+	// reverted by the contract.
+	ErrReverted = errors.New("transaction reverted")
+
+	// errEmptyReading is returned if a sensor reading without a sensor name or
+	// value is published, which the contract would revert.
+	errEmptyReading = errors.New("empty sensor name or value")
+
+	// errNoReadings is returned if no sensor readings are published at all.
+	errNoReadings = errors.New("no sensor readings")
+)
+
+// Backend is the chain access the client needs, e.g. ethclient.Client or the
+// client of a simulated backend.
+type Backend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+}
+
+// Reading is a single sensor value measured by a device.
+type Reading struct {
+	Sensor string // Name of the sensor (e.g. "temperature")
+	Value  string // Measured value, stored verbatim
+}
+
+// DataChange is a sensor value change recorded by the contract.
+type DataChange = contract.IoTDataTrackerDataChanged
+
+// Client sends the transactions of a single device to an IoTDataTracker
+// contract. It manages the nonces of the device locally, so readings can be
+// published back to back without waiting for them to be mined.
+type Client struct {
+	address  common.Address
+	backend  Backend
+	abi      *abi.ABI
+	contract *contract.IoTDataTracker
+	opts     *bind.TransactOpts
+
+	BatchSize int // Maximum number of readings per transaction
+	GasMargin int // Percentage added to the gas estimates of sensor updates
+
+	nonce *big.Int   // Next nonce to send with, nil if it needs to be fetched
+	lock  sync.Mutex // Protects the nonce and serializes sending
+}
+
+// NewClient creates a client of the IoTDataTracker contract at the given
+// address, sending transactions with the device key behind the transactor.
+func NewClient(address common.Address, backend Backend, opts *bind.TransactOpts) (*Client, error) {
+	parsed, err := contract.IoTDataTrackerMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	tracker, err := contract.NewIoTDataTracker(address, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		address:   address,
+		backend:   backend,
+		abi:       parsed,
+		contract:  tracker,
+		opts:      opts,
+		BatchSize: DefaultBatchSize,
+		GasMargin: DefaultGasMargin,
+	}, nil
+}
+
+// Deploy creates a new IoTDataTracker contract owned by the account behind the
+// transactor and returns a client of it once the deployment is sent. Use
+// bind.WaitDeployed to wait for the contract to be mined.
+func Deploy(opts *bind.TransactOpts, backend Backend) (*Client, *types.Transaction, error) {
+	address, tx, _, err := contract.DeployIoTDataTracker(opts, backend)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := NewClient(address, backend, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, tx, nil
+}
+
+// Address returns the address of the contract.
+func (c *Client) Address() common.Address {
+	return c.address
+}
+
+// Device returns the address of the device sending the transactions.
+func (c *Client) Device() common.Address {
+	return c.opts.From
+}
+
+// Contract returns the underlying contract binding.
+func (c *Client) Contract() *contract.IoTDataTracker {
+	return c.contract
+}
+
+// Registered returns whether the device is registered and active.
+func (c *Client) Registered(ctx context.Context) (bool, error) {
+	info, err := c.contract.GetDeviceInfo(&bind.CallOpts{Context: ctx}, c.opts.From)
+	if err != nil {
+		return false, err
+	}
+	return info.IsActive, nil
+}
+
+// Register sends the transaction registering the device with the given
+// identifier and location.
+func (c *Client) Register(ctx context.Context, deviceID string, location string) (*types.Transaction, error) {
+	return c.transact(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return c.contract.RegisterDevice(opts, deviceID, location)
+	})
+}
+
+// Publish sends the given sensor readings, batched into updateMultipleSensors
+// transactions of at most BatchSize readings. Only the last reading of every
+// sensor is sent. The transactions are sent with consecutive nonces and
+// returned in order, up to the first one that failed to send.
+//
+// Unless the transactor has a fixed gas limit, the gas is estimated against the
+// latest block and raised by GasMargin percent, so the registration of the
+// device has to be mined first.
+func (c *Client) Publish(ctx context.Context, readings []Reading) ([]*types.Transaction, error) {
+	batches, err := c.batch(readings)
+	if err != nil {
+		return nil, err
+	}
+	var txs []*types.Transaction
+	for _, batch := range batches {
+		sensors := make([]string, len(batch))
+		values := make([]string, len(batch))
+		for i, reading := range batch {
+			sensors[i], values[i] = reading.Sensor, reading.Value
+		}
+		tx, err := c.transact(ctx, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+			if opts.GasLimit == 0 {
+				gas, err := c.estimate(ctx, "updateMultipleSensors", sensors, values)
+				if err != nil {
+					return nil, err
+				}
+				opts.GasLimit = gas
+			}
+			return c.contract.UpdateMultipleSensors(opts, sensors, values)
+		})
+		if err != nil {
+			return txs, err
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
+
+// batch deduplicates the readings, keeping the last value of every sensor, and
+// splits them into batches of at most BatchSize readings sorted by sensor.
+func (c *Client) batch(readings []Reading) ([][]Reading, error) {
+	if len(readings) == 0 {
+		return nil, errNoReadings
+	}
+	latest := make(map[string]string, len(readings))
+	for _, reading := range readings {
+		if reading.Sensor == "" || reading.Value == "" {
+			return nil, errEmptyReading
+		}
+		latest[reading.Sensor] = reading.Value
+	}
+	sensors := make([]string, 0, len(latest))
+	for sensor := range latest {
+		sensors = append(sensors, sensor)
+	}
+	slices.Sort(sensors)
+
+	size := c.BatchSize
+	if size <= 0 {
+		size = DefaultBatchSize
+	}
+	var batches [][]Reading
+	for len(sensors) > 0 {
+		n := size
+		if n > len(sensors) {
+			n = len(sensors)
+		}
+		batch := make([]Reading, n)
+		for i, sensor := range sensors[:n] {
+			batch[i] = Reading{Sensor: sensor, Value: latest[sensor]}
+		}
+		batches = append(batches, batch)
+		sensors = sensors[n:]
+	}
+	return batches, nil
+}
+
+// estimate estimates the gas used by a contract call from the device, raised by
+// the gas margin.
+func (c *Client) estimate(ctx context.Context, method string, args ...interface{}) (uint64, error) {
+	input, err := c.abi.Pack(method, args...)
+	if err != nil {
+		return 0, err
+	}
+	gas, err := c.backend.EstimateGas(ctx, ethereum.CallMsg{From: c.opts.From, To: &c.address, Data: input})
+	if err != nil {
+		return 0, err
+	}
+	return gas + gas*uint64(c.GasMargin)/100, nil
+}
+
+// transact sends a transaction with the next nonce of the device. If sending
+// fails, the nonce is fetched from the pending state again for the next one.
+func (c *Client) transact(ctx context.Context, send func(opts *bind.TransactOpts) (*types.Transaction, error)) (*types.Transaction, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.nonce == nil {
+		nonce, err := c.backend.PendingNonceAt(ctx, c.opts.From)
+		if err != nil {
+			return nil, err
+		}
+		c.nonce = new(big.Int).SetUint64(nonce)
+	}
+	opts := *c.opts
+	opts.Context = ctx
+	opts.Nonce = new(big.Int).Set(c.nonce)
+
+	tx, err := send(&opts)
+	if err != nil {
+		c.nonce = nil
+		return nil, err
+	}
+	c.nonce.Add(c.nonce, common.Big1)
+	return tx, nil
+}
+
+// ResetNonce drops the locally tracked nonce, fetching it from the pending state
+// on the next transaction, e.g. after transactions were dropped from the pool.
+func (c *Client) ResetNonce() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.nonce = nil
+}
+
+// Wait waits for a transaction to be mined and returns its receipt, failing if
+// the transaction reverted.
+func (c *Client) Wait(ctx context.Context, tx *types.Transaction) (*types.Receipt, error) {
+	receipt, err := bind.WaitMined(ctx, c.backend, tx)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return receipt, ErrReverted
+	}
+	return receipt, nil
+}
+
+// Latest returns the latest value of a sensor of a device along with the time
+// and the number of times it changed.
+func (c *Client) Latest(ctx context.Context, device common.Address, sensor string) (string, uint64, uint64, error) {
+	data, err := c.contract.GetSensorData(&bind.CallOpts{Context: ctx}, device, sensor)
+	if err != nil {
+		return "", 0, 0, err
+	}
+	return data.Value, data.Timestamp.Uint64(), data.ChangeCount.Uint64(), nil
+}
+
+// WatchChanges subscribes to the sensor value changes of the given devices, or
+// of all devices if none are given, delivering them into the sink.
+func (c *Client) WatchChanges(ctx context.Context, sink chan<- *DataChange, devices ...common.Address) (event.Subscription, error) {
+	return c.contract.WatchDataChanged(&bind.WatchOpts{Context: ctx}, sink, devices, nil, nil)
+}
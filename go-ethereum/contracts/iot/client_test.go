@@ -0,0 +1,225 @@
+package iot
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	ownerKey, _  = crypto.GenerateKey()
+	ownerAddr    = crypto.PubkeyToAddress(ownerKey.PublicKey)
+	deviceKey, _ = crypto.GenerateKey()
+	deviceAddr   = crypto.PubkeyToAddress(deviceKey.PublicKey)
+)
+
+// newTestClient deploys a tracker contract on a simulated chain and returns a
+// client sending from the device account.
+func newTestClient(t *testing.T) (*simulated.Backend, *Client) {
+	t.Helper()
+
+	funds := new(big.Int).Mul(big.NewInt(100), big.NewInt(params.Ether))
+	sim := simulated.NewBackend(types.GenesisAlloc{
+		ownerAddr:  {Balance: funds},
+		deviceAddr: {Balance: funds},
+	})
+	t.Cleanup(func() { sim.Close() })
+
+	backend := sim.Client()
+	chainID, err := backend.ChainID(context.Background())
+	if err != nil {
+		t.Fatalf("failed to retrieve chain id: %v", err)
+	}
+	owner, _ := bind.NewKeyedTransactorWithChainID(ownerKey, chainID)
+	deployed, tx, err := Deploy(owner, backend)
+	if err != nil {
+		t.Fatalf("failed to deploy contract: %v", err)
+	}
+	sim.Commit()
+	if _, err := bind.WaitDeployed(context.Background(), backend, tx); err != nil {
+		t.Fatalf("failed to wait for deployment: %v", err)
+	}
+	device, _ := bind.NewKeyedTransactorWithChainID(deviceKey, chainID)
+	client, err := NewClient(deployed.Address(), backend, device)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return sim, client
+}
+
+// Tests that a device registers itself and publishes readings in batches with
+// locally managed nonces.
+func TestPublish(t *testing.T) {
+	sim, client := newTestClient(t)
+	ctx := context.Background()
+
+	if registered, err := client.Registered(ctx); err != nil || registered {
+		t.Fatalf("device registered before registration: %v, %v", registered, err)
+	}
+	if _, err := client.Register(ctx, "station-1", "roof"); err != nil {
+		t.Fatalf("failed to register device: %v", err)
+	}
+	sim.Commit()
+
+	// Publish readings back to back without mining, the later value of a
+	// sensor wins and the batches get consecutive nonces
+	client.BatchSize = 2
+	txs, err := client.Publish(ctx, []Reading{
+		{Sensor: "temperature", Value: "20.5"},
+		{Sensor: "humidity", Value: "40"},
+		{Sensor: "pressure", Value: "1013"},
+		{Sensor: "temperature", Value: "21.0"},
+	})
+	if err != nil {
+		t.Fatalf("failed to publish readings: %v", err)
+	}
+	if len(txs) != 2 {
+		t.Fatalf("batch count mismatch: have %d, want 2", len(txs))
+	}
+	for i, tx := range txs {
+		if tx.Nonce() != uint64(i+1) {
+			t.Errorf("batch %d: nonce mismatch: have %d, want %d", i, tx.Nonce(), i+1)
+		}
+	}
+	sim.Commit()
+
+	for _, tx := range txs {
+		if _, err := client.Wait(ctx, tx); err != nil {
+			t.Fatalf("batch failed: %v", err)
+		}
+	}
+	if registered, err := client.Registered(ctx); err != nil || !registered {
+		t.Fatalf("device not registered: %v, %v", registered, err)
+	}
+	value, _, changes, err := client.Latest(ctx, deviceAddr, "temperature")
+	if err != nil {
+		t.Fatalf("failed to retrieve sensor data: %v", err)
+	}
+	if value != "21.0" || changes != 1 {
+		t.Errorf("sensor data mismatch: have %s (%d changes), want 21.0 (1 change)", value, changes)
+	}
+	// Invalid readings are rejected before sending anything
+	if _, err := client.Publish(ctx, nil); err != errNoReadings {
+		t.Errorf("empty publish error mismatch: have %v, want %v", err, errNoReadings)
+	}
+	if _, err := client.Publish(ctx, []Reading{{Sensor: "temperature"}}); err != errEmptyReading {
+		t.Errorf("empty reading error mismatch: have %v, want %v", err, errEmptyReading)
+	}
+}
+
+// Tests that the nonce is fetched again if sending a transaction failed.
+func TestNonceRecovery(t *testing.T) {
+	sim, client := newTestClient(t)
+	ctx := context.Background()
+
+	if _, err := client.Register(ctx, "meter-1", "basement"); err != nil {
+		t.Fatalf("failed to register device: %v", err)
+	}
+	sim.Commit()
+
+	// Simulate a send failure with a gas limit below the intrinsic gas
+	client.opts.GasLimit = 1
+	if _, err := client.Publish(ctx, []Reading{{Sensor: "power", Value: "1.2"}}); err == nil {
+		t.Fatalf("publish with insufficient gas succeeded")
+	}
+	client.opts.GasLimit = 0
+
+	// Use up the next nonce behind the client's back, it must pick the one
+	// after that up from the pending state
+	gasPrice, err := sim.Client().SuggestGasPrice(ctx)
+	if err != nil {
+		t.Fatalf("failed to suggest gas price: %v", err)
+	}
+	transfer, _ := client.opts.Signer(deviceAddr, types.NewTransaction(1, ownerAddr, big.NewInt(1), params.TxGas, gasPrice, nil))
+	if err := sim.Client().SendTransaction(ctx, transfer); err != nil {
+		t.Fatalf("failed to send transfer: %v", err)
+	}
+	txs, err := client.Publish(ctx, []Reading{{Sensor: "power", Value: "1.2"}})
+	if err != nil {
+		t.Fatalf("failed to publish readings: %v", err)
+	}
+	if txs[0].Nonce() != 2 {
+		t.Errorf("nonce mismatch: have %d, want 2", txs[0].Nonce())
+	}
+	sim.Commit()
+	if _, err := client.Wait(ctx, txs[0]); err != nil {
+		t.Fatalf("batch failed: %v", err)
+	}
+}
+
+// Tests that the gas estimates of consecutive updates of a sensor cover the
+// timestamp write, which is free in the block the previous update was mined in.
+func TestPublishGasMargin(t *testing.T) {
+	sim, client := newTestClient(t)
+	ctx := context.Background()
+
+	if _, err := client.Register(ctx, "meter-1", "basement"); err != nil {
+		t.Fatalf("failed to register device: %v", err)
+	}
+	sim.Commit()
+
+	for i, value := range []string{"1.2", "1.3", "1.4"} {
+		txs, err := client.Publish(ctx, []Reading{{Sensor: "power", Value: value}, {Sensor: "voltage", Value: value}})
+		if err != nil {
+			t.Fatalf("update %d: failed to publish readings: %v", i, err)
+		}
+		sim.Commit()
+		if _, err := client.Wait(ctx, txs[0]); err != nil {
+			t.Fatalf("update %d: batch failed: %v", i, err)
+		}
+	}
+}
+
+// Tests that the sensor value changes of a device are delivered to watchers.
+func TestWatchChanges(t *testing.T) {
+	sim, client := newTestClient(t)
+	ctx := context.Background()
+
+	sink := make(chan *DataChange, 4)
+	sub, err := client.WatchChanges(ctx, sink, deviceAddr)
+	if err != nil {
+		t.Fatalf("failed to watch changes: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	if _, err := client.Register(ctx, "sensor-1", "hall"); err != nil {
+		t.Fatalf("failed to register device: %v", err)
+	}
+	sim.Commit()
+
+	// The unchanged value of the second batch must not emit an event
+	for _, value := range []string{"open", "open", "closed"} {
+		if _, err := client.Publish(ctx, []Reading{{Sensor: "door", Value: value}}); err != nil {
+			t.Fatalf("failed to publish reading: %v", err)
+		}
+	}
+	sim.Commit()
+
+	for _, want := range []string{"open", "closed"} {
+		select {
+		case change := <-sink:
+			if change.Device != deviceAddr || change.Value != want {
+				t.Errorf("change mismatch: have %x %s, want %x %s", change.Device, change.Value, deviceAddr, want)
+			}
+			if change.Sensor != crypto.Keccak256Hash([]byte("door")) {
+				t.Errorf("sensor topic mismatch: have %x", change.Sensor)
+			}
+		case err := <-sub.Err():
+			t.Fatalf("subscription failed: %v", err)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("change %s not delivered", want)
+		}
+	}
+	select {
+	case change := <-sink:
+		t.Errorf("unexpected change: %s", change.Value)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
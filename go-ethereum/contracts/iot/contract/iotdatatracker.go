@@ -0,0 +1,1120 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package contract
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// IoTDataTrackerMetaData contains all meta data concerning the IoTDataTracker contract.
+var IoTDataTrackerMetaData = &bind.MetaData{
+	ABI: "[{\"inputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"device\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"string\",\"name\":\"sensor\",\"type\":\"string\"},{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"timestamp\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"bytes32\",\"name\":\"valueHash\",\"type\":\"bytes32\"},{\"indexed\":false,\"internalType\":\"string\",\"name\":\"value\",\"type\":\"string\"}],\"name\":\"DataChanged\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"device\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"string\",\"name\":\"deviceId\",\"type\":\"string\"},{\"indexed\":false,\"internalType\":\"string\",\"name\":\"location\",\"type\":\"string\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"timestamp\",\"type\":\"uint256\"}],\"name\":\"DeviceRegistered\",\"type\":\"event\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"name\":\"changeHashes\",\"outputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"name\":\"dataHistory\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"sensor\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"value\",\"type\":\"string\"},{\"internalType\":\"uint256\",\"name\":\"timestamp\",\"type\":\"uint256\"},{\"internalType\":\"address\",\"name\":\"device\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_device\",\"type\":\"address\"}],\"name\":\"deactivateDevice\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"name\":\"deviceList\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"name\":\"devices\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"deviceId\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"location\",\"type\":\"string\"},{\"internalType\":\"uint256\",\"name\":\"registrationTime\",\"type\":\"uint256\"},{\"internalType\":\"bool\",\"name\":\"isActive\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"getChangeCount\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_changeHash\",\"type\":\"bytes32\"}],\"name\":\"getDataChange\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"sensor\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"value\",\"type\":\"string\"},{\"internalType\":\"uint256\",\"name\":\"timestamp\",\"type\":\"uint256\"},{\"internalType\":\"address\",\"name\":\"device\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"getDeviceCount\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_device\",\"type\":\"address\"}],\"name\":\"getDeviceInfo\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"deviceId\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"location\",\"type\":\"string\"},{\"internalType\":\"uint256\",\"name\":\"registrationTime\",\"type\":\"uint256\"},{\"internalType\":\"bool\",\"name\":\"isActive\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_device\",\"type\":\"address\"}],\"name\":\"getDeviceSensors\",\"outputs\":[{\"internalType\":\"string[]\",\"name\":\"sensors\",\"type\":\"string[]\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_device\",\"type\":\"address\"},{\"internalType\":\"string\",\"name\":\"_sensor\",\"type\":\"string\"},{\"internalType\":\"uint256\",\"name\":\"_limit\",\"type\":\"uint256\"}],\"name\":\"getRecentChanges\",\"outputs\":[{\"internalType\":\"string[]\",\"name\":\"values\",\"type\":\"string[]\"},{\"internalType\":\"uint256[]\",\"name\":\"timestamps\",\"type\":\"uint256[]\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_device\",\"type\":\"address\"},{\"internalType\":\"string\",\"name\":\"_sensor\",\"type\":\"string\"}],\"name\":\"getSensorData\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"value\",\"type\":\"string\"},{\"internalType\":\"uint256\",\"name\":\"timestamp\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"changeCount\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"owner\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_device\",\"type\":\"address\"}],\"name\":\"reactivateDevice\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"_deviceId\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"_location\",\"type\":\"string\"}],\"name\":\"registerDevice\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"string[]\",\"name\":\"_sensors\",\"type\":\"string[]\"},{\"internalType\":\"string[]\",\"name\":\"_values\",\"type\":\"string[]\"}],\"name\":\"updateMultipleSensors\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"_sensor\",\"type\":\"string\"},{\"internalType\":\"string\",\"name\":\"_value\",\"type\":\"string\"}],\"name\":\"updateSensorData\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]",
+	Bin: "0x6080604052348015600f57600080fd5b50600480546001600160a01b03191633179055611d7d806100316000396000f3fe608060405234801561001057600080fd5b506004361061010b5760003560e01c80638d8449f1116100a2578063a5fcd25611610071578063a5fcd25614610255578063cf65952c14610268578063d425b8491461027b578063e7b4cac61461028e578063f7de1b8f146102a157600080fd5b80638d8449f1146101f15780638da5cb5b14610204578063979ebb421461022f5780639c6bd4ff1461024257600080fd5b806344e0841e116100de57806344e0841e1461018f5780634869fe26146101975780634c32f347146101ba57806353494f9f146101cf57600080fd5b806313d236b3146101105780632ccea9ef1461013a5780633792546c1461015a57806337a0701a1461017d575b600080fd5b61012361011e366004611612565b6102b4565b604051610131929190611715565b60405180910390f35b61014d610148366004611763565b61067c565b6040516101319190611785565b61016d610168366004611798565b6107cd565b60405161013194939291906117b1565b6002545b604051908152602001610131565b600354610181565b6101aa6101a5366004611763565b61092d565b60405161013194939291906117f8565b6101cd6101c8366004611836565b610975565b005b6101e26101dd36600461189d565b610af2565b604051610131939291906118d4565b6101cd6101ff366004611836565b610c88565b600454610217906001600160a01b031681565b6040516001600160a01b039091168152602001610131565b61021761023d366004611798565b610cc8565b61016d610250366004611798565b610cf2565b6101cd61026336600461199f565b610e34565b6101cd610276366004611763565b610f56565b6101cd610289366004611763565b611003565b6101aa61029c366004611763565b61112a565b6101816102af366004611798565b611266565b6060806000831180156102c8575060648311155b6103195760405162461bcd60e51b815260206004820152601f60248201527f4c696d6974206d757374206265206265747765656e203120616e64203130300060448201526064015b60405180910390fd5b600354600090815b818110801561032f57508583105b1561041757600081610342600185611a12565b61034c9190611a12565b90506000600160006003848154811061036757610367611a2b565b60009182526020808320909101548352820192909252604001902060038101549091506001600160a01b03908116908b161480156103ef5750886040516020016103b19190611a41565b60408051601f19818403018152908290528051602091820120916103d791849101611a97565b60405160208183030381529060405280519060200120145b1561040257846103fe81611b0c565b9550505b5050808061040f90611b0c565b915050610321565b50816001600160401b038111156104305761043061155d565b60405190808252806020026020018201604052801561046357816020015b606081526020019060019003908161044e5790505b509350816001600160401b0381111561047e5761047e61155d565b6040519080825280602002602001820160405280156104a7578160200160208202803683370190505b5092506000805b82811080156104bc57508382105b15610670576000816104cf600186611a12565b6104d99190611a12565b9050600060016000600384815481106104f4576104f4611a2b565b60009182526020808320909101548352820192909252604001902060038101549091506001600160a01b03908116908c1614801561057c57508960405160200161053e9190611a41565b60408051601f198184030181529082905280516020918201209161056491849101611a97565b60405160208183030381529060405280519060200120145b1561065b5780600101805461059090611a5d565b80601f01602080910402602001604051908101604052809291908181526020018280546105bc90611a5d565b80156106095780601f106105de57610100808354040283529160200191610609565b820191906000526020600020905b8154815290600101906020018083116105ec57829003601f168201915b505050505088858151811061062057610620611a2b565b6020026020010181905250806002015487858151811061064257610642611a2b565b60209081029190910101528361065781611b0c565b9450505b5050808061066890611b0c565b9150506104ae565b50505050935093915050565b6001600160a01b03811660009081526020819052604090206003015460609060ff166106e25760405162461bcd60e51b815260206004820152601560248201527411195d9a58d9481b9bdd081c9959da5cdd195c9959605a1b6044820152606401610310565b6001600160a01b03821660009081526020818152604080832060050180548251818502810185019093528083529193909284015b828210156107c257838290600052602060002001805461073590611a5d565b80601f016020809104026020016040519081016040528092919081815260200182805461076190611a5d565b80156107ae5780601f10610783576101008083540402835291602001916107ae565b820191906000526020600020905b81548152906001019060200180831161079157829003601f168201915b505050505081526020019060010190610716565b505050509050919050565b6000818152600160208190526040822060028101546003820154825460609586959094859490938493928401926001600160a01b0390911690849061081190611a5d565b80601f016020809104026020016040519081016040528092919081815260200182805461083d90611a5d565b801561088a5780601f1061085f5761010080835404028352916020019161088a565b820191906000526020600020905b81548152906001019060200180831161086d57829003601f168201915b5050505050935082805461089d90611a5d565b80601f01602080910402602001604051908101604052809291908181526020018280546108c990611a5d565b80156109165780601f106108eb57610100808354040283529160200191610916565b820191906000526020600020905b8154815290600101906020018083116108f957829003601f168201915b505050505092509450945094509450509193509193565b6001600160a01b03811660009081526020819052604081206002810154600382015482546060948594909384939192839260018401929160ff90911690849061081190611a5d565b3360009081526020819052604090206003015460ff16156109d85760405162461bcd60e51b815260206004820152601960248201527f44657669636520616c72656164792072656769737465726564000000000000006044820152606401610310565b6000825111610a295760405162461bcd60e51b815260206004820152601960248201527f4465766963652049442063616e6e6f7420626520656d707479000000000000006044820152606401610310565b33600090815260208190526040902080610a438482611b6c565b5060018101610a528382611b6c565b5042600280830182905560038301805460ff19166001908117909155815490810182556000919091527f405787fa12a823e0f2b7631cc41b3ba8828b3321ca811111fa75cd3aa3bb5ace0180546001600160a01b0319163390811790915560405190917ff433a0d4cb94bbe6bce9d2720fd6aa2a8546da64a568af901f70331496e70c6a91610ae5918791879190611c2a565b60405180910390a2505050565b6001600160a01b03821660009081526020819052604081206003015460609190819060ff16610b5b5760405162461bcd60e51b815260206004820152601560248201527411195d9a58d9481b9bdd081c9959da5cdd195c9959605a1b6044820152606401610310565b6001600160a01b0385166000908152602081905260408082209051600490910190610b87908790611a41565b908152604051908190036020019020600481015490915060ff16610be05760405162461bcd60e51b815260206004820152601060248201526f14d95b9cdbdc881b9bdd08199bdd5b9960821b6044820152606401610310565b8060000181600101548260030154828054610bfa90611a5d565b80601f0160208091040260200160405190810160405280929190818152602001828054610c2690611a5d565b8015610c735780601f10610c4857610100808354040283529160200191610c73565b820191906000526020600020905b815481529060010190602001808311610c5657829003601f168201915b50505050509250935093509350509250925092565b3360009081526020819052604090206003015460ff16610cba5760405162461bcd60e51b815260040161031090611c60565b610cc48282611287565b5050565b60028181548110610cd857600080fd5b6000918252602090912001546001600160a01b0316905081565b600160205260009081526040902080548190610d0d90611a5d565b80601f0160208091040260200160405190810160405280929190818152602001828054610d3990611a5d565b8015610d865780601f10610d5b57610100808354040283529160200191610d86565b820191906000526020600020905b815481529060010190602001808311610d6957829003601f168201915b505050505090806001018054610d9b90611a5d565b80601f0160208091040260200160405190810160405280929190818152602001828054610dc790611a5d565b8015610e145780601f10610de957610100808354040283529160200191610e14565b820191906000526020600020905b815481529060010190602001808311610df757829003601f168201915b5050505060028301546003909301549192916001600160a01b0316905084565b3360009081526020819052604090206003015460ff16610e665760405162461bcd60e51b815260040161031090611c60565b8051825114610eb05760405162461bcd60e51b8152602060048201526016602482015275082e4e4c2f2e640d8cadccee8d040dad2e6dac2e8c6d60531b6044820152606401610310565b6000825111610f015760405162461bcd60e51b815260206004820152601860248201527f456d70747920617272617973206e6f7420616c6c6f77656400000000000000006044820152606401610310565b60005b8251811015610f5157610f49838281518110610f2257610f22611a2b565b6020026020010151838381518110610f3c57610f3c611a2b565b6020026020010151611287565b600101610f04565b505050565b6004546001600160a01b03163314610f805760405162461bcd60e51b815260040161031090611ca1565b6001600160a01b03811660009081526020819052604090206003015460ff16610fdf5760405162461bcd60e51b8152602060048201526011602482015270446576696365206e6f742061637469766560781b6044820152606401610310565b6001600160a01b03166000908152602081905260409020600301805460ff19169055565b6004546001600160a01b0316331461102d5760405162461bcd60e51b815260040161031090611ca1565b6001600160a01b03811660009081526020819052604090206003015460ff16156110915760405162461bcd60e51b815260206004820152601560248201527444657669636520616c72656164792061637469766560581b6044820152606401610310565b6001600160a01b038116600090815260208190526040812080546110b490611a5d565b9050116111035760405162461bcd60e51b815260206004820152601760248201527f446576696365206e6576657220726567697374657265640000000000000000006044820152606401610310565b6001600160a01b03166000908152602081905260409020600301805460ff19166001179055565b60006020819052908152604090208054819061114590611a5d565b80601f016020809104026020016040519081016040528092919081815260200182805461117190611a5d565b80156111be5780601f10611193576101008083540402835291602001916111be565b820191906000526020600020905b8154815290600101906020018083116111a157829003601f168201915b5050505050908060010180546111d390611a5d565b80601f01602080910402602001604051908101604052809291908181526020018280546111ff90611a5d565b801561124c5780601f106112215761010080835404028352916020019161124c565b820191906000526020600020905b81548152906001019060200180831161122f57829003601f168201915b50505050600283015460039093015491929160ff16905084565b6003818154811061127657600080fd5b600091825260209091200154905081565b60008251116112d85760405162461bcd60e51b815260206004820152601b60248201527f53656e736f72206e616d652063616e6e6f7420626520656d70747900000000006044820152606401610310565b60008151116113215760405162461bcd60e51b815260206004820152601560248201527456616c75652063616e6e6f7420626520656d70747960581b6044820152606401610310565b6000816040516020016113349190611a41565b60408051601f198184030181528282528051602091820120336000908152918290529181209193509091600483019061136e908790611a41565b908152604051908190036020019020600481015490915060ff166113be5760058201805460018101825560009182526020909120016113ad8682611b6c565b5060048101805460ff191660011790555b8281600201541461153a57806113d48582611b6c565b50426001820155600281018390556003810180549060006113f483611b0c565b91905055506000338642866040516020016114129493929190611ce2565b60408051601f19818403018152828252805160209182012060808401835289845283820189905242848401523360608501526000818152600190925291902082519193509081906114639082611b6c565b50602082015160018201906114789082611b6c565b506040828101516002830155606090920151600391820180546001600160a01b0319166001600160a01b0390921691909117905580546001810182556000919091527fc2575a0e9e593c00f959f8c92f12db2869c3395a3b0502d05e2516446f71f85b018290555142906114ed908890611a41565b6040518091039020336001600160a01b03167f591a69c1400d4ef641b45b3bd07d167b2bb861f9c7acb0073dab2e81211b4ca28789604051611530929190611d26565b60405180910390a4505b5050505050565b80356001600160a01b038116811461155857600080fd5b919050565b634e487b7160e01b600052604160045260246000fd5b604051601f8201601f191681016001600160401b038111828210171561159b5761159b61155d565b604052919050565b600082601f8301126115b457600080fd5b81356001600160401b038111156115cd576115cd61155d565b6115e0601f8201601f1916602001611573565b8181528460208386010111156115f557600080fd5b816020850160208301376000918101602001919091529392505050565b60008060006060848603121561162757600080fd5b61163084611541565b925060208401356001600160401b0381111561164b57600080fd5b611657868287016115a3565b93969395505050506040919091013590565b60005b8381101561168457818101518382015260200161166c565b50506000910152565b600081518084526116a5816020860160208601611669565b601f01601f19169290920160200192915050565b600082825180855260208501945060208160051b8301016020850160005b8381101561170957601f198584030188526116f383835161168d565b60209889019890935091909101906001016116d7565b50909695505050505050565b60408152600061172860408301856116b9565b828103602084015280845180835260208301915060208601925060005b81811015611709578351835260209384019390920191600101611745565b60006020828403121561177557600080fd5b61177e82611541565b9392505050565b60208152600061177e60208301846116b9565b6000602082840312156117aa57600080fd5b5035919050565b6080815260006117c4608083018761168d565b82810360208401526117d6818761168d565b604084019590955250506001600160a01b039190911660609091015292915050565b60808152600061180b608083018761168d565b828103602084015261181d818761168d565b6040840195909552505090151560609091015292915050565b6000806040838503121561184957600080fd5b82356001600160401b0381111561185f57600080fd5b61186b858286016115a3565b92505060208301356001600160401b0381111561188757600080fd5b611893858286016115a3565b9150509250929050565b600080604083850312156118b057600080fd5b6118b983611541565b915060208301356001600160401b0381111561188757600080fd5b6060815260006118e7606083018661168d565b60208301949094525060400152919050565b600082601f83011261190a57600080fd5b81356001600160401b038111156119235761192361155d565b8060051b61193360208201611573565b9182526020818501810192908101908684111561194f57600080fd5b6020860192505b838310156119955782356001600160401b0381111561197457600080fd5b611983886020838a01016115a3565b83525060209283019290910190611956565b9695505050505050565b600080604083850312156119b257600080fd5b82356001600160401b038111156119c857600080fd5b6119d4858286016118f9565b92505060208301356001600160401b038111156119f057600080fd5b611893858286016118f9565b634e487b7160e01b600052601160045260246000fd5b81810381811115611a2557611a256119fc565b92915050565b634e487b7160e01b600052603260045260246000fd5b60008251611a53818460208701611669565b9190910192915050565b600181811c90821680611a7157607f821691505b602082108103611a9157634e487b7160e01b600052602260045260246000fd5b50919050565b6000808354611aa581611a5d565b600182168015611abc5760018114611ad157611b01565b60ff1983168652811515820286019350611b01565b86600052602060002060005b83811015611af957815488820152600190910190602001611add565b505081860193505b509195945050505050565b600060018201611b1e57611b1e6119fc565b5060010190565b601f821115610f5157806000526020600020601f840160051c81016020851015611b4c5750805b601f840160051c820191505b8181101561153a5760008155600101611b58565b81516001600160401b03811115611b8557611b8561155d565b611b9981611b938454611a5d565b84611b25565b6020601f821160018114611bcd5760008315611bb55750848201515b600019600385901b1c1916600184901b17845561153a565b600084815260208120601f198516915b82811015611bfd5787850151825560209485019460019092019101611bdd565b5084821015611c1b5786840151600019600387901b60f8161c191681555b50505050600190811b01905550565b606081526000611c3d606083018661168d565b8281036020840152611c4f818661168d565b915050826040830152949350505050565b60208082526021908201527f446576696365206e6f742072656769737465726564206f7220696e61637469766040820152606560f81b606082015260800190565b60208082526021908201527f4f6e6c79206f776e65722063616e2063616c6c20746869732066756e6374696f6040820152603760f91b606082015260800190565b6bffffffffffffffffffffffff198560601b16815260008451611d0c816014850160208901611669565b909101601481019390935250603482015260540192915050565b828152604060208201526000611d3f604083018461168d565b94935050505056fea2646970667358221220a2eacd03608aa167297b45137143f8b4d1e05568461b3bdc3952dc9906c031aa64736f6c634300081e0033",
+}
+
+// IoTDataTrackerABI is the input ABI used to generate the binding from.
+// Deprecated: Use IoTDataTrackerMetaData.ABI instead.
+var IoTDataTrackerABI = IoTDataTrackerMetaData.ABI
+
+// IoTDataTrackerBin is the compiled bytecode used for deploying new contracts.
+// Deprecated: Use IoTDataTrackerMetaData.Bin instead.
+var IoTDataTrackerBin = IoTDataTrackerMetaData.Bin
+
+// DeployIoTDataTracker deploys a new Ethereum contract, binding an instance of IoTDataTracker to it.
+func DeployIoTDataTracker(auth *bind.TransactOpts, backend bind.ContractBackend) (common.Address, *types.Transaction, *IoTDataTracker, error) {
+	parsed, err := IoTDataTrackerMetaData.GetAbi()
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	if parsed == nil {
+		return common.Address{}, nil, nil, errors.New("GetABI returned nil")
+	}
+
+	address, tx, contract, err := bind.DeployContract(auth, *parsed, common.FromHex(IoTDataTrackerBin), backend)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	return address, tx, &IoTDataTracker{IoTDataTrackerCaller: IoTDataTrackerCaller{contract: contract}, IoTDataTrackerTransactor: IoTDataTrackerTransactor{contract: contract}, IoTDataTrackerFilterer: IoTDataTrackerFilterer{contract: contract}}, nil
+}
+
+// IoTDataTracker is an auto generated Go binding around an Ethereum contract.
+type IoTDataTracker struct {
+	IoTDataTrackerCaller     // Read-only binding to the contract
+	IoTDataTrackerTransactor // Write-only binding to the contract
+	IoTDataTrackerFilterer   // Log filterer for contract events
+}
+
+// IoTDataTrackerCaller is an auto generated read-only Go binding around an Ethereum contract.
+type IoTDataTrackerCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// IoTDataTrackerTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type IoTDataTrackerTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// IoTDataTrackerFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type IoTDataTrackerFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// IoTDataTrackerSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type IoTDataTrackerSession struct {
+	Contract     *IoTDataTracker   // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// IoTDataTrackerCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type IoTDataTrackerCallerSession struct {
+	Contract *IoTDataTrackerCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts         // Call options to use throughout this session
+}
+
+// IoTDataTrackerTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type IoTDataTrackerTransactorSession struct {
+	Contract     *IoTDataTrackerTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts         // Transaction auth options to use throughout this session
+}
+
+// IoTDataTrackerRaw is an auto generated low-level Go binding around an Ethereum contract.
+type IoTDataTrackerRaw struct {
+	Contract *IoTDataTracker // Generic contract binding to access the raw methods on
+}
+
+// IoTDataTrackerCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type IoTDataTrackerCallerRaw struct {
+	Contract *IoTDataTrackerCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// IoTDataTrackerTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type IoTDataTrackerTransactorRaw struct {
+	Contract *IoTDataTrackerTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewIoTDataTracker creates a new instance of IoTDataTracker, bound to a specific deployed contract.
+func NewIoTDataTracker(address common.Address, backend bind.ContractBackend) (*IoTDataTracker, error) {
+	contract, err := bindIoTDataTracker(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &IoTDataTracker{IoTDataTrackerCaller: IoTDataTrackerCaller{contract: contract}, IoTDataTrackerTransactor: IoTDataTrackerTransactor{contract: contract}, IoTDataTrackerFilterer: IoTDataTrackerFilterer{contract: contract}}, nil
+}
+
+// NewIoTDataTrackerCaller creates a new read-only instance of IoTDataTracker, bound to a specific deployed contract.
+func NewIoTDataTrackerCaller(address common.Address, caller bind.ContractCaller) (*IoTDataTrackerCaller, error) {
+	contract, err := bindIoTDataTracker(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &IoTDataTrackerCaller{contract: contract}, nil
+}
+
+// NewIoTDataTrackerTransactor creates a new write-only instance of IoTDataTracker, bound to a specific deployed contract.
+func NewIoTDataTrackerTransactor(address common.Address, transactor bind.ContractTransactor) (*IoTDataTrackerTransactor, error) {
+	contract, err := bindIoTDataTracker(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &IoTDataTrackerTransactor{contract: contract}, nil
+}
+
+// NewIoTDataTrackerFilterer creates a new log filterer instance of IoTDataTracker, bound to a specific deployed contract.
+func NewIoTDataTrackerFilterer(address common.Address, filterer bind.ContractFilterer) (*IoTDataTrackerFilterer, error) {
+	contract, err := bindIoTDataTracker(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &IoTDataTrackerFilterer{contract: contract}, nil
+}
+
+// bindIoTDataTracker binds a generic wrapper to an already deployed contract.
+func bindIoTDataTracker(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := IoTDataTrackerMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_IoTDataTracker *IoTDataTrackerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _IoTDataTracker.Contract.IoTDataTrackerCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_IoTDataTracker *IoTDataTrackerRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _IoTDataTracker.Contract.IoTDataTrackerTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_IoTDataTracker *IoTDataTrackerRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _IoTDataTracker.Contract.IoTDataTrackerTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_IoTDataTracker *IoTDataTrackerCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _IoTDataTracker.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_IoTDataTracker *IoTDataTrackerTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _IoTDataTracker.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_IoTDataTracker *IoTDataTrackerTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _IoTDataTracker.Contract.contract.Transact(opts, method, params...)
+}
+
+// ChangeHashes is a free data retrieval call binding the contract method 0xf7de1b8f.
+//
+// Solidity: function changeHashes(uint256 ) view returns(bytes32)
+func (_IoTDataTracker *IoTDataTrackerCaller) ChangeHashes(opts *bind.CallOpts, arg0 *big.Int) ([32]byte, error) {
+	var out []interface{}
+	err := _IoTDataTracker.contract.Call(opts, &out, "changeHashes", arg0)
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// ChangeHashes is a free data retrieval call binding the contract method 0xf7de1b8f.
+//
+// Solidity: function changeHashes(uint256 ) view returns(bytes32)
+func (_IoTDataTracker *IoTDataTrackerSession) ChangeHashes(arg0 *big.Int) ([32]byte, error) {
+	return _IoTDataTracker.Contract.ChangeHashes(&_IoTDataTracker.CallOpts, arg0)
+}
+
+// ChangeHashes is a free data retrieval call binding the contract method 0xf7de1b8f.
+//
+// Solidity: function changeHashes(uint256 ) view returns(bytes32)
+func (_IoTDataTracker *IoTDataTrackerCallerSession) ChangeHashes(arg0 *big.Int) ([32]byte, error) {
+	return _IoTDataTracker.Contract.ChangeHashes(&_IoTDataTracker.CallOpts, arg0)
+}
+
+// DataHistory is a free data retrieval call binding the contract method 0x9c6bd4ff.
+//
+// Solidity: function dataHistory(bytes32 ) view returns(string sensor, string value, uint256 timestamp, address device)
+func (_IoTDataTracker *IoTDataTrackerCaller) DataHistory(opts *bind.CallOpts, arg0 [32]byte) (struct {
+	Sensor    string
+	Value     string
+	Timestamp *big.Int
+	Device    common.Address
+}, error) {
+	var out []interface{}
+	err := _IoTDataTracker.contract.Call(opts, &out, "dataHistory", arg0)
+
+	outstruct := new(struct {
+		Sensor    string
+		Value     string
+		Timestamp *big.Int
+		Device    common.Address
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.Sensor = *abi.ConvertType(out[0], new(string)).(*string)
+	outstruct.Value = *abi.ConvertType(out[1], new(string)).(*string)
+	outstruct.Timestamp = *abi.ConvertType(out[2], new(*big.Int)).(**big.Int)
+	outstruct.Device = *abi.ConvertType(out[3], new(common.Address)).(*common.Address)
+
+	return *outstruct, err
+
+}
+
+// DataHistory is a free data retrieval call binding the contract method 0x9c6bd4ff.
+//
+// Solidity: function dataHistory(bytes32 ) view returns(string sensor, string value, uint256 timestamp, address device)
+func (_IoTDataTracker *IoTDataTrackerSession) DataHistory(arg0 [32]byte) (struct {
+	Sensor    string
+	Value     string
+	Timestamp *big.Int
+	Device    common.Address
+}, error) {
+	return _IoTDataTracker.Contract.DataHistory(&_IoTDataTracker.CallOpts, arg0)
+}
+
+// DataHistory is a free data retrieval call binding the contract method 0x9c6bd4ff.
+//
+// Solidity: function dataHistory(bytes32 ) view returns(string sensor, string value, uint256 timestamp, address device)
+func (_IoTDataTracker *IoTDataTrackerCallerSession) DataHistory(arg0 [32]byte) (struct {
+	Sensor    string
+	Value     string
+	Timestamp *big.Int
+	Device    common.Address
+}, error) {
+	return _IoTDataTracker.Contract.DataHistory(&_IoTDataTracker.CallOpts, arg0)
+}
+
+// DeviceList is a free data retrieval call binding the contract method 0x979ebb42.
+//
+// Solidity: function deviceList(uint256 ) view returns(address)
+func (_IoTDataTracker *IoTDataTrackerCaller) DeviceList(opts *bind.CallOpts, arg0 *big.Int) (common.Address, error) {
+	var out []interface{}
+	err := _IoTDataTracker.contract.Call(opts, &out, "deviceList", arg0)
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// DeviceList is a free data retrieval call binding the contract method 0x979ebb42.
+//
+// Solidity: function deviceList(uint256 ) view returns(address)
+func (_IoTDataTracker *IoTDataTrackerSession) DeviceList(arg0 *big.Int) (common.Address, error) {
+	return _IoTDataTracker.Contract.DeviceList(&_IoTDataTracker.CallOpts, arg0)
+}
+
+// DeviceList is a free data retrieval call binding the contract method 0x979ebb42.
+//
+// Solidity: function deviceList(uint256 ) view returns(address)
+func (_IoTDataTracker *IoTDataTrackerCallerSession) DeviceList(arg0 *big.Int) (common.Address, error) {
+	return _IoTDataTracker.Contract.DeviceList(&_IoTDataTracker.CallOpts, arg0)
+}
+
+// Devices is a free data retrieval call binding the contract method 0xe7b4cac6.
+//
+// Solidity: function devices(address ) view returns(string deviceId, string location, uint256 registrationTime, bool isActive)
+func (_IoTDataTracker *IoTDataTrackerCaller) Devices(opts *bind.CallOpts, arg0 common.Address) (struct {
+	DeviceId         string
+	Location         string
+	RegistrationTime *big.Int
+	IsActive         bool
+}, error) {
+	var out []interface{}
+	err := _IoTDataTracker.contract.Call(opts, &out, "devices", arg0)
+
+	outstruct := new(struct {
+		DeviceId         string
+		Location         string
+		RegistrationTime *big.Int
+		IsActive         bool
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.DeviceId = *abi.ConvertType(out[0], new(string)).(*string)
+	outstruct.Location = *abi.ConvertType(out[1], new(string)).(*string)
+	outstruct.RegistrationTime = *abi.ConvertType(out[2], new(*big.Int)).(**big.Int)
+	outstruct.IsActive = *abi.ConvertType(out[3], new(bool)).(*bool)
+
+	return *outstruct, err
+
+}
+
+// Devices is a free data retrieval call binding the contract method 0xe7b4cac6.
+//
+// Solidity: function devices(address ) view returns(string deviceId, string location, uint256 registrationTime, bool isActive)
+func (_IoTDataTracker *IoTDataTrackerSession) Devices(arg0 common.Address) (struct {
+	DeviceId         string
+	Location         string
+	RegistrationTime *big.Int
+	IsActive         bool
+}, error) {
+	return _IoTDataTracker.Contract.Devices(&_IoTDataTracker.CallOpts, arg0)
+}
+
+// Devices is a free data retrieval call binding the contract method 0xe7b4cac6.
+//
+// Solidity: function devices(address ) view returns(string deviceId, string location, uint256 registrationTime, bool isActive)
+func (_IoTDataTracker *IoTDataTrackerCallerSession) Devices(arg0 common.Address) (struct {
+	DeviceId         string
+	Location         string
+	RegistrationTime *big.Int
+	IsActive         bool
+}, error) {
+	return _IoTDataTracker.Contract.Devices(&_IoTDataTracker.CallOpts, arg0)
+}
+
+// GetChangeCount is a free data retrieval call binding the contract method 0x44e0841e.
+//
+// Solidity: function getChangeCount() view returns(uint256)
+func (_IoTDataTracker *IoTDataTrackerCaller) GetChangeCount(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _IoTDataTracker.contract.Call(opts, &out, "getChangeCount")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// GetChangeCount is a free data retrieval call binding the contract method 0x44e0841e.
+//
+// Solidity: function getChangeCount() view returns(uint256)
+func (_IoTDataTracker *IoTDataTrackerSession) GetChangeCount() (*big.Int, error) {
+	return _IoTDataTracker.Contract.GetChangeCount(&_IoTDataTracker.CallOpts)
+}
+
+// GetChangeCount is a free data retrieval call binding the contract method 0x44e0841e.
+//
+// Solidity: function getChangeCount() view returns(uint256)
+func (_IoTDataTracker *IoTDataTrackerCallerSession) GetChangeCount() (*big.Int, error) {
+	return _IoTDataTracker.Contract.GetChangeCount(&_IoTDataTracker.CallOpts)
+}
+
+// GetDataChange is a free data retrieval call binding the contract method 0x3792546c.
+//
+// Solidity: function getDataChange(bytes32 _changeHash) view returns(string sensor, string value, uint256 timestamp, address device)
+func (_IoTDataTracker *IoTDataTrackerCaller) GetDataChange(opts *bind.CallOpts, _changeHash [32]byte) (struct {
+	Sensor    string
+	Value     string
+	Timestamp *big.Int
+	Device    common.Address
+}, error) {
+	var out []interface{}
+	err := _IoTDataTracker.contract.Call(opts, &out, "getDataChange", _changeHash)
+
+	outstruct := new(struct {
+		Sensor    string
+		Value     string
+		Timestamp *big.Int
+		Device    common.Address
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.Sensor = *abi.ConvertType(out[0], new(string)).(*string)
+	outstruct.Value = *abi.ConvertType(out[1], new(string)).(*string)
+	outstruct.Timestamp = *abi.ConvertType(out[2], new(*big.Int)).(**big.Int)
+	outstruct.Device = *abi.ConvertType(out[3], new(common.Address)).(*common.Address)
+
+	return *outstruct, err
+
+}
+
+// GetDataChange is a free data retrieval call binding the contract method 0x3792546c.
+//
+// Solidity: function getDataChange(bytes32 _changeHash) view returns(string sensor, string value, uint256 timestamp, address device)
+func (_IoTDataTracker *IoTDataTrackerSession) GetDataChange(_changeHash [32]byte) (struct {
+	Sensor    string
+	Value     string
+	Timestamp *big.Int
+	Device    common.Address
+}, error) {
+	return _IoTDataTracker.Contract.GetDataChange(&_IoTDataTracker.CallOpts, _changeHash)
+}
+
+// GetDataChange is a free data retrieval call binding the contract method 0x3792546c.
+//
+// Solidity: function getDataChange(bytes32 _changeHash) view returns(string sensor, string value, uint256 timestamp, address device)
+func (_IoTDataTracker *IoTDataTrackerCallerSession) GetDataChange(_changeHash [32]byte) (struct {
+	Sensor    string
+	Value     string
+	Timestamp *big.Int
+	Device    common.Address
+}, error) {
+	return _IoTDataTracker.Contract.GetDataChange(&_IoTDataTracker.CallOpts, _changeHash)
+}
+
+// GetDeviceCount is a free data retrieval call binding the contract method 0x37a0701a.
+//
+// Solidity: function getDeviceCount() view returns(uint256)
+func (_IoTDataTracker *IoTDataTrackerCaller) GetDeviceCount(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _IoTDataTracker.contract.Call(opts, &out, "getDeviceCount")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// GetDeviceCount is a free data retrieval call binding the contract method 0x37a0701a.
+//
+// Solidity: function getDeviceCount() view returns(uint256)
+func (_IoTDataTracker *IoTDataTrackerSession) GetDeviceCount() (*big.Int, error) {
+	return _IoTDataTracker.Contract.GetDeviceCount(&_IoTDataTracker.CallOpts)
+}
+
+// GetDeviceCount is a free data retrieval call binding the contract method 0x37a0701a.
+//
+// Solidity: function getDeviceCount() view returns(uint256)
+func (_IoTDataTracker *IoTDataTrackerCallerSession) GetDeviceCount() (*big.Int, error) {
+	return _IoTDataTracker.Contract.GetDeviceCount(&_IoTDataTracker.CallOpts)
+}
+
+// GetDeviceInfo is a free data retrieval call binding the contract method 0x4869fe26.
+//
+// Solidity: function getDeviceInfo(address _device) view returns(string deviceId, string location, uint256 registrationTime, bool isActive)
+func (_IoTDataTracker *IoTDataTrackerCaller) GetDeviceInfo(opts *bind.CallOpts, _device common.Address) (struct {
+	DeviceId         string
+	Location         string
+	RegistrationTime *big.Int
+	IsActive         bool
+}, error) {
+	var out []interface{}
+	err := _IoTDataTracker.contract.Call(opts, &out, "getDeviceInfo", _device)
+
+	outstruct := new(struct {
+		DeviceId         string
+		Location         string
+		RegistrationTime *big.Int
+		IsActive         bool
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.DeviceId = *abi.ConvertType(out[0], new(string)).(*string)
+	outstruct.Location = *abi.ConvertType(out[1], new(string)).(*string)
+	outstruct.RegistrationTime = *abi.ConvertType(out[2], new(*big.Int)).(**big.Int)
+	outstruct.IsActive = *abi.ConvertType(out[3], new(bool)).(*bool)
+
+	return *outstruct, err
+
+}
+
+// GetDeviceInfo is a free data retrieval call binding the contract method 0x4869fe26.
+//
+// Solidity: function getDeviceInfo(address _device) view returns(string deviceId, string location, uint256 registrationTime, bool isActive)
+func (_IoTDataTracker *IoTDataTrackerSession) GetDeviceInfo(_device common.Address) (struct {
+	DeviceId         string
+	Location         string
+	RegistrationTime *big.Int
+	IsActive         bool
+}, error) {
+	return _IoTDataTracker.Contract.GetDeviceInfo(&_IoTDataTracker.CallOpts, _device)
+}
+
+// GetDeviceInfo is a free data retrieval call binding the contract method 0x4869fe26.
+//
+// Solidity: function getDeviceInfo(address _device) view returns(string deviceId, string location, uint256 registrationTime, bool isActive)
+func (_IoTDataTracker *IoTDataTrackerCallerSession) GetDeviceInfo(_device common.Address) (struct {
+	DeviceId         string
+	Location         string
+	RegistrationTime *big.Int
+	IsActive         bool
+}, error) {
+	return _IoTDataTracker.Contract.GetDeviceInfo(&_IoTDataTracker.CallOpts, _device)
+}
+
+// GetDeviceSensors is a free data retrieval call binding the contract method 0x2ccea9ef.
+//
+// Solidity: function getDeviceSensors(address _device) view returns(string[] sensors)
+func (_IoTDataTracker *IoTDataTrackerCaller) GetDeviceSensors(opts *bind.CallOpts, _device common.Address) ([]string, error) {
+	var out []interface{}
+	err := _IoTDataTracker.contract.Call(opts, &out, "getDeviceSensors", _device)
+
+	if err != nil {
+		return *new([]string), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([]string)).(*[]string)
+
+	return out0, err
+
+}
+
+// GetDeviceSensors is a free data retrieval call binding the contract method 0x2ccea9ef.
+//
+// Solidity: function getDeviceSensors(address _device) view returns(string[] sensors)
+func (_IoTDataTracker *IoTDataTrackerSession) GetDeviceSensors(_device common.Address) ([]string, error) {
+	return _IoTDataTracker.Contract.GetDeviceSensors(&_IoTDataTracker.CallOpts, _device)
+}
+
+// GetDeviceSensors is a free data retrieval call binding the contract method 0x2ccea9ef.
+//
+// Solidity: function getDeviceSensors(address _device) view returns(string[] sensors)
+func (_IoTDataTracker *IoTDataTrackerCallerSession) GetDeviceSensors(_device common.Address) ([]string, error) {
+	return _IoTDataTracker.Contract.GetDeviceSensors(&_IoTDataTracker.CallOpts, _device)
+}
+
+// GetRecentChanges is a free data retrieval call binding the contract method 0x13d236b3.
+//
+// Solidity: function getRecentChanges(address _device, string _sensor, uint256 _limit) view returns(string[] values, uint256[] timestamps)
+func (_IoTDataTracker *IoTDataTrackerCaller) GetRecentChanges(opts *bind.CallOpts, _device common.Address, _sensor string, _limit *big.Int) (struct {
+	Values     []string
+	Timestamps []*big.Int
+}, error) {
+	var out []interface{}
+	err := _IoTDataTracker.contract.Call(opts, &out, "getRecentChanges", _device, _sensor, _limit)
+
+	outstruct := new(struct {
+		Values     []string
+		Timestamps []*big.Int
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.Values = *abi.ConvertType(out[0], new([]string)).(*[]string)
+	outstruct.Timestamps = *abi.ConvertType(out[1], new([]*big.Int)).(*[]*big.Int)
+
+	return *outstruct, err
+
+}
+
+// GetRecentChanges is a free data retrieval call binding the contract method 0x13d236b3.
+//
+// Solidity: function getRecentChanges(address _device, string _sensor, uint256 _limit) view returns(string[] values, uint256[] timestamps)
+func (_IoTDataTracker *IoTDataTrackerSession) GetRecentChanges(_device common.Address, _sensor string, _limit *big.Int) (struct {
+	Values     []string
+	Timestamps []*big.Int
+}, error) {
+	return _IoTDataTracker.Contract.GetRecentChanges(&_IoTDataTracker.CallOpts, _device, _sensor, _limit)
+}
+
+// GetRecentChanges is a free data retrieval call binding the contract method 0x13d236b3.
+//
+// Solidity: function getRecentChanges(address _device, string _sensor, uint256 _limit) view returns(string[] values, uint256[] timestamps)
+func (_IoTDataTracker *IoTDataTrackerCallerSession) GetRecentChanges(_device common.Address, _sensor string, _limit *big.Int) (struct {
+	Values     []string
+	Timestamps []*big.Int
+}, error) {
+	return _IoTDataTracker.Contract.GetRecentChanges(&_IoTDataTracker.CallOpts, _device, _sensor, _limit)
+}
+
+// GetSensorData is a free data retrieval call binding the contract method 0x53494f9f.
+//
+// Solidity: function getSensorData(address _device, string _sensor) view returns(string value, uint256 timestamp, uint256 changeCount)
+func (_IoTDataTracker *IoTDataTrackerCaller) GetSensorData(opts *bind.CallOpts, _device common.Address, _sensor string) (struct {
+	Value       string
+	Timestamp   *big.Int
+	ChangeCount *big.Int
+}, error) {
+	var out []interface{}
+	err := _IoTDataTracker.contract.Call(opts, &out, "getSensorData", _device, _sensor)
+
+	outstruct := new(struct {
+		Value       string
+		Timestamp   *big.Int
+		ChangeCount *big.Int
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.Value = *abi.ConvertType(out[0], new(string)).(*string)
+	outstruct.Timestamp = *abi.ConvertType(out[1], new(*big.Int)).(**big.Int)
+	outstruct.ChangeCount = *abi.ConvertType(out[2], new(*big.Int)).(**big.Int)
+
+	return *outstruct, err
+
+}
+
+// GetSensorData is a free data retrieval call binding the contract method 0x53494f9f.
+//
+// Solidity: function getSensorData(address _device, string _sensor) view returns(string value, uint256 timestamp, uint256 changeCount)
+func (_IoTDataTracker *IoTDataTrackerSession) GetSensorData(_device common.Address, _sensor string) (struct {
+	Value       string
+	Timestamp   *big.Int
+	ChangeCount *big.Int
+}, error) {
+	return _IoTDataTracker.Contract.GetSensorData(&_IoTDataTracker.CallOpts, _device, _sensor)
+}
+
+// GetSensorData is a free data retrieval call binding the contract method 0x53494f9f.
+//
+// Solidity: function getSensorData(address _device, string _sensor) view returns(string value, uint256 timestamp, uint256 changeCount)
+func (_IoTDataTracker *IoTDataTrackerCallerSession) GetSensorData(_device common.Address, _sensor string) (struct {
+	Value       string
+	Timestamp   *big.Int
+	ChangeCount *big.Int
+}, error) {
+	return _IoTDataTracker.Contract.GetSensorData(&_IoTDataTracker.CallOpts, _device, _sensor)
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_IoTDataTracker *IoTDataTrackerCaller) Owner(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _IoTDataTracker.contract.Call(opts, &out, "owner")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_IoTDataTracker *IoTDataTrackerSession) Owner() (common.Address, error) {
+	return _IoTDataTracker.Contract.Owner(&_IoTDataTracker.CallOpts)
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_IoTDataTracker *IoTDataTrackerCallerSession) Owner() (common.Address, error) {
+	return _IoTDataTracker.Contract.Owner(&_IoTDataTracker.CallOpts)
+}
+
+// DeactivateDevice is a paid mutator transaction binding the contract method 0xcf65952c.
+//
+// Solidity: function deactivateDevice(address _device) returns()
+func (_IoTDataTracker *IoTDataTrackerTransactor) DeactivateDevice(opts *bind.TransactOpts, _device common.Address) (*types.Transaction, error) {
+	return _IoTDataTracker.contract.Transact(opts, "deactivateDevice", _device)
+}
+
+// DeactivateDevice is a paid mutator transaction binding the contract method 0xcf65952c.
+//
+// Solidity: function deactivateDevice(address _device) returns()
+func (_IoTDataTracker *IoTDataTrackerSession) DeactivateDevice(_device common.Address) (*types.Transaction, error) {
+	return _IoTDataTracker.Contract.DeactivateDevice(&_IoTDataTracker.TransactOpts, _device)
+}
+
+// DeactivateDevice is a paid mutator transaction binding the contract method 0xcf65952c.
+//
+// Solidity: function deactivateDevice(address _device) returns()
+func (_IoTDataTracker *IoTDataTrackerTransactorSession) DeactivateDevice(_device common.Address) (*types.Transaction, error) {
+	return _IoTDataTracker.Contract.DeactivateDevice(&_IoTDataTracker.TransactOpts, _device)
+}
+
+// ReactivateDevice is a paid mutator transaction binding the contract method 0xd425b849.
+//
+// Solidity: function reactivateDevice(address _device) returns()
+func (_IoTDataTracker *IoTDataTrackerTransactor) ReactivateDevice(opts *bind.TransactOpts, _device common.Address) (*types.Transaction, error) {
+	return _IoTDataTracker.contract.Transact(opts, "reactivateDevice", _device)
+}
+
+// ReactivateDevice is a paid mutator transaction binding the contract method 0xd425b849.
+//
+// Solidity: function reactivateDevice(address _device) returns()
+func (_IoTDataTracker *IoTDataTrackerSession) ReactivateDevice(_device common.Address) (*types.Transaction, error) {
+	return _IoTDataTracker.Contract.ReactivateDevice(&_IoTDataTracker.TransactOpts, _device)
+}
+
+// ReactivateDevice is a paid mutator transaction binding the contract method 0xd425b849.
+//
+// Solidity: function reactivateDevice(address _device) returns()
+func (_IoTDataTracker *IoTDataTrackerTransactorSession) ReactivateDevice(_device common.Address) (*types.Transaction, error) {
+	return _IoTDataTracker.Contract.ReactivateDevice(&_IoTDataTracker.TransactOpts, _device)
+}
+
+// RegisterDevice is a paid mutator transaction binding the contract method 0x4c32f347.
+//
+// Solidity: function registerDevice(string _deviceId, string _location) returns()
+func (_IoTDataTracker *IoTDataTrackerTransactor) RegisterDevice(opts *bind.TransactOpts, _deviceId string, _location string) (*types.Transaction, error) {
+	return _IoTDataTracker.contract.Transact(opts, "registerDevice", _deviceId, _location)
+}
+
+// RegisterDevice is a paid mutator transaction binding the contract method 0x4c32f347.
+//
+// Solidity: function registerDevice(string _deviceId, string _location) returns()
+func (_IoTDataTracker *IoTDataTrackerSession) RegisterDevice(_deviceId string, _location string) (*types.Transaction, error) {
+	return _IoTDataTracker.Contract.RegisterDevice(&_IoTDataTracker.TransactOpts, _deviceId, _location)
+}
+
+// RegisterDevice is a paid mutator transaction binding the contract method 0x4c32f347.
+//
+// Solidity: function registerDevice(string _deviceId, string _location) returns()
+func (_IoTDataTracker *IoTDataTrackerTransactorSession) RegisterDevice(_deviceId string, _location string) (*types.Transaction, error) {
+	return _IoTDataTracker.Contract.RegisterDevice(&_IoTDataTracker.TransactOpts, _deviceId, _location)
+}
+
+// UpdateMultipleSensors is a paid mutator transaction binding the contract method 0xa5fcd256.
+//
+// Solidity: function updateMultipleSensors(string[] _sensors, string[] _values) returns()
+func (_IoTDataTracker *IoTDataTrackerTransactor) UpdateMultipleSensors(opts *bind.TransactOpts, _sensors []string, _values []string) (*types.Transaction, error) {
+	return _IoTDataTracker.contract.Transact(opts, "updateMultipleSensors", _sensors, _values)
+}
+
+// UpdateMultipleSensors is a paid mutator transaction binding the contract method 0xa5fcd256.
+//
+// Solidity: function updateMultipleSensors(string[] _sensors, string[] _values) returns()
+func (_IoTDataTracker *IoTDataTrackerSession) UpdateMultipleSensors(_sensors []string, _values []string) (*types.Transaction, error) {
+	return _IoTDataTracker.Contract.UpdateMultipleSensors(&_IoTDataTracker.TransactOpts, _sensors, _values)
+}
+
+// UpdateMultipleSensors is a paid mutator transaction binding the contract method 0xa5fcd256.
+//
+// Solidity: function updateMultipleSensors(string[] _sensors, string[] _values) returns()
+func (_IoTDataTracker *IoTDataTrackerTransactorSession) UpdateMultipleSensors(_sensors []string, _values []string) (*types.Transaction, error) {
+	return _IoTDataTracker.Contract.UpdateMultipleSensors(&_IoTDataTracker.TransactOpts, _sensors, _values)
+}
+
+// UpdateSensorData is a paid mutator transaction binding the contract method 0x8d8449f1.
+//
+// Solidity: function updateSensorData(string _sensor, string _value) returns()
+func (_IoTDataTracker *IoTDataTrackerTransactor) UpdateSensorData(opts *bind.TransactOpts, _sensor string, _value string) (*types.Transaction, error) {
+	return _IoTDataTracker.contract.Transact(opts, "updateSensorData", _sensor, _value)
+}
+
+// UpdateSensorData is a paid mutator transaction binding the contract method 0x8d8449f1.
+//
+// Solidity: function updateSensorData(string _sensor, string _value) returns()
+func (_IoTDataTracker *IoTDataTrackerSession) UpdateSensorData(_sensor string, _value string) (*types.Transaction, error) {
+	return _IoTDataTracker.Contract.UpdateSensorData(&_IoTDataTracker.TransactOpts, _sensor, _value)
+}
+
+// UpdateSensorData is a paid mutator transaction binding the contract method 0x8d8449f1.
+//
+// Solidity: function updateSensorData(string _sensor, string _value) returns()
+func (_IoTDataTracker *IoTDataTrackerTransactorSession) UpdateSensorData(_sensor string, _value string) (*types.Transaction, error) {
+	return _IoTDataTracker.Contract.UpdateSensorData(&_IoTDataTracker.TransactOpts, _sensor, _value)
+}
+
+// IoTDataTrackerDataChangedIterator is returned from FilterDataChanged and is used to iterate over the raw logs and unpacked data for DataChanged events raised by the IoTDataTracker contract.
+type IoTDataTrackerDataChangedIterator struct {
+	Event *IoTDataTrackerDataChanged // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *IoTDataTrackerDataChangedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(IoTDataTrackerDataChanged)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(IoTDataTrackerDataChanged)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *IoTDataTrackerDataChangedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *IoTDataTrackerDataChangedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// IoTDataTrackerDataChanged represents a DataChanged event raised by the IoTDataTracker contract.
+type IoTDataTrackerDataChanged struct {
+	Device    common.Address
+	Sensor    common.Hash
+	Timestamp *big.Int
+	ValueHash [32]byte
+	Value     string
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterDataChanged is a free log retrieval operation binding the contract event 0x591a69c1400d4ef641b45b3bd07d167b2bb861f9c7acb0073dab2e81211b4ca2.
+//
+// Solidity: event DataChanged(address indexed device, string indexed sensor, uint256 indexed timestamp, bytes32 valueHash, string value)
+func (_IoTDataTracker *IoTDataTrackerFilterer) FilterDataChanged(opts *bind.FilterOpts, device []common.Address, sensor []string, timestamp []*big.Int) (*IoTDataTrackerDataChangedIterator, error) {
+
+	var deviceRule []interface{}
+	for _, deviceItem := range device {
+		deviceRule = append(deviceRule, deviceItem)
+	}
+	var sensorRule []interface{}
+	for _, sensorItem := range sensor {
+		sensorRule = append(sensorRule, sensorItem)
+	}
+	var timestampRule []interface{}
+	for _, timestampItem := range timestamp {
+		timestampRule = append(timestampRule, timestampItem)
+	}
+
+	logs, sub, err := _IoTDataTracker.contract.FilterLogs(opts, "DataChanged", deviceRule, sensorRule, timestampRule)
+	if err != nil {
+		return nil, err
+	}
+	return &IoTDataTrackerDataChangedIterator{contract: _IoTDataTracker.contract, event: "DataChanged", logs: logs, sub: sub}, nil
+}
+
+// WatchDataChanged is a free log subscription operation binding the contract event 0x591a69c1400d4ef641b45b3bd07d167b2bb861f9c7acb0073dab2e81211b4ca2.
+//
+// Solidity: event DataChanged(address indexed device, string indexed sensor, uint256 indexed timestamp, bytes32 valueHash, string value)
+func (_IoTDataTracker *IoTDataTrackerFilterer) WatchDataChanged(opts *bind.WatchOpts, sink chan<- *IoTDataTrackerDataChanged, device []common.Address, sensor []string, timestamp []*big.Int) (event.Subscription, error) {
+
+	var deviceRule []interface{}
+	for _, deviceItem := range device {
+		deviceRule = append(deviceRule, deviceItem)
+	}
+	var sensorRule []interface{}
+	for _, sensorItem := range sensor {
+		sensorRule = append(sensorRule, sensorItem)
+	}
+	var timestampRule []interface{}
+	for _, timestampItem := range timestamp {
+		timestampRule = append(timestampRule, timestampItem)
+	}
+
+	logs, sub, err := _IoTDataTracker.contract.WatchLogs(opts, "DataChanged", deviceRule, sensorRule, timestampRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(IoTDataTrackerDataChanged)
+				if err := _IoTDataTracker.contract.UnpackLog(event, "DataChanged", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseDataChanged is a log parse operation binding the contract event 0x591a69c1400d4ef641b45b3bd07d167b2bb861f9c7acb0073dab2e81211b4ca2.
+//
+// Solidity: event DataChanged(address indexed device, string indexed sensor, uint256 indexed timestamp, bytes32 valueHash, string value)
+func (_IoTDataTracker *IoTDataTrackerFilterer) ParseDataChanged(log types.Log) (*IoTDataTrackerDataChanged, error) {
+	event := new(IoTDataTrackerDataChanged)
+	if err := _IoTDataTracker.contract.UnpackLog(event, "DataChanged", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// IoTDataTrackerDeviceRegisteredIterator is returned from FilterDeviceRegistered and is used to iterate over the raw logs and unpacked data for DeviceRegistered events raised by the IoTDataTracker contract.
+type IoTDataTrackerDeviceRegisteredIterator struct {
+	Event *IoTDataTrackerDeviceRegistered // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *IoTDataTrackerDeviceRegisteredIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(IoTDataTrackerDeviceRegistered)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(IoTDataTrackerDeviceRegistered)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *IoTDataTrackerDeviceRegisteredIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *IoTDataTrackerDeviceRegisteredIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// IoTDataTrackerDeviceRegistered represents a DeviceRegistered event raised by the IoTDataTracker contract.
+type IoTDataTrackerDeviceRegistered struct {
+	Device    common.Address
+	DeviceId  string
+	Location  string
+	Timestamp *big.Int
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterDeviceRegistered is a free log retrieval operation binding the contract event 0xf433a0d4cb94bbe6bce9d2720fd6aa2a8546da64a568af901f70331496e70c6a.
+//
+// Solidity: event DeviceRegistered(address indexed device, string deviceId, string location, uint256 timestamp)
+func (_IoTDataTracker *IoTDataTrackerFilterer) FilterDeviceRegistered(opts *bind.FilterOpts, device []common.Address) (*IoTDataTrackerDeviceRegisteredIterator, error) {
+
+	var deviceRule []interface{}
+	for _, deviceItem := range device {
+		deviceRule = append(deviceRule, deviceItem)
+	}
+
+	logs, sub, err := _IoTDataTracker.contract.FilterLogs(opts, "DeviceRegistered", deviceRule)
+	if err != nil {
+		return nil, err
+	}
+	return &IoTDataTrackerDeviceRegisteredIterator{contract: _IoTDataTracker.contract, event: "DeviceRegistered", logs: logs, sub: sub}, nil
+}
+
+// WatchDeviceRegistered is a free log subscription operation binding the contract event 0xf433a0d4cb94bbe6bce9d2720fd6aa2a8546da64a568af901f70331496e70c6a.
+//
+// Solidity: event DeviceRegistered(address indexed device, string deviceId, string location, uint256 timestamp)
+func (_IoTDataTracker *IoTDataTrackerFilterer) WatchDeviceRegistered(opts *bind.WatchOpts, sink chan<- *IoTDataTrackerDeviceRegistered, device []common.Address) (event.Subscription, error) {
+
+	var deviceRule []interface{}
+	for _, deviceItem := range device {
+		deviceRule = append(deviceRule, deviceItem)
+	}
+
+	logs, sub, err := _IoTDataTracker.contract.WatchLogs(opts, "DeviceRegistered", deviceRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(IoTDataTrackerDeviceRegistered)
+				if err := _IoTDataTracker.contract.UnpackLog(event, "DeviceRegistered", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseDeviceRegistered is a log parse operation binding the contract event 0xf433a0d4cb94bbe6bce9d2720fd6aa2a8546da64a568af901f70331496e70c6a.
+//
+// Solidity: event DeviceRegistered(address indexed device, string deviceId, string location, uint256 timestamp)
+func (_IoTDataTracker *IoTDataTrackerFilterer) ParseDeviceRegistered(log types.Log) (*IoTDataTrackerDeviceRegistered, error) {
+	event := new(IoTDataTrackerDeviceRegistered)
+	if err := _IoTDataTracker.contract.UnpackLog(event, "DeviceRegistered", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
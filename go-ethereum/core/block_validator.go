@@ -109,6 +109,20 @@ func (v *BlockValidator) ValidateBody(block *types.Block) error {
 		}
 	}
 
+	// Transaction senders must be allowed by the consensus engine, if it restricts them.
+	if validator, ok := v.engine.(consensus.TxValidator); ok && validator.RestrictsSenders(header) {
+		signer := types.MakeSigner(v.config, header.Number, header.Time)
+		for i, tx := range block.Transactions() {
+			from, err := types.Sender(signer, tx)
+			if err != nil {
+				return fmt.Errorf("invalid sender of transaction %d: %w", i, err)
+			}
+			if err := validator.ValidateTxSender(v.bc, header, from); err != nil {
+				return fmt.Errorf("transaction %d from %v rejected: %w", i, from, err)
+			}
+		}
+	}
+
 	// Ancestor block must be known.
 	if !v.bc.HasBlockAndState(block.ParentHash(), block.NumberU64()-1) {
 		if !v.bc.HasBlock(block.ParentHash(), block.NumberU64()-1) {
@@ -116,6 +130,12 @@ func (v *BlockValidator) ValidateBody(block *types.Block) error {
 		}
 		return consensus.ErrPrunedAncestor
 	}
+	// Header fields depending on the parent state can be verified now that it's known.
+	if verifier, ok := v.engine.(consensus.StateVerifier); ok {
+		if err := verifier.VerifyHeaderState(v.bc, header); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
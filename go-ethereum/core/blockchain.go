@@ -216,6 +216,7 @@ type BlockChain struct {
 	gcproc        time.Duration                    // Accumulates canonical block processing for trie dumping
 	lastWrite     uint64                           // Last block when the state was flushed
 	flushInterval atomic.Int64                     // Time interval (processing time) after which to flush a state
+	lastProcTime  atomic.Int64                     // Processing time of the last imported canonical block
 	triedb        *triedb.Database                 // The database handler for maintaining trie nodes.
 	stateCache    state.Database                   // State database to reuse between imports (contains state cache)
 	txIndexer     *txIndexer                       // Transaction indexer, might be nil if not enabled
@@ -1451,6 +1452,11 @@ func (bc *BlockChain) writeBlockAndSetHead(block *types.Block, receipts []*types
 	if err != nil {
 		return NonStatTy, err
 	}
+	// Never reorganise the finalized block away, whatever the fork choice says
+	if reorg && block.ParentHash() != currentBlock.Hash() && bc.dropsFinalized(block.Header()) {
+		log.Warn("Refused reorg below finalized block", "number", block.Number(), "hash", block.Hash(), "finalized", bc.CurrentFinalBlock().Number)
+		reorg = false
+	}
 	if reorg {
 		// Reorganise the chain if the parent is not the head block
 		if block.ParentHash() != currentBlock.Hash() {
@@ -1487,6 +1493,29 @@ func (bc *BlockChain) writeBlockAndSetHead(block *types.Block, receipts []*types
 	return status, nil
 }
 
+// dropsFinalized returns whether making the given block the new head would drop
+// the finalized block from the canonical chain.
+func (bc *BlockChain) dropsFinalized(header *types.Header) bool {
+	final := bc.CurrentFinalBlock()
+	if final == nil {
+		return false
+	}
+	number := final.Number.Uint64()
+
+	// Walk back until the new chain joins the canonical one. As the finalized
+	// block is canonical, joining above it means it's kept.
+	for header.Number.Uint64() > number {
+		if bc.GetCanonicalHash(header.Number.Uint64()) == header.Hash() {
+			return false
+		}
+		header = bc.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+		if header == nil {
+			return true
+		}
+	}
+	return header.Hash() != final.Hash()
+}
+
 // addFutureBlock checks if the block is within the max allowed window to get
 // accepted for future processing, and returns an error if the block is too far
 // ahead and was not added.
@@ -1851,6 +1880,7 @@ func (bc *BlockChain) insertChain(chain types.Blocks, setHead bool) (int, error)
 
 			// Only count canonical blocks for GC processing time
 			bc.gcproc += proctime
+			bc.lastProcTime.Store(int64(proctime))
 
 		case SideStatTy:
 			log.Debug("Inserted forked block", "number", block.Number(), "hash", block.Hash(),
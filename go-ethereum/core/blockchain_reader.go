@@ -19,6 +19,7 @@ package core
 import (
 	"errors"
 	"math/big"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus"
@@ -63,6 +64,12 @@ func (bc *BlockChain) CurrentSafeBlock() *types.Header {
 	return bc.currentSafeBlock.Load()
 }
 
+// LastProcessingTime retrieves the time it took to process and validate the last
+// canonical block imported into the chain.
+func (bc *BlockChain) LastProcessingTime() time.Duration {
+	return time.Duration(bc.lastProcTime.Load())
+}
+
 // HasHeader checks if a block header is present in the database or not, caching
 // it if present.
 func (bc *BlockChain) HasHeader(hash common.Hash, number uint64) bool {
@@ -4326,3 +4326,43 @@ func TestEIP3651(t *testing.T) {
 		t.Fatalf("sender balance incorrect: expected %d, got %d", expected, actual)
 	}
 }
+
+// Tests that a heavier side chain doesn't reorganise the finalized block away,
+// while one forking off above the finalized block still does.
+func TestReorgBelowFinalized(t *testing.T) {
+	genDb, _, blockchain, err := newCanonical(ethash.NewFaker(), 0, true, rawdb.HashScheme)
+	if err != nil {
+		t.Fatalf("failed to create pristine chain: %v", err)
+	}
+	defer blockchain.Stop()
+
+	genesis := blockchain.GetBlockByHash(blockchain.CurrentBlock().Hash())
+	blocks, _ := GenerateChain(params.TestChainConfig, genesis, ethash.NewFaker(), genDb, 4, func(i int, b *BlockGen) {
+		b.OffsetTime(60)
+	})
+	if _, err := blockchain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	blockchain.SetFinalized(blocks[1].Header())
+
+	// A heavier chain forking off below the finalized block is kept on the side
+	below, _ := GenerateChain(params.TestChainConfig, blocks[0], ethash.NewFaker(), genDb, 5, func(i int, b *BlockGen) {
+		b.OffsetTime(-9)
+	})
+	if _, err := blockchain.InsertChain(below); err != nil {
+		t.Fatalf("failed to insert side chain: %v", err)
+	}
+	if head := blockchain.CurrentBlock().Hash(); head != blocks[3].Hash() {
+		t.Fatalf("head reorged below finality: have %x, want %x", head, blocks[3].Hash())
+	}
+	// A heavier chain forking off at the finalized block becomes canonical
+	above, _ := GenerateChain(params.TestChainConfig, blocks[1], ethash.NewFaker(), genDb, 4, func(i int, b *BlockGen) {
+		b.OffsetTime(-9)
+	})
+	if _, err := blockchain.InsertChain(above); err != nil {
+		t.Fatalf("failed to insert side chain: %v", err)
+	}
+	if head := blockchain.CurrentBlock().Hash(); head != above[3].Hash() {
+		t.Fatalf("head not reorged above finality: have %x, want %x", head, above[3].Hash())
+	}
+}
@@ -110,4 +110,18 @@ var (
 
 	// ErrBlobTxCreate is returned if a blob transaction has no explicit to field.
 	ErrBlobTxCreate = errors.New("blob transaction of type create")
+
+	// ErrInvalidSensorReadings is returned if the readings of a sensor transaction
+	// are not a valid batch.
+	ErrInvalidSensorReadings = types.ErrInvalidSensorReadings
+
+	// ErrSensorTxCreate is returned if a sensor transaction has no explicit to field.
+	ErrSensorTxCreate = errors.New("sensor transaction of type create")
+
+	// ErrSensorTxValue is returned if a sensor transaction transfers value.
+	ErrSensorTxValue = errors.New("sensor transaction with value")
+
+	// ErrSensorTxTarget is returned if a sensor transaction is not sent to the
+	// sensor data precompile.
+	ErrSensorTxTarget = errors.New("sensor transaction not to the sensor data precompile")
 )
@@ -564,6 +564,23 @@ func DeveloperGenesisBlock(gasLimit uint64, faucet *common.Address) *Genesis {
 	return genesis
 }
 
+// DeveloperPoiGenesisBlock returns the 'geth --dev --dev.poi' genesis block,
+// sealed by the PoI engine with the developer account as the only signer.
+func DeveloperPoiGenesisBlock(period uint64, gasLimit uint64, signer common.Address) *Genesis {
+	// Override the default period to the user requested one
+	config := *params.AllPoiProtocolChanges
+	config.ChainID = big.NewInt(1337)
+	config.Poi = &params.PoiConfig{
+		Period: period,
+		Epoch:  config.Poi.Epoch,
+	}
+	// Assemble and return the genesis with the precompiles and signer pre-funded
+	genesis := DeveloperGenesisBlock(gasLimit, &signer)
+	genesis.Config = &config
+	genesis.ExtraData = append(append(make([]byte, 32), signer[:]...), make([]byte, crypto.SignatureLength)...)
+	return genesis
+}
+
 func decodePrealloc(data string) types.GenesisAlloc {
 	var p []struct {
 		Addr    *big.Int
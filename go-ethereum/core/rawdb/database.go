@@ -479,6 +479,9 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 		bloomBits       stat
 		beaconHeaders   stat
 		cliqueSnaps     stat
+		poiSnaps        stat
+		poiAttested     stat
+		iotIndex        stat
 
 		// Les statistic
 		chtTrieNodes   stat
@@ -541,6 +544,12 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 			beaconHeaders.Add(size)
 		case bytes.HasPrefix(key, CliqueSnapshotPrefix) && len(key) == 7+common.HashLength:
 			cliqueSnaps.Add(size)
+		case bytes.HasPrefix(key, PoiSnapshotPrefix) && len(key) == len(PoiSnapshotPrefix)+common.HashLength:
+			poiSnaps.Add(size)
+		case bytes.HasPrefix(key, PoiAttestedPrefix) && len(key) == len(PoiAttestedPrefix)+common.AddressLength:
+			poiAttested.Add(size)
+		case bytes.HasPrefix(key, IoTIndexTablePrefix):
+			iotIndex.Add(size)
 		case bytes.HasPrefix(key, ChtTablePrefix) ||
 			bytes.HasPrefix(key, ChtIndexTablePrefix) ||
 			bytes.HasPrefix(key, ChtPrefix): // Canonical hash trie
@@ -594,6 +603,9 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 		{"Key-Value store", "Storage snapshot", storageSnaps.Size(), storageSnaps.Count()},
 		{"Key-Value store", "Beacon sync headers", beaconHeaders.Size(), beaconHeaders.Count()},
 		{"Key-Value store", "Clique snapshots", cliqueSnaps.Size(), cliqueSnaps.Count()},
+		{"Key-Value store", "PoI snapshots", poiSnaps.Size(), poiSnaps.Count()},
+		{"Key-Value store", "PoI attested blocks", poiAttested.Size(), poiAttested.Count()},
+		{"Key-Value store", "IoT data index", iotIndex.Size(), iotIndex.Count()},
 		{"Key-Value store", "Singleton metadata", metadata.Size(), metadata.Count()},
 		{"Light client", "CHT trie nodes", chtTrieNodes.Size(), chtTrieNodes.Count()},
 		{"Light client", "Bloom trie nodes", bloomTrieNodes.Size(), bloomTrieNodes.Count()},
@@ -134,6 +134,9 @@ var (
 
 	CliqueSnapshotPrefix = []byte("clique-")
 	PoiSnapshotPrefix    = []byte("poi-")
+	PoiAttestedPrefix    = []byte("poi-attested-") // PoiAttestedPrefix + signer address -> RLP(number, hash) of the last block it attested to
+
+	IoTIndexTablePrefix = []byte("iot-") // Table of the IoT data index, see eth/iotindex
 
 	BestUpdateKey         = []byte("update-")    // bigEndian64(syncPeriod) -> RLP(types.LightClientUpdate)  (nextCommittee only referenced by root hash)
 	FixedCommitteeRootKey = []byte("fixedRoot-") // bigEndian64(syncPeriod) -> committee root hash
@@ -18,6 +18,7 @@ package core
 
 import (
 	"crypto/ecdsa"
+	"errors"
 	"math/big"
 	"testing"
 
@@ -422,3 +423,97 @@ func GenerateBadBlock(parent *types.Block, engine consensus.Engine, txs types.Tr
 	}
 	return types.NewBlock(header, txs, nil, receipts, trie.NewStackTrie(nil))
 }
+
+// Tests that sensor transactions are only accepted after the sensor data fork,
+// that their readings reach the sensor data precompile and that they can't be
+// sent anywhere else or without readings.
+func TestSensorTransactions(t *testing.T) {
+	var (
+		config = *params.TestChainConfig
+		key, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr   = crypto.PubkeyToAddress(key.PublicKey)
+		gspec  = &Genesis{
+			Config: &config,
+			Alloc:  types.GenesisAlloc{addr: {Balance: big.NewInt(1000000000000000000)}},
+		}
+		readings = []types.SensorReading{{Sensor: "temperature", Value: "21.5"}, {Sensor: "humidity", Value: "40"}}
+		changed  = crypto.Keccak256Hash([]byte("DataChanged(address,string,uint256,bytes32,string)"))
+	)
+	config.Poi = &params.PoiConfig{Period: 1, Epoch: 30000}
+	config.SensorDataBlock = big.NewInt(2)
+
+	enc, err := types.EncodeSensorReadings(readings)
+	if err != nil {
+		t.Fatalf("failed to encode readings: %v", err)
+	}
+	signer := types.LatestSigner(&config)
+	mkSensorTx := func(nonce uint64, baseFee *big.Int, to common.Address, readings []byte) *types.Transaction {
+		return types.MustSignNewTx(key, signer, &types.SensorTx{
+			ChainID:   config.ChainID,
+			Nonce:     nonce,
+			GasTipCap: big.NewInt(1),
+			GasFeeCap: baseFee,
+			Gas:       200000,
+			To:        to,
+			Readings:  readings,
+		})
+	}
+	var early *types.Transaction
+	db, blocks, receipts := GenerateChainWithGenesis(gspec, ethash.NewFaker(), 2, func(i int, b *BlockGen) {
+		switch i {
+		case 0:
+			early = mkSensorTx(b.TxNonce(addr), b.BaseFee(), vm.SensorDataAddress, enc)
+		case 1:
+			b.AddTx(mkSensorTx(b.TxNonce(addr), b.BaseFee(), vm.SensorDataAddress, enc))
+		}
+	})
+	// Sensor transactions are not valid before the fork
+	if _, err := TransactionToMessage(early, types.MakeSigner(&config, big.NewInt(1), 0), nil); !errors.Is(err, types.ErrTxTypeNotSupported) {
+		t.Fatalf("pre-fork sender error mismatch: have %v, want %v", err, types.ErrTxTypeNotSupported)
+	}
+	intrinsic, err := SensorIntrinsicGas(enc)
+	if err != nil {
+		t.Fatalf("failed to compute intrinsic gas: %v", err)
+	}
+	if want := params.SensorTxGas + 2*params.SensorTxReadingGas + uint64(len(enc))*params.SensorTxByteGas; intrinsic != want {
+		t.Fatalf("intrinsic gas mismatch: have %d, want %d", intrinsic, want)
+	}
+	receipt := receipts[1][0]
+	if receipt.Type != types.SensorTxType || receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatalf("receipt mismatch: type %d, status %d", receipt.Type, receipt.Status)
+	}
+	if receipt.GasUsed <= intrinsic {
+		t.Errorf("gas used %d not above intrinsic gas %d", receipt.GasUsed, intrinsic)
+	}
+	if len(receipt.Logs) != len(readings) {
+		t.Fatalf("log count mismatch: have %d, want %d", len(receipt.Logs), len(readings))
+	}
+	for i, log := range receipt.Logs {
+		if log.Address != vm.SensorDataAddress || log.Topics[0] != changed {
+			t.Errorf("log %d mismatch: address %v, topic %x", i, log.Address, log.Topics[0])
+		}
+		if log.Topics[2] != crypto.Keccak256Hash([]byte(readings[i].Sensor)) {
+			t.Errorf("log %d sensor mismatch: have %x, want %x", i, log.Topics[2], crypto.Keccak256Hash([]byte(readings[i].Sensor)))
+		}
+	}
+	// Sensor transactions to other targets or without readings are rejected
+	blockchain, _ := NewBlockChain(db, nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	defer blockchain.Stop()
+
+	if _, err := blockchain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	baseFee := eip1559.CalcBaseFee(&config, blocks[1].Header())
+	for i, tt := range []struct {
+		tx   *types.Transaction
+		want error
+	}{
+		{tx: mkSensorTx(1, baseFee, common.HexToAddress("0xdead"), enc), want: ErrSensorTxTarget},
+		{tx: mkSensorTx(1, baseFee, vm.SensorDataAddress, nil), want: ErrInvalidSensorReadings},
+	} {
+		block := GenerateBadBlock(blocks[1], ethash.NewFaker(), types.Transactions{tt.tx}, &config)
+		if _, err := blockchain.InsertChain(types.Blocks{block}); !errors.Is(err, tt.want) {
+			t.Errorf("test %d: import error mismatch: have %v, want %v", i, err, tt.want)
+		}
+	}
+}
@@ -116,6 +116,20 @@ func IntrinsicGas(data []byte, accessList types.AccessList, isContractCreation b
 	return gas, nil
 }
 
+// SensorIntrinsicGas computes the 'intrinsic gas' of a sensor transaction with
+// the given compact readings. It replaces the calldata pricing of IntrinsicGas,
+// as the readings are only expanded into calldata during execution.
+func SensorIntrinsicGas(readings []byte) (uint64, error) {
+	decoded, err := types.DecodeSensorReadings(readings)
+	if err != nil {
+		return 0, err
+	}
+	gas := params.SensorTxGas
+	gas += uint64(len(decoded)) * params.SensorTxReadingGas
+	gas += uint64(len(readings)) * params.SensorTxByteGas
+	return gas, nil
+}
+
 // toWordSize returns the ceiled word size required for init code payment calculation.
 func toWordSize(size uint64) uint64 {
 	if size > math.MaxUint64-31 {
@@ -141,6 +155,10 @@ type Message struct {
 	BlobGasFeeCap *big.Int
 	BlobHashes    []common.Hash
 
+	// SensorTx is set if the message is a sensor transaction. Its Data holds the
+	// compact readings, which are sent to the sensor data precompile.
+	SensorTx bool
+
 	// When SkipAccountChecks is true, the message nonce is not checked against the
 	// account nonce in state. It also disables checking that the sender is an EOA.
 	// This field will be set to true for operations like RPC eth_call.
@@ -163,6 +181,9 @@ func TransactionToMessage(tx *types.Transaction, s types.Signer, baseFee *big.In
 		BlobHashes:        tx.BlobHashes(),
 		BlobGasFeeCap:     tx.BlobGasFeeCap(),
 	}
+	if tx.Type() == types.SensorTxType {
+		msg.SensorTx = true
+	}
 	// If baseFee provided, set gasPrice to effectiveGasPrice.
 	if baseFee != nil {
 		msg.GasPrice = cmath.BigMin(msg.GasPrice.Add(msg.GasTipCap, baseFee), msg.GasFeeCap)
@@ -336,6 +357,26 @@ func (st *StateTransition) preCheck() error {
 			}
 		}
 	}
+	// Check the sensor transaction validity
+	if msg.SensorTx {
+		if !st.evm.ChainConfig().IsSensorData(st.evm.Context.BlockNumber) {
+			return ErrTxTypeNotSupported
+		}
+		// As for blob transactions, the to field is only missing for messages
+		// created through RPC (eth_call).
+		if msg.To == nil {
+			return ErrSensorTxCreate
+		}
+		if *msg.To != vm.SensorDataAddress {
+			return fmt.Errorf("%w: address %v, to: %v", ErrSensorTxTarget, msg.From.Hex(), msg.To.Hex())
+		}
+		if msg.Value != nil && msg.Value.Sign() != 0 {
+			return fmt.Errorf("%w: address %v, value: %v", ErrSensorTxValue, msg.From.Hex(), msg.Value)
+		}
+		if _, err := types.DecodeSensorReadings(msg.Data); err != nil {
+			return fmt.Errorf("%w: address %v", err, msg.From.Hex())
+		}
+	}
 	// Check that the user is paying at least the current blob fee
 	if st.evm.ChainConfig().IsCancun(st.evm.Context.BlockNumber, st.evm.Context.Time) {
 		if st.blobGasUsed() > 0 {
@@ -395,7 +436,15 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 	)
 
 	// Check clauses 4-5, subtract intrinsic gas if everything is correct
-	gas, err := IntrinsicGas(msg.Data, msg.AccessList, contractCreation, rules.IsHomestead, rules.IsIstanbul, rules.IsShanghai)
+	var (
+		gas uint64
+		err error
+	)
+	if msg.SensorTx {
+		gas, err = SensorIntrinsicGas(msg.Data)
+	} else {
+		gas, err = IntrinsicGas(msg.Data, msg.AccessList, contractCreation, rules.IsHomestead, rules.IsIstanbul, rules.IsShanghai)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -423,6 +472,11 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 	// - reset transient storage(eip 1153)
 	st.state.Prepare(rules, msg.From, st.evm.Context.Coinbase, msg.To, vm.ActivePrecompiles(rules), msg.AccessList)
 
+	// Sensor transactions update the readings in the sensor data precompile
+	input := msg.Data
+	if msg.SensorTx {
+		input = append([]byte{vm.SensorDataUpdate}, msg.Data...)
+	}
 	var (
 		ret   []byte
 		vmerr error // vm errors do not effect consensus and are therefore not assigned to err
@@ -432,7 +486,7 @@ func (st *StateTransition) TransitionDb() (*ExecutionResult, error) {
 	} else {
 		// Increment the nonce for the next transaction
 		st.state.SetNonce(msg.From, st.state.GetNonce(sender.Address())+1)
-		ret, st.gasRemaining, vmerr = st.evm.Call(sender, st.to(), msg.Data, st.gasRemaining, value)
+		ret, st.gasRemaining, vmerr = st.evm.Call(sender, st.to(), input, st.gasRemaining, value)
 	}
 
 	var gasRefund uint64
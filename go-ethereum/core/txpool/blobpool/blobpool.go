@@ -1094,6 +1094,8 @@ func (p *BlobPool) validateTx(tx *types.Transaction) error {
 	stateOpts := &txpool.ValidationOptionsWithState{
 		State: p.state,
 
+		ValidateSender: txpool.SenderValidator(p.chain, p.head),
+
 		FirstNonceGap: func(addr common.Address) uint64 {
 			// Nonce gaps are not permitted in the blob pool, the first gap will
 			// be the next nonce shifted by however many transactions we already
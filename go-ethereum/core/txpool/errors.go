@@ -60,4 +60,8 @@ var (
 	// input transaction of non-blob type when a blob transaction from this sender
 	// remains pending (and vice-versa).
 	ErrAlreadyReserved = errors.New("address already reserved")
+
+	// ErrRestrictedSender is returned if the consensus engine doesn't allow the
+	// sender of a transaction to send transactions.
+	ErrRestrictedSender = errors.New("sender not allowed")
 )
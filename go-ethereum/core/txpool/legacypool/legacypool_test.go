@@ -30,6 +30,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
@@ -355,6 +356,73 @@ func TestInvalidTransactions(t *testing.T) {
 	}
 }
 
+// restrictedEngine is a consensus engine only allowing a single account to send
+// transactions.
+type restrictedEngine struct {
+	consensus.Engine
+	allowed common.Address
+}
+
+func (e *restrictedEngine) RestrictsSenders(header *types.Header) bool {
+	return true
+}
+
+func (e *restrictedEngine) ValidateTxSender(chain consensus.ChainHeaderReader, header *types.Header, sender common.Address) error {
+	if sender != e.allowed {
+		return errors.New("unregistered sender")
+	}
+	return nil
+}
+
+// restrictedBlockChain is a test chain sealed by a restrictedEngine.
+type restrictedBlockChain struct {
+	*testBlockChain
+	engine consensus.Engine
+}
+
+func (bc *restrictedBlockChain) Engine() consensus.Engine                    { return bc.engine }
+func (bc *restrictedBlockChain) CurrentHeader() *types.Header                { return bc.CurrentBlock() }
+func (bc *restrictedBlockChain) GetHeader(common.Hash, uint64) *types.Header { return nil }
+func (bc *restrictedBlockChain) GetHeaderByNumber(uint64) *types.Header      { return nil }
+func (bc *restrictedBlockChain) GetHeaderByHash(common.Hash) *types.Header   { return nil }
+func (bc *restrictedBlockChain) GetTd(common.Hash, uint64) *big.Int          { return nil }
+
+// Tests that transactions of senders the consensus engine doesn't allow are
+// neither accepted into the pool nor propagated.
+func TestRestrictedSenders(t *testing.T) {
+	t.Parallel()
+
+	allowed, _ := crypto.GenerateKey()
+	denied, _ := crypto.GenerateKey()
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := &restrictedBlockChain{
+		testBlockChain: newTestBlockChain(params.TestChainConfig, 10000000, statedb, new(event.Feed)),
+		engine:         &restrictedEngine{allowed: crypto.PubkeyToAddress(allowed.PublicKey)},
+	}
+	pool := New(testTxPoolConfig, blockchain)
+	if err := pool.Init(testTxPoolConfig.PriceLimit, blockchain.CurrentBlock(), makeAddressReserver()); err != nil {
+		t.Fatalf("failed to init pool: %v", err)
+	}
+	defer pool.Close()
+
+	testAddBalance(pool, crypto.PubkeyToAddress(allowed.PublicKey), big.NewInt(1000000))
+	testAddBalance(pool, crypto.PubkeyToAddress(denied.PublicKey), big.NewInt(1000000))
+
+	if err := pool.addRemoteSync(transaction(0, 100000, denied)); !errors.Is(err, txpool.ErrRestrictedSender) {
+		t.Errorf("remote transaction error mismatch: have %v, want %v", err, txpool.ErrRestrictedSender)
+	}
+	if err := pool.addLocal(transaction(0, 100000, denied)); !errors.Is(err, txpool.ErrRestrictedSender) {
+		t.Errorf("local transaction error mismatch: have %v, want %v", err, txpool.ErrRestrictedSender)
+	}
+	if err := pool.addRemoteSync(transaction(0, 100000, allowed)); err != nil {
+		t.Errorf("failed to add transaction of allowed sender: %v", err)
+	}
+	if pending, queued := pool.Stats(); pending != 1 || queued != 0 {
+		t.Errorf("pool stats mismatch: have %d pending %d queued, want 1 pending 0 queued", pending, queued)
+	}
+}
+
 func TestQueue(t *testing.T) {
 	t.Parallel()
 
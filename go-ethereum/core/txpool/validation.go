@@ -22,9 +22,11 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto/kzg4844"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
@@ -41,7 +43,7 @@ var (
 type ValidationOptions struct {
 	Config *params.ChainConfig // Chain configuration to selectively validate based on current fork rules
 
-	Accept  uint8    // Bitmap of transaction types that should be accepted for the calling pool
+	Accept  uint32   // Bitmap of transaction types that should be accepted for the calling pool
 	MaxSize uint64   // Maximum size of a transaction that the caller can meaningfully handle
 	MinTip  *big.Int // Minimum gas tip needed to allow a transaction into the caller pool
 }
@@ -72,6 +74,18 @@ func ValidateTransaction(tx *types.Transaction, head *types.Header, signer types
 	if !opts.Config.IsCancun(head.Number, head.Time) && tx.Type() == types.BlobTxType {
 		return fmt.Errorf("%w: type %d rejected, pool not yet in Cancun", core.ErrTxTypeNotSupported, tx.Type())
 	}
+	if !opts.Config.IsSensorData(head.Number) && tx.Type() == types.SensorTxType {
+		return fmt.Errorf("%w: type %d rejected, pool not yet in the sensor data fork", core.ErrTxTypeNotSupported, tx.Type())
+	}
+	// Sensor transactions may only update readings in the sensor data precompile
+	if tx.Type() == types.SensorTxType {
+		if *tx.To() != vm.SensorDataAddress {
+			return fmt.Errorf("%w: to %v", core.ErrSensorTxTarget, tx.To().Hex())
+		}
+		if _, err := types.DecodeSensorReadings(tx.Data()); err != nil {
+			return err
+		}
+	}
 	// Check whether the init code size has been exceeded
 	if opts.Config.IsShanghai(head.Number, head.Time) && tx.To() == nil && len(tx.Data()) > params.MaxInitCodeSize {
 		return fmt.Errorf("%w: code size %v, limit %v", core.ErrMaxInitCodeSizeExceeded, len(tx.Data()), params.MaxInitCodeSize)
@@ -102,7 +116,15 @@ func ValidateTransaction(tx *types.Transaction, head *types.Header, signer types
 	}
 	// Ensure the transaction has more gas than the bare minimum needed to cover
 	// the transaction metadata
-	intrGas, err := core.IntrinsicGas(tx.Data(), tx.AccessList(), tx.To() == nil, true, opts.Config.IsIstanbul(head.Number), opts.Config.IsShanghai(head.Number, head.Time))
+	var (
+		intrGas uint64
+		err     error
+	)
+	if tx.Type() == types.SensorTxType {
+		intrGas, err = core.SensorIntrinsicGas(tx.Data())
+	} else {
+		intrGas, err = core.IntrinsicGas(tx.Data(), tx.AccessList(), tx.To() == nil, true, opts.Config.IsIstanbul(head.Number), opts.Config.IsShanghai(head.Number, head.Time))
+	}
 	if err != nil {
 		return err
 	}
@@ -191,6 +213,49 @@ type ValidationOptionsWithState struct {
 	// ExistingCost is a mandatory callback to retrieve an already pooled
 	// transaction's cost with the given nonce to check for overdrafts.
 	ExistingCost func(addr common.Address, nonce uint64) *big.Int
+
+	// ValidateSender is an optional callback to check whether the consensus
+	// engine allows an account to send transactions. If this method is not set,
+	// all senders are permitted.
+	ValidateSender func(addr common.Address) error
+}
+
+// senderValidatorChain is the chain a pool validates transactions against if
+// its consensus engine may restrict transaction senders, e.g. core.BlockChain.
+type senderValidatorChain interface {
+	consensus.ChainHeaderReader
+
+	// Engine retrieves the chain's consensus engine.
+	Engine() consensus.Engine
+}
+
+// SenderValidator returns a callback checking whether the consensus engine of
+// the chain allows an account to send transactions in the block on top of the
+// given head, or nil if the engine doesn't restrict senders.
+func SenderValidator(chain interface{}, head *types.Header) func(addr common.Address) error {
+	bc, ok := chain.(senderValidatorChain)
+	if !ok {
+		return nil
+	}
+	validator, ok := bc.Engine().(consensus.TxValidator)
+	if !ok {
+		return nil
+	}
+	header := &types.Header{
+		ParentHash: head.Hash(),
+		Number:     new(big.Int).Add(head.Number, common.Big1),
+		Difficulty: head.Difficulty,
+		Time:       head.Time,
+	}
+	if !validator.RestrictsSenders(header) {
+		return nil
+	}
+	return func(addr common.Address) error {
+		if err := validator.ValidateTxSender(bc, header, addr); err != nil {
+			return fmt.Errorf("%w: %v", ErrRestrictedSender, err)
+		}
+		return nil
+	}
 }
 
 // ValidateTransactionWithState is a helper method to check whether a transaction
@@ -205,6 +270,12 @@ func ValidateTransactionWithState(tx *types.Transaction, signer types.Signer, op
 		log.Error("Transaction sender recovery failed", "err", err)
 		return err
 	}
+	// Ensure the consensus engine allows the sender to transact
+	if opts.ValidateSender != nil {
+		if err := opts.ValidateSender(from); err != nil {
+			return err
+		}
+	}
 	next := opts.State.GetNonce(from)
 	if next > tx.Nonce() {
 		return fmt.Errorf("%w: next nonce %v, tx nonce %v", core.ErrNonceTooLow, next, tx.Nonce())
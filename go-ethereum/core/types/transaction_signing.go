@@ -53,6 +53,9 @@ func MakeSigner(config *params.ChainConfig, blockNumber *big.Int, blockTime uint
 	default:
 		signer = FrontierSigner{}
 	}
+	if config.IsSensorData(blockNumber) && config.ChainID != nil {
+		signer = NewSensorSigner(config.ChainID, signer)
+	}
 	return signer
 }
 
@@ -64,6 +67,16 @@ func MakeSigner(config *params.ChainConfig, blockNumber *big.Int, blockTime uint
 // Use this in transaction-handling code where the current block number is unknown. If you
 // have the current block number available, use MakeSigner instead.
 func LatestSigner(config *params.ChainConfig) Signer {
+	signer := latestForkSigner(config)
+	if config.SensorDataBlock != nil && config.ChainID != nil {
+		signer = NewSensorSigner(config.ChainID, signer)
+	}
+	return signer
+}
+
+// latestForkSigner returns the 'most permissive' Signer of the Ethereum forks
+// scheduled in the chain config.
+func latestForkSigner(config *params.ChainConfig) Signer {
 	if config.ChainID != nil {
 		if config.CancunTime != nil {
 			return NewCancunSigner(config.ChainID)
@@ -175,6 +188,74 @@ type Signer interface {
 	Equal(Signer) bool
 }
 
+type sensorSigner struct {
+	Signer
+	chainId *big.Int
+}
+
+// NewSensorSigner returns a signer that accepts sensor transactions of the
+// given chain, along with the transactions accepted by the parent signer.
+func NewSensorSigner(chainId *big.Int, parent Signer) Signer {
+	return sensorSigner{Signer: parent, chainId: chainId}
+}
+
+func (s sensorSigner) Sender(tx *Transaction) (common.Address, error) {
+	if tx.Type() != SensorTxType {
+		return s.Signer.Sender(tx)
+	}
+	V, R, S := tx.RawSignatureValues()
+	// Sensor txs are defined to use 0 and 1 as their recovery
+	// id, add 27 to become equivalent to unprotected Homestead signatures.
+	V = new(big.Int).Add(V, big.NewInt(27))
+	if tx.ChainId().Cmp(s.chainId) != 0 {
+		return common.Address{}, fmt.Errorf("%w: have %d want %d", ErrInvalidChainId, tx.ChainId(), s.chainId)
+	}
+	return recoverPlain(s.Hash(tx), R, S, V, true)
+}
+
+func (s sensorSigner) ChainID() *big.Int {
+	return s.chainId
+}
+
+func (s sensorSigner) Equal(s2 Signer) bool {
+	x, ok := s2.(sensorSigner)
+	return ok && x.chainId.Cmp(s.chainId) == 0 && x.Signer.Equal(s.Signer)
+}
+
+func (s sensorSigner) SignatureValues(tx *Transaction, sig []byte) (R, S, V *big.Int, err error) {
+	txdata, ok := tx.inner.(*SensorTx)
+	if !ok {
+		return s.Signer.SignatureValues(tx, sig)
+	}
+	// Check that chain ID of tx matches the signer. We also accept ID zero here,
+	// because it indicates that the chain ID was not specified in the tx.
+	if txdata.ChainID.Sign() != 0 && txdata.ChainID.Cmp(s.chainId) != 0 {
+		return nil, nil, nil, fmt.Errorf("%w: have %d want %d", ErrInvalidChainId, txdata.ChainID, s.chainId)
+	}
+	R, S, _ = decodeSignature(sig)
+	V = big.NewInt(int64(sig[64]))
+	return R, S, V, nil
+}
+
+// Hash returns the hash to be signed by the sender.
+// It does not uniquely identify the transaction.
+func (s sensorSigner) Hash(tx *Transaction) common.Hash {
+	if tx.Type() != SensorTxType {
+		return s.Signer.Hash(tx)
+	}
+	return prefixedRlpHash(
+		tx.Type(),
+		[]interface{}{
+			s.chainId,
+			tx.Nonce(),
+			tx.GasTipCap(),
+			tx.GasFeeCap(),
+			tx.Gas(),
+			tx.To(),
+			tx.Data(),
+		})
+}
+
 type cancunSigner struct{ londonSigner }
 
 // NewCancunSigner returns a signer that accepts
@@ -0,0 +1,158 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// ErrInvalidSensorReadings is returned if the readings of a sensor transaction
+// are not a valid batch.
+var ErrInvalidSensorReadings = errors.New("invalid sensor readings")
+
+// maxSensorReadingLength is the maximum length of a sensor name or value, as
+// they are prefixed by a single byte length.
+const maxSensorReadingLength = 255
+
+// SensorTx is a transaction of a device publishing a batch of sensor readings
+// to a contract. It carries no value and the readings in a compact encoding
+// instead of calldata: every reading is a length prefixed (uint8) sensor name
+// followed by a length prefixed value, the same encoding as the input of the
+// sensor data precompile.
+type SensorTx struct {
+	ChainID   *big.Int
+	Nonce     uint64
+	GasTipCap *big.Int // a.k.a. maxPriorityFeePerGas
+	GasFeeCap *big.Int // a.k.a. maxFeePerGas
+	Gas       uint64
+	To        common.Address
+	Readings  []byte
+
+	// Signature values
+	V *big.Int `json:"v" gencodec:"required"`
+	R *big.Int `json:"r" gencodec:"required"`
+	S *big.Int `json:"s" gencodec:"required"`
+}
+
+// copy creates a deep copy of the transaction data and initializes all fields.
+func (tx *SensorTx) copy() TxData {
+	cpy := &SensorTx{
+		Nonce:    tx.Nonce,
+		To:       tx.To,
+		Readings: common.CopyBytes(tx.Readings),
+		Gas:      tx.Gas,
+		// These are copied below.
+		ChainID:   new(big.Int),
+		GasTipCap: new(big.Int),
+		GasFeeCap: new(big.Int),
+		V:         new(big.Int),
+		R:         new(big.Int),
+		S:         new(big.Int),
+	}
+	if tx.ChainID != nil {
+		cpy.ChainID.Set(tx.ChainID)
+	}
+	if tx.GasTipCap != nil {
+		cpy.GasTipCap.Set(tx.GasTipCap)
+	}
+	if tx.GasFeeCap != nil {
+		cpy.GasFeeCap.Set(tx.GasFeeCap)
+	}
+	if tx.V != nil {
+		cpy.V.Set(tx.V)
+	}
+	if tx.R != nil {
+		cpy.R.Set(tx.R)
+	}
+	if tx.S != nil {
+		cpy.S.Set(tx.S)
+	}
+	return cpy
+}
+
+// accessors for innerTx.
+func (tx *SensorTx) txType() byte           { return SensorTxType }
+func (tx *SensorTx) chainID() *big.Int      { return tx.ChainID }
+func (tx *SensorTx) accessList() AccessList { return nil }
+func (tx *SensorTx) data() []byte           { return tx.Readings }
+func (tx *SensorTx) gas() uint64            { return tx.Gas }
+func (tx *SensorTx) gasFeeCap() *big.Int    { return tx.GasFeeCap }
+func (tx *SensorTx) gasTipCap() *big.Int    { return tx.GasTipCap }
+func (tx *SensorTx) gasPrice() *big.Int     { return tx.GasFeeCap }
+func (tx *SensorTx) value() *big.Int        { return common.Big0 }
+func (tx *SensorTx) nonce() uint64          { return tx.Nonce }
+func (tx *SensorTx) to() *common.Address    { tmp := tx.To; return &tmp }
+
+func (tx *SensorTx) effectiveGasPrice(dst *big.Int, baseFee *big.Int) *big.Int {
+	if baseFee == nil {
+		return dst.Set(tx.GasFeeCap)
+	}
+	tip := dst.Sub(tx.GasFeeCap, baseFee)
+	if tip.Cmp(tx.GasTipCap) > 0 {
+		tip.Set(tx.GasTipCap)
+	}
+	return tip.Add(tip, baseFee)
+}
+
+func (tx *SensorTx) rawSignatureValues() (v, r, s *big.Int) {
+	return tx.V, tx.R, tx.S
+}
+
+func (tx *SensorTx) setSignatureValues(chainID, v, r, s *big.Int) {
+	tx.ChainID, tx.V, tx.R, tx.S = chainID, v, r, s
+}
+
+func (tx *SensorTx) encode(b *bytes.Buffer) error {
+	return rlp.Encode(b, tx)
+}
+
+func (tx *SensorTx) decode(input []byte) error {
+	return rlp.DecodeBytes(input, tx)
+}
+
+// SensorReading is a single sensor value of a sensor transaction.
+type SensorReading struct {
+	Sensor string `json:"sensor"`
+	Value  string `json:"value"`
+}
+
+// EncodeSensorReadings encodes a batch of readings into the compact encoding of
+// sensor transactions.
+func EncodeSensorReadings(readings []SensorReading) ([]byte, error) {
+	if len(readings) == 0 {
+		return nil, ErrInvalidSensorReadings
+	}
+	var enc []byte
+	for _, reading := range readings {
+		for _, field := range []string{reading.Sensor, reading.Value} {
+			if len(field) == 0 || len(field) > maxSensorReadingLength {
+				return nil, ErrInvalidSensorReadings
+			}
+			enc = append(append(enc, byte(len(field))), field...)
+		}
+	}
+	return enc, nil
+}
+
+// DecodeSensorReadings decodes the compact readings of a sensor transaction,
+// rejecting empty batches and empty sensor names or values.
+func DecodeSensorReadings(enc []byte) ([]SensorReading, error) {
+	var readings []SensorReading
+	for len(enc) > 0 {
+		var fields [2]string
+		for i := range fields {
+			if len(enc) < 1 || len(enc) < 1+int(enc[0]) || enc[0] == 0 {
+				return nil, ErrInvalidSensorReadings
+			}
+			fields[i], enc = string(enc[1:1+int(enc[0])]), enc[1+int(enc[0]):]
+		}
+		readings = append(readings, SensorReading{Sensor: fields[0], Value: fields[1]})
+	}
+	if len(readings) == 0 {
+		return nil, ErrInvalidSensorReadings
+	}
+	return readings, nil
+}
@@ -0,0 +1,112 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Tests the compact encoding of sensor readings.
+func TestSensorReadingsEncoding(t *testing.T) {
+	readings := []SensorReading{{Sensor: "temperature", Value: "21.5"}, {Sensor: "humidity", Value: "40"}}
+
+	enc, err := EncodeSensorReadings(readings)
+	if err != nil {
+		t.Fatalf("failed to encode readings: %v", err)
+	}
+	want := append(append([]byte{11}, "temperature"...), 4)
+	want = append(append(want, "21.5"...), 8)
+	want = append(append(want, "humidity"...), 2)
+	want = append(want, "40"...)
+	if !bytes.Equal(enc, want) {
+		t.Fatalf("encoding mismatch: have %x, want %x", enc, want)
+	}
+	dec, err := DecodeSensorReadings(enc)
+	if err != nil {
+		t.Fatalf("failed to decode readings: %v", err)
+	}
+	if len(dec) != len(readings) || dec[0] != readings[0] || dec[1] != readings[1] {
+		t.Fatalf("decoded readings mismatch: have %v, want %v", dec, readings)
+	}
+	// Invalid batches must be rejected both ways
+	for i, invalid := range [][]SensorReading{
+		nil,
+		{{Sensor: "", Value: "1"}},
+		{{Sensor: "temperature", Value: ""}},
+		{{Sensor: strings.Repeat("a", 256), Value: "1"}},
+	} {
+		if _, err := EncodeSensorReadings(invalid); !errors.Is(err, ErrInvalidSensorReadings) {
+			t.Errorf("test %d: encoding error mismatch: have %v, want %v", i, err, ErrInvalidSensorReadings)
+		}
+	}
+	for i, invalid := range [][]byte{
+		nil,
+		{0x00, 0x01, 'a'},
+		{0x01, 'a'},
+		{0x02, 'a', 0x01, 'b'},
+		enc[:len(enc)-1],
+	} {
+		if _, err := DecodeSensorReadings(invalid); !errors.Is(err, ErrInvalidSensorReadings) {
+			t.Errorf("test %d: decoding error mismatch: have %v, want %v", i, err, ErrInvalidSensorReadings)
+		}
+	}
+}
+
+// Tests that sensor transactions can be signed, encoded and decoded, and that
+// signers not aware of them reject them.
+func TestSensorTxSigning(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	enc, _ := EncodeSensorReadings([]SensorReading{{Sensor: "temperature", Value: "21.5"}})
+
+	signer := NewSensorSigner(big.NewInt(1), NewCancunSigner(big.NewInt(1)))
+	tx, err := SignNewTx(key, signer, &SensorTx{
+		ChainID:   big.NewInt(1),
+		Nonce:     3,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(10),
+		Gas:       50000,
+		To:        common.HexToAddress("0x1001"),
+		Readings:  enc,
+	})
+	if err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+	if from, err := Sender(signer, tx); err != nil || from != crypto.PubkeyToAddress(key.PublicKey) {
+		t.Fatalf("sender mismatch: have %v (%v), want %v", from, err, crypto.PubkeyToAddress(key.PublicKey))
+	}
+	if _, err := Sender(LatestSignerForChainID(big.NewInt(1)), tx); !errors.Is(err, ErrTxTypeNotSupported) {
+		t.Fatalf("latest signer error mismatch: have %v, want %v", err, ErrTxTypeNotSupported)
+	}
+	// Check the binary and JSON round trips
+	blob, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to encode transaction: %v", err)
+	}
+	if blob[0] != SensorTxType {
+		t.Fatalf("type byte mismatch: have %d, want %d", blob[0], SensorTxType)
+	}
+	var decoded Transaction
+	if err := decoded.UnmarshalBinary(blob); err != nil {
+		t.Fatalf("failed to decode transaction: %v", err)
+	}
+	if decoded.Hash() != tx.Hash() || !bytes.Equal(decoded.Data(), enc) {
+		t.Fatalf("binary round trip mismatch: have %x, want %x", decoded.Hash(), tx.Hash())
+	}
+	js, err := json.Marshal(tx)
+	if err != nil {
+		t.Fatalf("failed to marshal transaction: %v", err)
+	}
+	var parsed Transaction
+	if err := json.Unmarshal(js, &parsed); err != nil {
+		t.Fatalf("failed to unmarshal transaction: %v", err)
+	}
+	if parsed.Hash() != tx.Hash() {
+		t.Fatalf("JSON round trip mismatch: have %x, want %x", parsed.Hash(), tx.Hash())
+	}
+}
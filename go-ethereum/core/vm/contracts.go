@@ -25,6 +25,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/crypto/blake2b"
 	"github.com/ethereum/go-ethereum/crypto/bls12381"
@@ -42,6 +43,19 @@ type PrecompiledContract interface {
 	Run(input []byte) ([]byte, error) // Run runs the precompiled contract
 }
 
+// StatefulPrecompiledContract is a native Go contract that accesses the state,
+// e.g. to keep data of its callers under its own address. The gas depending on
+// the input is still charged via RequiredGas, but it is run via RunStateful
+// instead of Run, charging the gas depending on the state from what's left.
+type StatefulPrecompiledContract interface {
+	PrecompiledContract
+
+	// RunStateful runs the contract on behalf of the caller with the gas left
+	// after RequiredGas and returns the gas left after the run. If readOnly is
+	// set, the contract must not modify the state.
+	RunStateful(evm *EVM, caller common.Address, input []byte, gas uint64, readOnly bool) ([]byte, uint64, error)
+}
+
 // PrecompiledContractsHomestead contains the default set of pre-compiled Ethereum
 // contracts used in the Frontier and Homestead releases.
 var PrecompiledContractsHomestead = map[common.Address]PrecompiledContract{
@@ -121,6 +135,15 @@ var PrecompiledContractsBLS = map[common.Address]PrecompiledContract{
 	common.BytesToAddress([]byte{18}): &bls12381MapG2{},
 }
 
+// SensorDataAddress is the address of the sensor data precompile.
+var SensorDataAddress = common.BytesToAddress([]byte{0x10, 0x01})
+
+// PrecompiledContractsSensorData contains the pre-compiled contracts enabled on
+// PoI chains by the sensor data fork, on top of the ones of the release.
+var PrecompiledContractsSensorData = map[common.Address]PrecompiledContract{
+	SensorDataAddress: &sensorData{},
+}
+
 var (
 	PrecompiledAddressesCancun    []common.Address
 	PrecompiledAddressesBerlin    []common.Address
@@ -149,6 +172,16 @@ func init() {
 
 // ActivePrecompiles returns the precompiles enabled with the current configuration.
 func ActivePrecompiles(rules params.Rules) []common.Address {
+	addresses := activeReleasePrecompiles(rules)
+	if rules.IsSensorData {
+		addresses = append(addresses[:len(addresses):len(addresses)], SensorDataAddress)
+	}
+	return addresses
+}
+
+// activeReleasePrecompiles returns the precompiles of the release enabled with
+// the current configuration.
+func activeReleasePrecompiles(rules params.Rules) []common.Address {
 	switch {
 	case rules.IsCancun:
 		return PrecompiledAddressesCancun
@@ -1134,3 +1167,210 @@ func kZGToVersionedHash(kzg kzg4844.Commitment) common.Hash {
 
 	return h
 }
+
+var (
+	// sensorDataChangedTopic is the topic of the DataChanged event, which the
+	// sensor data precompile emits like the IoTDataTracker contract does.
+	sensorDataChangedTopic = crypto.Keccak256Hash([]byte("DataChanged(address,string,uint256,bytes32,string)"))
+
+	errSensorDataStateless    = errors.New("sensor data precompile requires state access")
+	errSensorDataInvalidInput = errors.New("invalid sensor data input")
+	errSensorDataEmpty        = errors.New("empty sensor name or value")
+)
+
+const (
+	SensorDataUpdate = 0x00 // Op of storing sensor readings of the caller
+	SensorDataQuery  = 0x01 // Op of retrieving the latest sensor value of a device
+)
+
+// sensorData implements the change-only storage of IoTDataTracker natively. Its
+// callers are the devices, storing sensor readings under their own address.
+//
+// An update is the byte 0x00 followed by one or more readings, each encoded as
+// a length prefixed (uint8) sensor name and a length prefixed value. A reading
+// is only stored and logged if the value differs from the latest one of the
+// sensor. It returns the number of changed readings as a 32 byte word.
+//
+// A query is the byte 0x01 followed by the 20 byte device address and the
+// sensor name. It returns the ABI encoding of (bytes32 valueHash, uint256
+// timestamp, uint256 changeCount) of the latest value.
+//
+// Every sensor takes two storage slots at keccak256(device ++ keccak256(sensor))
+// and the slot after it: the hash of the latest value, and its timestamp and
+// change count packed into the lower 16 bytes.
+//
+// The precompile has no code and no balance. EIP-161 deletes such accounts along
+// with their storage once a transaction touched them, unless their nonce is set.
+// The first update therefore sets the nonce of the precompile to 1, like contract
+// creation does for new contracts since EIP-161, which keeps the account and its
+// storage alive.
+type sensorData struct{}
+
+// RequiredGas returns the gas required to execute the pre-compiled contract.
+// It only depends on the input, charging every reading as if it changed. Slot
+// accesses, the storage of sensors written for the first time and the logged
+// data are charged on top while running.
+func (c *sensorData) RequiredGas(input []byte) uint64 {
+	if len(input) > 0 && input[0] == SensorDataQuery {
+		return params.SensorDataQueryGas
+	}
+	readings, _ := parseSensorReadings(input)
+	return params.SensorDataBaseGas + uint64(len(readings))*params.SensorDataReadingGas + uint64(len(input))*params.SensorDataByteGas
+}
+
+func (c *sensorData) Run(input []byte) ([]byte, error) {
+	return nil, errSensorDataStateless
+}
+
+func (c *sensorData) RunStateful(evm *EVM, caller common.Address, input []byte, gas uint64, readOnly bool) ([]byte, uint64, error) {
+	if len(input) == 0 {
+		return nil, gas, errSensorDataInvalidInput
+	}
+	switch input[0] {
+	case SensorDataQuery:
+		if len(input) < 1+common.AddressLength {
+			return nil, gas, errSensorDataInvalidInput
+		}
+		device := common.BytesToAddress(input[1 : 1+common.AddressLength])
+		slot := sensorDataSlot(device, input[1+common.AddressLength:])
+
+		cost := sensorDataAccessGas(evm, slot) + sensorDataAccessGas(evm, incSlot(slot))
+		if gas < cost {
+			return nil, 0, ErrOutOfGas
+		}
+		gas -= cost
+
+		hash, meta := evm.StateDB.GetState(SensorDataAddress, slot), evm.StateDB.GetState(SensorDataAddress, incSlot(slot))
+		output := make([]byte, 96)
+		copy(output, hash[:])
+		copy(output[56:64], meta[16:24])
+		copy(output[88:96], meta[24:32])
+		return output, gas, nil
+
+	case SensorDataUpdate:
+		readings, err := parseSensorReadings(input)
+		if err != nil {
+			return nil, gas, err
+		}
+		use := func(cost uint64) bool {
+			if gas < cost {
+				gas = 0
+				return false
+			}
+			gas -= cost
+			return true
+		}
+		var changed uint64
+		for _, reading := range readings {
+			slot := sensorDataSlot(caller, reading[0])
+			hash := crypto.Keccak256Hash(reading[1])
+
+			if !use(sensorDataAccessGas(evm, slot)) {
+				return nil, 0, ErrOutOfGas
+			}
+			current := evm.StateDB.GetState(SensorDataAddress, slot)
+			if current == hash {
+				continue
+			}
+			if readOnly {
+				return nil, gas, ErrWriteProtection
+			}
+			// Both slots of a sensor written for the first time are created,
+			// charge them like SSTOREs from zero
+			if current == (common.Hash{}) && !use(params.SensorDataNewSensorGas) {
+				return nil, 0, ErrOutOfGas
+			}
+			if !use(sensorDataAccessGas(evm, incSlot(slot))) {
+				return nil, 0, ErrOutOfGas
+			}
+			// The logged data is the ABI encoding of the value hash and value
+			if !use(uint64(96+(len(reading[1])+31)/32*32) * params.LogDataGas) {
+				return nil, 0, ErrOutOfGas
+			}
+			// Keep the storage of the precompile from being cleared (EIP-161)
+			if evm.StateDB.GetNonce(SensorDataAddress) == 0 {
+				evm.StateDB.SetNonce(SensorDataAddress, 1)
+			}
+			meta := evm.StateDB.GetState(SensorDataAddress, incSlot(slot))
+			count := binary.BigEndian.Uint64(meta[24:32]) + 1
+			binary.BigEndian.PutUint64(meta[16:24], evm.Context.Time)
+			binary.BigEndian.PutUint64(meta[24:32], count)
+
+			evm.StateDB.SetState(SensorDataAddress, slot, hash)
+			evm.StateDB.SetState(SensorDataAddress, incSlot(slot), meta)
+
+			// Emit the DataChanged event of the contract, so existing log
+			// consumers only need to watch an additional address
+			var timestamp common.Hash
+			binary.BigEndian.PutUint64(timestamp[24:], evm.Context.Time)
+
+			data := make([]byte, 96+(len(reading[1])+31)/32*32)
+			copy(data, hash[:])
+			data[63] = 0x40
+			data[95] = byte(len(reading[1]))
+			copy(data[96:], reading[1])
+
+			evm.StateDB.AddLog(&types.Log{
+				Address:     SensorDataAddress,
+				Topics:      []common.Hash{sensorDataChangedTopic, common.BytesToHash(caller.Bytes()), crypto.Keccak256Hash(reading[0]), timestamp},
+				Data:        data,
+				BlockNumber: evm.Context.BlockNumber.Uint64(),
+			})
+			changed++
+		}
+		return common.BigToHash(new(big.Int).SetUint64(changed)).Bytes(), gas, nil
+
+	default:
+		return nil, gas, errSensorDataInvalidInput
+	}
+}
+
+// sensorDataAccessGas returns the gas of accessing a storage slot of the sensor
+// data precompile, charged like SLOAD: cold slots cost more than the ones already
+// accessed in the transaction (EIP-2929). The slot is added to the access list.
+func sensorDataAccessGas(evm *EVM, slot common.Hash) uint64 {
+	if !evm.chainRules.IsBerlin {
+		return params.SloadGasEIP2200
+	}
+	if _, ok := evm.StateDB.SlotInAccessList(SensorDataAddress, slot); ok {
+		return params.WarmStorageReadCostEIP2929
+	}
+	evm.StateDB.AddSlotToAccessList(SensorDataAddress, slot)
+	return params.ColdSloadCostEIP2929
+}
+
+// parseSensorReadings splits a sensor data update into its sensor name and
+// value pairs.
+func parseSensorReadings(input []byte) ([][2][]byte, error) {
+	if len(input) < 1 || input[0] != SensorDataUpdate {
+		return nil, errSensorDataInvalidInput
+	}
+	var readings [][2][]byte
+	for rest := input[1:]; len(rest) > 0; {
+		var reading [2][]byte
+		for i := range reading {
+			if len(rest) < 1 || len(rest) < 1+int(rest[0]) {
+				return nil, errSensorDataInvalidInput
+			}
+			reading[i], rest = rest[1:1+int(rest[0])], rest[1+int(rest[0]):]
+			if len(reading[i]) == 0 {
+				return nil, errSensorDataEmpty
+			}
+		}
+		readings = append(readings, reading)
+	}
+	if len(readings) == 0 {
+		return nil, errSensorDataInvalidInput
+	}
+	return readings, nil
+}
+
+// sensorDataSlot returns the storage slot of the latest value hash of a sensor.
+func sensorDataSlot(device common.Address, sensor []byte) common.Hash {
+	return crypto.Keccak256Hash(device.Bytes(), crypto.Keccak256(sensor))
+}
+
+// incSlot returns the storage slot following the given one.
+func incSlot(slot common.Hash) common.Hash {
+	return common.BigToHash(new(big.Int).Add(slot.Big(), common.Big1))
+}
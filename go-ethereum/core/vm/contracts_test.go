@@ -20,11 +20,18 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"os"
 	"testing"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
 )
 
 // precompiledTest defines the input/output pairs for precompiled contract tests.
@@ -395,3 +402,107 @@ func BenchmarkPrecompiledBLS12381G2MultiExpWorstCase(b *testing.B) {
 	}
 	benchmarkPrecompiled("0f", testcase, b)
 }
+
+// Tests that the sensor data precompile stores and logs changed readings only,
+// serves queries and is only available after its fork.
+func TestPrecompiledSensorData(t *testing.T) {
+	var (
+		device = common.HexToAddress("0xdead")
+		config = *params.AllPoiProtocolChanges
+	)
+	config.SensorDataBlock = big.NewInt(1)
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	vmctx := BlockContext{
+		CanTransfer: func(StateDB, common.Address, *uint256.Int) bool { return true },
+		Transfer:    func(StateDB, common.Address, common.Address, *uint256.Int) {},
+		BlockNumber: big.NewInt(0),
+		Time:        1000,
+	}
+	update := []byte{SensorDataUpdate, 4, 'd', 'o', 'o', 'r', 4, 'o', 'p', 'e', 'n', 5, 'p', 'o', 'w', 'e', 'r', 3, '1', '.', '2'}
+
+	// Before the fork the address is a plain account
+	evm := NewEVM(vmctx, TxContext{}, statedb, &config, Config{})
+	if ret, _, err := evm.Call(AccountRef(device), SensorDataAddress, update, 100000, new(uint256.Int)); err != nil || len(ret) != 0 {
+		t.Fatalf("pre-fork call mismatch: have %x, %v, want empty result", ret, err)
+	}
+	// After the fork new sensors need their storage paid for
+	vmctx.BlockNumber = big.NewInt(1)
+	evm = NewEVM(vmctx, TxContext{}, statedb, &config, Config{})
+
+	rules := config.Rules(vmctx.BlockNumber, false, vmctx.Time)
+	statedb.Prepare(rules, device, common.Address{}, &SensorDataAddress, ActivePrecompiles(rules), nil)
+
+	required := (&sensorData{}).RequiredGas(update)
+	if _, _, err := evm.Call(AccountRef(device), SensorDataAddress, update, required+params.SensorDataNewSensorGas, new(uint256.Int)); err != ErrOutOfGas {
+		t.Fatalf("underpaid update error mismatch: have %v, want %v", err, ErrOutOfGas)
+	}
+	if len(statedb.Logs()) != 0 {
+		t.Fatalf("underpaid update logged %d readings", len(statedb.Logs()))
+	}
+	// With enough gas both readings are stored and logged
+	ret, gas, err := evm.Call(AccountRef(device), SensorDataAddress, update, 200000, new(uint256.Int))
+	if err != nil {
+		t.Fatalf("failed to update sensor data: %v", err)
+	}
+	// Both slots of both sensors are cold, and both logs carry 128 bytes of data
+	if want := required + 2*params.SensorDataNewSensorGas + 4*params.ColdSloadCostEIP2929 + 2*128*params.LogDataGas; 200000-gas != want {
+		t.Errorf("gas mismatch: have %d, want %d", 200000-gas, want)
+	}
+	if changed := new(big.Int).SetBytes(ret); changed.Uint64() != 2 {
+		t.Errorf("changed readings mismatch: have %v, want 2", changed)
+	}
+	logs := statedb.Logs()
+	if len(logs) != 2 {
+		t.Fatalf("log count mismatch: have %d, want 2", len(logs))
+	}
+	if logs[0].Topics[0] != sensorDataChangedTopic || logs[0].Topics[1] != common.BytesToHash(device.Bytes()) || logs[0].Topics[2] != crypto.Keccak256Hash([]byte("door")) {
+		t.Errorf("log topics mismatch: have %v", logs[0].Topics)
+	}
+	if value := logs[0].Data[96:100]; string(value) != "open" || logs[0].Data[95] != 4 {
+		t.Errorf("logged value mismatch: have %x", logs[0].Data)
+	}
+	// The storage must outlive the transaction
+	statedb.Finalise(true)
+
+	// Unchanged readings are neither stored nor logged again, changes of known
+	// sensors only cost the input dependent gas, the slot accesses and the log
+	vmctx.Time = 1010
+	evm = NewEVM(vmctx, TxContext{}, statedb, &config, Config{})
+	statedb.Prepare(rules, device, common.Address{}, &SensorDataAddress, ActivePrecompiles(rules), nil)
+	update = []byte{SensorDataUpdate, 4, 'd', 'o', 'o', 'r', 6, 'c', 'l', 'o', 's', 'e', 'd', 5, 'p', 'o', 'w', 'e', 'r', 3, '1', '.', '2'}
+	ret, gas, err = evm.Call(AccountRef(device), SensorDataAddress, update, 100000, new(uint256.Int))
+	if err != nil || new(big.Int).SetBytes(ret).Uint64() != 1 {
+		t.Fatalf("second update mismatch: have %x, %v, want 1 change", ret, err)
+	}
+	if want := (&sensorData{}).RequiredGas(update) + 3*params.ColdSloadCostEIP2929 + 128*params.LogDataGas; 100000-gas != want {
+		t.Errorf("gas mismatch: have %d, want %d", 100000-gas, want)
+	}
+	if len(statedb.Logs()) != 3 {
+		t.Errorf("log count mismatch: have %d, want 3", len(statedb.Logs()))
+	}
+	// Queries return the latest value hash, timestamp and change count, the
+	// slots being warm after the update
+	query := append(append([]byte{SensorDataQuery}, device.Bytes()...), "door"...)
+	ret, gas, err = evm.StaticCall(AccountRef(common.Address{}), SensorDataAddress, query, 100000)
+	if err != nil {
+		t.Fatalf("failed to query sensor data: %v", err)
+	}
+	if want := params.SensorDataQueryGas + 2*params.WarmStorageReadCostEIP2929; 100000-gas != want {
+		t.Errorf("query gas mismatch: have %d, want %d", 100000-gas, want)
+	}
+	want := append(append(crypto.Keccak256([]byte("closed")), common.BigToHash(big.NewInt(1010)).Bytes()...), common.BigToHash(big.NewInt(2)).Bytes()...)
+	if !bytes.Equal(ret, want) {
+		t.Errorf("query result mismatch: have %x, want %x", ret, want)
+	}
+	// Changes are rejected in static calls, malformed input everywhere
+	update = []byte{SensorDataUpdate, 4, 'd', 'o', 'o', 'r', 4, 'o', 'p', 'e', 'n'}
+	if _, _, err := evm.StaticCall(AccountRef(device), SensorDataAddress, update, 100000); err != ErrWriteProtection {
+		t.Errorf("static update error mismatch: have %v, want %v", err, ErrWriteProtection)
+	}
+	for _, input := range [][]byte{nil, {SensorDataUpdate}, {SensorDataUpdate, 4, 'd', 'o'}, {SensorDataUpdate, 4, 'd', 'o', 'o', 'r', 0}, {0x02}} {
+		if _, _, err := evm.Call(AccountRef(device), SensorDataAddress, input, 100000, new(uint256.Int)); err == nil {
+			t.Errorf("malformed input %x accepted", input)
+		}
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"math/big"
 	"runtime"
 	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
@@ -43,6 +44,7 @@ import (
 	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
 	"github.com/ethereum/go-ethereum/eth/gasprice"
+	"github.com/ethereum/go-ethereum/eth/protocols/attest"
 	"github.com/ethereum/go-ethereum/eth/protocols/eth"
 	"github.com/ethereum/go-ethereum/eth/protocols/snap"
 	"github.com/ethereum/go-ethereum/ethdb"
@@ -76,6 +78,7 @@ type Ethereum struct {
 	ethDialCandidates  enode.Iterator
 	snapDialCandidates enode.Iterator
 	merger             *consensus.Merger
+	finality           *finalityGadget // PoI attestation gossip, nil for other engines
 
 	// DB interfaces
 	chainDb ethdb.Database // Block chain database
@@ -220,6 +223,12 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	}
 	eth.bloomIndexer.Start(eth.blockchain)
 
+	if engine := poiEngine(eth.engine); engine != nil {
+		eth.finality = newFinalityGadget(engine, eth.blockchain)
+		engine.SetTelemetry(func() (time.Duration, int) {
+			return eth.blockchain.LastProcessingTime(), eth.p2pServer.PeerCount()
+		})
+	}
 	if config.BlobPool.Datadir != "" {
 		config.BlobPool.Datadir = stack.ResolvePath(config.BlobPool.Datadir)
 	}
@@ -407,6 +416,9 @@ func (s *Ethereum) shouldPreserve(header *types.Header) bool {
 	if _, ok := s.engine.(*poi.Poi); ok {
 		return false
 	}
+	if _, ok := s.engine.(*poi.Transition); ok {
+		return false
+	}
 	return s.isLocalBlock(header)
 }
 
@@ -443,11 +455,15 @@ func (s *Ethereum) StartMining() error {
 			cli = c
 		} else if p, ok := s.engine.(*poi.Poi); ok {
 			poiEngine = p
+		} else if t, ok := s.engine.(*poi.Transition); ok {
+			cli, poiEngine = t.Clique(), t.Poi()
 		} else if cl, ok := s.engine.(*beacon.Beacon); ok {
 			if c, ok := cl.InnerEngine().(*clique.Clique); ok {
 				cli = c
 			} else if p, ok := cl.InnerEngine().(*poi.Poi); ok {
 				poiEngine = p
+			} else if t, ok := cl.InnerEngine().(*poi.Transition); ok {
+				cli, poiEngine = t.Clique(), t.Poi()
 			}
 		}
 		if cli != nil {
@@ -517,6 +533,9 @@ func (s *Ethereum) Protocols() []p2p.Protocol {
 	if s.config.SnapshotCache > 0 {
 		protos = append(protos, snap.MakeProtocols((*snapHandler)(s.handler), s.snapDialCandidates)...)
 	}
+	if s.finality != nil {
+		protos = append(protos, attest.MakeProtocols(s.finality)...)
+	}
 	return protos
 }
 
@@ -541,6 +560,11 @@ func (s *Ethereum) Start() error {
 	}
 	// Start the networking layer and the light server if requested
 	s.handler.Start(maxPeers)
+
+	// Start attesting to blocks if the chain is sealed by PoI
+	if s.finality != nil {
+		s.finality.start()
+	}
 	return nil
 }
 
@@ -550,6 +574,9 @@ func (s *Ethereum) Stop() error {
 	// Stop all the peer-related stuff first.
 	s.ethDialCandidates.Close()
 	s.snapDialCandidates.Close()
+	if s.finality != nil {
+		s.finality.stop()
+	}
 	s.handler.Stop()
 
 	// Then stop everything else.
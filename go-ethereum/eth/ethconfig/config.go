@@ -167,6 +167,15 @@ type Config struct {
 // only exist on already merged networks.
 func CreateConsensusEngine(config *params.ChainConfig, db ethdb.Database) (consensus.Engine, error) {
 	// If proof-of-authority is requested, set it up
+	if config.PoiBlock != nil {
+		if config.Clique == nil || config.Poi == nil {
+			return nil, errors.New("poi fork block requires both clique and poi configs")
+		}
+		if config.PoiBlock.Sign() <= 0 {
+			return nil, errors.New("poi fork block must be positive")
+		}
+		return beacon.New(poi.NewTransition(config.PoiBlock.Uint64(), clique.New(config.Clique, db), poi.New(config.Poi, db))), nil
+	}
 	if config.Clique != nil {
 		return beacon.New(clique.New(config.Clique, db)), nil
 	}
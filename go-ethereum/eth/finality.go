@@ -0,0 +1,176 @@
+package eth
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/beacon"
+	"github.com/ethereum/go-ethereum/consensus/poi"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/eth/protocols/attest"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+// poiEngine returns the PoI engine sealing the chain, or nil if the chain isn't
+// sealed by PoI.
+func poiEngine(engine consensus.Engine) *poi.Poi {
+	if b, ok := engine.(*beacon.Beacon); ok {
+		engine = b.InnerEngine()
+	}
+	switch engine := engine.(type) {
+	case *poi.Poi:
+		return engine
+	case *poi.Transition:
+		return engine.Poi()
+	}
+	return nil
+}
+
+// attestPeerInfo represents a short summary of the `attest` sub-protocol metadata
+// known about a connected peer.
+type attestPeerInfo struct {
+	Version uint `json:"version"` // Attest protocol version negotiated
+}
+
+// finalityGadget attests to blocks with the local PoI signer and gossips the
+// attestations of all signers over the `attest` protocol, advancing the finalized
+// and safe blocks of the chain. It implements the attest.Backend interface.
+type finalityGadget struct {
+	engine   *poi.Poi
+	chain    *core.BlockChain
+	finality *poi.Finality
+
+	peers map[string]*attest.Peer // Peers speaking the `attest` protocol
+	lock  sync.RWMutex            // Protects the peer set
+
+	headCh  chan core.ChainHeadEvent
+	headSub event.Subscription
+	wg      sync.WaitGroup
+}
+
+// newFinalityGadget creates a finality gadget for a chain sealed by PoI.
+func newFinalityGadget(engine *poi.Poi, chain *core.BlockChain) *finalityGadget {
+	return &finalityGadget{
+		engine:   engine,
+		chain:    chain,
+		finality: poi.NewFinality(engine, chain),
+		peers:    make(map[string]*attest.Peer),
+		headCh:   make(chan core.ChainHeadEvent, 16),
+	}
+}
+
+// start begins attesting to new chain heads.
+func (g *finalityGadget) start() {
+	g.headSub = g.chain.SubscribeChainHeadEvent(g.headCh)
+
+	g.wg.Add(1)
+	go g.loop()
+}
+
+// stop terminates the attestation loop.
+func (g *finalityGadget) stop() {
+	g.headSub.Unsubscribe()
+	g.wg.Wait()
+}
+
+// loop re-tallies the attestations on every new head, and attests to the block
+// AttestationDelay blocks behind it with the local signer, if the node is sealing
+// with an authorized one.
+func (g *finalityGadget) loop() {
+	defer g.wg.Done()
+
+	for {
+		select {
+		case ev := <-g.headCh:
+			// Relay the attestations verified now that their blocks arrived
+			g.relay(g.finality.Update(), nil)
+
+			number := ev.Block.NumberU64()
+			if number < poi.AttestationDelay {
+				continue
+			}
+			header := g.chain.GetHeaderByNumber(number - poi.AttestationDelay)
+			if header == nil {
+				continue
+			}
+			att, err := g.engine.Attest(g.chain, header)
+			if err != nil {
+				log.Debug("Skipped block attestation", "number", header.Number, "hash", header.Hash(), "err", err)
+				continue
+			}
+			g.add([]*poi.Attestation{att}, nil)
+
+		case <-g.headSub.Err():
+			return
+		}
+	}
+}
+
+// add tallies up a batch of attestations and relays the new ones to all peers
+// but the one they came from.
+func (g *finalityGadget) add(atts []*poi.Attestation, origin *attest.Peer) {
+	var fresh []*poi.Attestation
+	for _, att := range atts {
+		added, err := g.finality.Add(att)
+		if err != nil {
+			log.Trace("Dropped block attestation", "number", att.Number, "hash", att.Hash, "err", err)
+			continue
+		}
+		if added {
+			fresh = append(fresh, att)
+		}
+	}
+	g.relay(fresh, origin)
+}
+
+// relay sends a batch of attestations to all peers but the one they came from.
+func (g *finalityGadget) relay(atts []*poi.Attestation, origin *attest.Peer) {
+	if len(atts) == 0 {
+		return
+	}
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+
+	for _, peer := range g.peers {
+		if peer == origin {
+			continue
+		}
+		if err := peer.SendAttestations(atts); err != nil {
+			peer.Log().Debug("Failed to relay attestations", "err", err)
+		}
+	}
+}
+
+// RunPeer is invoked when a peer joins on the `attest` protocol.
+func (g *finalityGadget) RunPeer(peer *attest.Peer, handler attest.Handler) error {
+	g.lock.Lock()
+	g.peers[peer.ID()] = peer
+	g.lock.Unlock()
+
+	defer func() {
+		g.lock.Lock()
+		delete(g.peers, peer.ID())
+		g.lock.Unlock()
+	}()
+	return handler(peer)
+}
+
+// PeerInfo retrieves all known `attest` information about a peer.
+func (g *finalityGadget) PeerInfo(id enode.ID) interface{} {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+
+	if peer := g.peers[id.String()]; peer != nil {
+		return &attestPeerInfo{Version: peer.Version()}
+	}
+	return nil
+}
+
+// Handle is invoked from a peer's message handler when it receives a batch of
+// attestations.
+func (g *finalityGadget) Handle(peer *attest.Peer, packet *attest.AttestationsPacket) error {
+	g.add(*packet, peer)
+	return nil
+}
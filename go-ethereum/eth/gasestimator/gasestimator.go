@@ -102,7 +102,7 @@ func Estimate(ctx context.Context, call *core.Message, opts *Options, gasCap uin
 	// directly try 21000. Returning 21000 without any execution is dangerous as
 	// some tx field combos might bump the price up even for plain transfers (e.g.
 	// unused access list items). Ever so slightly wasteful, but safer overall.
-	if len(call.Data) == 0 {
+	if len(call.Data) == 0 && !call.SensorTx {
 		if call.To != nil && opts.State.GetCodeSize(*call.To) == 0 {
 			failed, _, err := execute(ctx, call, opts, params.TxGas)
 			if !failed && err == nil {
@@ -0,0 +1,155 @@
+package iotindex
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// maxSeriesPoints is the maximum number of points returned by a single series
+// query, a narrower time range has to be requested above it.
+const maxSeriesPoints = 10000
+
+var errInvalidRange = errors.New("invalid time range")
+
+// API exposes the IoT data index over the iot RPC namespace.
+type API struct {
+	indexer *Indexer
+}
+
+// NewAPI creates the RPC API of the indexer.
+func NewAPI(indexer *Indexer) *API {
+	return &API{indexer: indexer}
+}
+
+// Point is a sensor value change of a time series.
+type Point struct {
+	Timestamp   hexutil.Uint64 `json:"timestamp"`
+	Value       string         `json:"value"`
+	ValueHash   common.Hash    `json:"valueHash"`
+	Contract    common.Address `json:"contract"`
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	BlockHash   common.Hash    `json:"blockHash"`
+	TxIndex     hexutil.Uint64 `json:"transactionIndex"`
+	LogIndex    hexutil.Uint   `json:"logIndex"`
+}
+
+// Device is a device registered with a watched contract.
+type Device struct {
+	Address     common.Address `json:"address"`
+	Contract    common.Address `json:"contract"`
+	DeviceID    string         `json:"deviceId"`
+	Location    string         `json:"location"`
+	Registered  hexutil.Uint64 `json:"registrationTime"`
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	BlockHash   common.Hash    `json:"blockHash"`
+}
+
+// GetSeries returns the value changes of a sensor of the device with
+// timestamps within [from, to], ordered by time.
+func (api *API) GetSeries(ctx context.Context, device common.Address, sensor string, from, to hexutil.Uint64) ([]*Point, error) {
+	if from > to {
+		return nil, errInvalidRange
+	}
+	var (
+		prefix    = seriesPrefixKey(device, crypto.Keccak256Hash([]byte(sensor)))
+		start     = binary.BigEndian.AppendUint64(nil, uint64(from))
+		canonical = api.canonical(ctx)
+		points    = []*Point{}
+	)
+	it := api.indexer.db.NewIterator(prefix, start)
+	defer it.Release()
+
+	for it.Next() {
+		key := it.Key()[len(prefix):]
+		if len(key) != 8+8+4 {
+			continue
+		}
+		var (
+			time   = binary.BigEndian.Uint64(key[:8])
+			number = binary.BigEndian.Uint64(key[8:16])
+			index  = binary.BigEndian.Uint32(key[16:])
+		)
+		if time > uint64(to) {
+			break
+		}
+		var stored storedPoint
+		if err := rlp.DecodeBytes(it.Value(), &stored); err != nil {
+			return nil, err
+		}
+		// Skip the points of blocks reorged out but not yet rolled back
+		if !canonical(number, stored.BlockHash) {
+			continue
+		}
+		if len(points) == maxSeriesPoints {
+			return nil, fmt.Errorf("more than %d points in range, narrow the time range", maxSeriesPoints)
+		}
+		points = append(points, &Point{
+			Timestamp:   hexutil.Uint64(time),
+			Value:       stored.Value,
+			ValueHash:   stored.ValueHash,
+			Contract:    stored.Contract,
+			BlockNumber: hexutil.Uint64(number),
+			BlockHash:   stored.BlockHash,
+			TxIndex:     hexutil.Uint64(stored.TxIndex),
+			LogIndex:    hexutil.Uint(index),
+		})
+	}
+	return points, it.Error()
+}
+
+// GetDevices returns the devices registered with the watched contracts.
+func (api *API) GetDevices(ctx context.Context) ([]*Device, error) {
+	canonical := api.canonical(ctx)
+	devices := []*Device{}
+
+	it := api.indexer.db.NewIterator(devicePrefix, nil)
+	defer it.Release()
+
+	for it.Next() {
+		key := it.Key()[len(devicePrefix):]
+		if len(key) != 2*common.AddressLength {
+			continue
+		}
+		var stored storedDevice
+		if err := rlp.DecodeBytes(it.Value(), &stored); err != nil {
+			return nil, err
+		}
+		if !canonical(stored.Number, stored.BlockHash) {
+			continue
+		}
+		devices = append(devices, &Device{
+			Address:     common.BytesToAddress(key[:common.AddressLength]),
+			Contract:    common.BytesToAddress(key[common.AddressLength:]),
+			DeviceID:    stored.DeviceID,
+			Location:    stored.Location,
+			Registered:  hexutil.Uint64(stored.Time),
+			BlockNumber: hexutil.Uint64(stored.Number),
+			BlockHash:   stored.BlockHash,
+		})
+	}
+	return devices, it.Error()
+}
+
+// canonical returns a function reporting whether a block is canonical, caching
+// the canonical hashes looked up for the duration of a request.
+func (api *API) canonical(ctx context.Context) func(number uint64, hash common.Hash) bool {
+	hashes := make(map[uint64]common.Hash)
+	return func(number uint64, hash common.Hash) bool {
+		canon, ok := hashes[number]
+		if !ok {
+			if header, _ := api.indexer.backend.HeaderByNumber(ctx, rpc.BlockNumber(number)); header != nil {
+				canon = header.Hash()
+			}
+			hashes[number] = canon
+		}
+		return canon == hash
+	}
+}
@@ -0,0 +1,439 @@
+// Package iotindex maintains a node side index of the sensor data recorded by
+// IoTDataTracker contracts and the sensor data precompile, serving per device
+// and sensor time series over the iot RPC namespace.
+package iotindex
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/contracts/iot/contract"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// journalLimit is the number of recent blocks whose index changes are kept
+	// to undo reorgs. Deeper reorgs reindex the chain from scratch.
+	journalLimit = 1024
+
+	// syncBatch is the maximum number of blocks indexed before the progress is
+	// flushed to the database.
+	syncBatch = 256
+)
+
+// Backend is the chain access needed by the indexer.
+type Backend interface {
+	ChainDb() ethdb.Database
+	ChainConfig() *params.ChainConfig
+	CurrentHeader() *types.Header
+	HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error)
+	GetLogs(ctx context.Context, blockHash common.Hash, number uint64) ([][]*types.Log, error)
+	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
+}
+
+// Config are the configuration parameters of the indexer.
+type Config struct {
+	Contracts []common.Address // IoTDataTracker contracts to index
+}
+
+// Indexer follows the canonical chain and indexes the DataChanged and
+// DeviceRegistered logs of the configured contracts, as well as the ones of the
+// sensor data precompile once it is enabled. The changes of recent blocks are
+// journaled, so the index is rolled back when they are reorged out.
+type Indexer struct {
+	backend   Backend
+	db        ethdb.Database // Table of the index in the chain database
+	addresses []common.Address
+	watched   map[common.Address]bool
+
+	dataChanged      abi.Event
+	deviceRegistered abi.Event
+
+	head indexHead // Last block indexed, only accessed by the sync loop
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates an indexer and registers it and its API with the node.
+func New(stack *node.Node, backend Backend, config Config) (*Indexer, error) {
+	indexer, err := newIndexer(backend, config)
+	if err != nil {
+		return nil, err
+	}
+	stack.RegisterAPIs([]rpc.API{{
+		Namespace: "iot",
+		Service:   NewAPI(indexer),
+	}})
+	stack.RegisterLifecycle(indexer)
+	return indexer, nil
+}
+
+func newIndexer(backend Backend, config Config) (*Indexer, error) {
+	parsed, err := contract.IoTDataTrackerMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	indexer := &Indexer{
+		backend:          backend,
+		db:               rawdb.NewTable(backend.ChainDb(), string(rawdb.IoTIndexTablePrefix)),
+		watched:          make(map[common.Address]bool),
+		dataChanged:      parsed.Events["DataChanged"],
+		deviceRegistered: parsed.Events["DeviceRegistered"],
+		quit:             make(chan struct{}),
+	}
+	addresses := config.Contracts
+	if backend.ChainConfig().SensorDataBlock != nil {
+		addresses = append(addresses[:len(addresses):len(addresses)], vm.SensorDataAddress)
+	}
+	for _, address := range addresses {
+		if !indexer.watched[address] {
+			indexer.watched[address] = true
+			indexer.addresses = append(indexer.addresses, address)
+		}
+	}
+	return indexer, nil
+}
+
+// Start implements node.Lifecycle, starting to follow the chain.
+func (ix *Indexer) Start() error {
+	heads := make(chan core.ChainHeadEvent, 10)
+	sub := ix.backend.SubscribeChainHeadEvent(heads)
+
+	ix.wg.Add(1)
+	go ix.loop(heads, sub)
+	return nil
+}
+
+// Stop implements node.Lifecycle, terminating the indexing.
+func (ix *Indexer) Stop() error {
+	close(ix.quit)
+	ix.wg.Wait()
+	return nil
+}
+
+// loop indexes the chain up to the current head, and again on every new head.
+func (ix *Indexer) loop(heads chan core.ChainHeadEvent, sub event.Subscription) {
+	defer ix.wg.Done()
+	defer sub.Unsubscribe()
+
+	if err := ix.init(); err != nil {
+		log.Error("Failed to initialize IoT data index", "err", err)
+		return
+	}
+	ix.sync()
+	for {
+		select {
+		case <-heads:
+			ix.sync()
+		case <-sub.Err():
+			return
+		case <-ix.quit:
+			return
+		}
+	}
+}
+
+// init loads the index head, starting over if there is none or the index was
+// created for other contracts.
+func (ix *Indexer) init() error {
+	if blob, _ := ix.db.Get(headKey); len(blob) > 0 {
+		var head indexHead
+		if err := rlp.DecodeBytes(blob, &head); err != nil {
+			log.Warn("Invalid IoT data index head, reindexing", "err", err)
+		} else if !sameAddresses(head.Contracts, ix.addresses) {
+			log.Info("Indexed IoT contracts changed, reindexing")
+		} else {
+			ix.head = head
+			log.Info("Loaded IoT data index", "number", head.Number, "hash", head.Hash)
+			return nil
+		}
+	}
+	return ix.reset()
+}
+
+// reset wipes the index, restarting it from the genesis block.
+func (ix *Indexer) reset() error {
+	genesis, err := ix.backend.HeaderByNumber(context.Background(), 0)
+	if err != nil {
+		return err
+	}
+	batch := ix.db.NewBatch()
+	it := ix.db.NewIterator(nil, nil)
+	for it.Next() {
+		batch.Delete(it.Key())
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				it.Release()
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	it.Release()
+
+	ix.head = indexHead{Number: 0, Hash: genesis.Hash(), Contracts: ix.addresses}
+	if err := ix.writeHead(batch); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// sync rolls back the blocks that are no longer canonical and indexes the ones
+// that became canonical since, until the index reaches the head of the chain.
+func (ix *Indexer) sync() {
+	for {
+		select {
+		case <-ix.quit:
+			return
+		default:
+		}
+		current := ix.backend.CurrentHeader().Number.Uint64()
+		if ix.head.Number > current || !ix.canonical(ix.head.Number, ix.head.Hash) {
+			if err := ix.rollback(); err != nil {
+				log.Error("Failed to roll back IoT data index", "number", ix.head.Number, "err", err)
+				return
+			}
+			continue
+		}
+		if ix.head.Number == current {
+			return
+		}
+		last := current
+		if last > ix.head.Number+syncBatch {
+			last = ix.head.Number + syncBatch
+		}
+		number := ix.head.Number
+		if err := ix.index(last); err != nil {
+			log.Error("Failed to index IoT data", "number", ix.head.Number+1, "err", err)
+			return
+		}
+		// Retry on the next head if the chain is being reorged meanwhile
+		if ix.head.Number == number {
+			return
+		}
+	}
+}
+
+// canonical reports whether the block with the given number and hash is part
+// of the canonical chain.
+func (ix *Indexer) canonical(number uint64, hash common.Hash) bool {
+	header, _ := ix.backend.HeaderByNumber(context.Background(), rpc.BlockNumber(number))
+	return header != nil && header.Hash() == hash
+}
+
+// rollback undoes the index changes of the head block, or reindexes from the
+// genesis if the reorg is deeper than the journal reaches.
+func (ix *Indexer) rollback() error {
+	number := ix.head.Number
+	if number == 0 {
+		return ix.reset()
+	}
+	current, err := ix.readJournal(number)
+	if err != nil {
+		log.Warn("IoT data index reorged beyond its journal, reindexing", "number", number, "err", err)
+		return ix.reset()
+	}
+	parent := indexHead{Number: number - 1, Contracts: ix.addresses}
+	if parent.Number > 0 {
+		prev, err := ix.readJournal(parent.Number)
+		if err != nil {
+			log.Warn("IoT data index reorged beyond its journal, reindexing", "number", parent.Number, "err", err)
+			return ix.reset()
+		}
+		parent.Hash = prev.Hash
+	} else {
+		genesis, err := ix.backend.HeaderByNumber(context.Background(), 0)
+		if err != nil {
+			return err
+		}
+		parent.Hash = genesis.Hash()
+	}
+	batch := ix.db.NewBatch()
+	for i := len(current.Changes) - 1; i >= 0; i-- {
+		change := current.Changes[i]
+		if change.Existed {
+			batch.Put(change.Key, change.Prev)
+		} else {
+			batch.Delete(change.Key)
+		}
+	}
+	batch.Delete(journalKey(number))
+
+	ix.head = parent
+	if err := ix.writeHead(batch); err != nil {
+		return err
+	}
+	log.Debug("Rolled back IoT data index", "number", number, "hash", current.Hash, "changes", len(current.Changes))
+	return batch.Write()
+}
+
+// index indexes the blocks following the head up to the given number. It stops
+// early without error if the chain is reorged meanwhile, leaving it to the next
+// iteration of sync to roll back.
+func (ix *Indexer) index(last uint64) error {
+	var (
+		batch   = ix.db.NewBatch()
+		pending = make(map[string][]byte) // Index entries written by the batch so far
+		head    = ix.head
+	)
+	for head.Number < last {
+		header, err := ix.backend.HeaderByNumber(context.Background(), rpc.BlockNumber(head.Number+1))
+		if err != nil {
+			return err
+		}
+		if header == nil || header.ParentHash != head.Hash {
+			break
+		}
+		logs, err := ix.backend.GetLogs(context.Background(), header.Hash(), header.Number.Uint64())
+		if err != nil {
+			return err
+		}
+		entry := &journal{Hash: header.Hash()}
+		write := func(key []byte, value []byte) {
+			prev, existed := pending[string(key)]
+			if !existed {
+				if has, _ := ix.db.Has(key); has {
+					prev, _ = ix.db.Get(key)
+					existed = true
+				}
+			}
+			entry.Changes = append(entry.Changes, change{Key: key, Prev: prev, Existed: existed})
+			pending[string(key)] = value
+			batch.Put(key, value)
+		}
+		var index uint
+		for txIndex, receipt := range logs {
+			for _, l := range receipt {
+				if err := ix.indexLog(header, uint64(txIndex), index, l, write); err != nil {
+					log.Debug("Skipping undecodable IoT log", "number", header.Number, "index", index, "err", err)
+				}
+				index++
+			}
+		}
+		blob, err := rlp.EncodeToBytes(entry)
+		if err != nil {
+			return err
+		}
+		batch.Put(journalKey(header.Number.Uint64()), blob)
+		if header.Number.Uint64() > journalLimit {
+			batch.Delete(journalKey(header.Number.Uint64() - journalLimit))
+		}
+		head = indexHead{Number: header.Number.Uint64(), Hash: header.Hash(), Contracts: ix.addresses}
+	}
+	if head.Number == ix.head.Number {
+		return nil
+	}
+	ix.head = head
+	if err := ix.writeHead(batch); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// indexLog indexes a single log if it is a DataChanged or DeviceRegistered
+// event of a watched contract.
+func (ix *Indexer) indexLog(header *types.Header, txIndex uint64, index uint, l *types.Log, write func(key, value []byte)) error {
+	if !ix.watched[l.Address] || len(l.Topics) == 0 {
+		return nil
+	}
+	switch l.Topics[0] {
+	case ix.dataChanged.ID:
+		if len(l.Topics) != 4 {
+			return errors.New("invalid DataChanged topics")
+		}
+		values, err := ix.dataChanged.Inputs.NonIndexed().Unpack(l.Data)
+		if err != nil {
+			return err
+		}
+		time := l.Topics[3].Big()
+		if !time.IsUint64() {
+			return errors.New("timestamp out of range")
+		}
+		blob, err := rlp.EncodeToBytes(&storedPoint{
+			Contract:  l.Address,
+			ValueHash: values[0].([32]byte),
+			Value:     values[1].(string),
+			BlockHash: header.Hash(),
+			TxIndex:   txIndex,
+		})
+		if err != nil {
+			return err
+		}
+		device := common.BytesToAddress(l.Topics[1].Bytes())
+		write(seriesKey(device, l.Topics[2], time.Uint64(), header.Number.Uint64(), index), blob)
+
+	case ix.deviceRegistered.ID:
+		if len(l.Topics) != 2 {
+			return errors.New("invalid DeviceRegistered topics")
+		}
+		values, err := ix.deviceRegistered.Inputs.NonIndexed().Unpack(l.Data)
+		if err != nil {
+			return err
+		}
+		time := values[2].(*big.Int)
+		if !time.IsUint64() {
+			return errors.New("timestamp out of range")
+		}
+		blob, err := rlp.EncodeToBytes(&storedDevice{
+			DeviceID:  values[0].(string),
+			Location:  values[1].(string),
+			Time:      time.Uint64(),
+			Number:    header.Number.Uint64(),
+			BlockHash: header.Hash(),
+		})
+		if err != nil {
+			return err
+		}
+		write(deviceKey(common.BytesToAddress(l.Topics[1].Bytes()), l.Address), blob)
+	}
+	return nil
+}
+
+// readJournal retrieves the index changes of the given block.
+func (ix *Indexer) readJournal(number uint64) (*journal, error) {
+	blob, err := ix.db.Get(journalKey(number))
+	if err != nil {
+		return nil, err
+	}
+	entry := new(journal)
+	if err := rlp.DecodeBytes(blob, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// writeHead stores the index head in the batch.
+func (ix *Indexer) writeHead(batch ethdb.KeyValueWriter) error {
+	blob, err := rlp.EncodeToBytes(&ix.head)
+	if err != nil {
+		return err
+	}
+	return batch.Put(headKey, blob)
+}
+
+// sameAddresses reports whether two address lists are equal.
+func sameAddresses(a, b []common.Address) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,254 @@
+package iotindex
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/contracts/iot/contract"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	testContract = common.HexToAddress("0x1000000000000000000000000000000000000001")
+	testOther    = common.HexToAddress("0x2000000000000000000000000000000000000002")
+	testDevice   = common.HexToAddress("0xd000000000000000000000000000000000000001")
+
+	testABI, _ = contract.IoTDataTrackerMetaData.GetAbi()
+)
+
+// testBackend is an in memory chain whose canonical blocks can be replaced to
+// simulate reorgs.
+type testBackend struct {
+	db      ethdb.Database
+	config  *params.ChainConfig
+	headers []*types.Header // Canonical chain, indexed by number
+	logs    map[common.Hash][][]*types.Log
+	feed    event.Feed
+	lock    sync.Mutex
+}
+
+func newTestBackend() *testBackend {
+	b := &testBackend{
+		db:     rawdb.NewMemoryDatabase(),
+		config: params.TestChainConfig,
+		logs:   make(map[common.Hash][][]*types.Log),
+	}
+	b.headers = []*types.Header{{Number: new(big.Int), Extra: []byte("genesis")}}
+	return b
+}
+
+func (b *testBackend) ChainDb() ethdb.Database          { return b.db }
+func (b *testBackend) ChainConfig() *params.ChainConfig { return b.config }
+
+func (b *testBackend) CurrentHeader() *types.Header {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.headers[len(b.headers)-1]
+}
+
+func (b *testBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if number < 0 || int(number) >= len(b.headers) {
+		return nil, nil
+	}
+	return b.headers[number], nil
+}
+
+func (b *testBackend) GetLogs(ctx context.Context, hash common.Hash, number uint64) ([][]*types.Log, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.logs[hash], nil
+}
+
+func (b *testBackend) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription {
+	return b.feed.Subscribe(ch)
+}
+
+// extend rewinds the canonical chain to the given number and appends blocks
+// with the given logs on top, tagged to make them distinct from the ones they
+// replace.
+func (b *testBackend) extend(from uint64, tag string, blocks [][][]*types.Log) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.headers = b.headers[:from+1]
+	for _, logs := range blocks {
+		parent := b.headers[len(b.headers)-1]
+		header := &types.Header{
+			ParentHash: parent.Hash(),
+			Number:     new(big.Int).Add(parent.Number, common.Big1),
+			Extra:      []byte(tag),
+		}
+		b.headers = append(b.headers, header)
+		b.logs[header.Hash()] = logs
+	}
+}
+
+// dataChanged creates a DataChanged log of the contract.
+func dataChanged(t *testing.T, address common.Address, device common.Address, sensor string, time uint64, value string) *types.Log {
+	event := testABI.Events["DataChanged"]
+	data, err := event.Inputs.NonIndexed().Pack(crypto.Keccak256Hash([]byte(value)), value)
+	if err != nil {
+		t.Fatalf("failed to pack DataChanged: %v", err)
+	}
+	return &types.Log{
+		Address: address,
+		Topics: []common.Hash{
+			event.ID,
+			common.BytesToHash(device.Bytes()),
+			crypto.Keccak256Hash([]byte(sensor)),
+			common.BigToHash(new(big.Int).SetUint64(time)),
+		},
+		Data: data,
+	}
+}
+
+// deviceRegistered creates a DeviceRegistered log of the contract.
+func deviceRegistered(t *testing.T, address common.Address, device common.Address, id string, time uint64) *types.Log {
+	event := testABI.Events["DeviceRegistered"]
+	data, err := event.Inputs.NonIndexed().Pack(id, "lab", new(big.Int).SetUint64(time))
+	if err != nil {
+		t.Fatalf("failed to pack DeviceRegistered: %v", err)
+	}
+	return &types.Log{
+		Address: address,
+		Topics:  []common.Hash{event.ID, common.BytesToHash(device.Bytes())},
+		Data:    data,
+	}
+}
+
+// newTestIndexer creates an indexer of the test contract, synced manually
+// instead of following the chain head events.
+func newTestIndexer(t *testing.T, backend *testBackend, contracts ...common.Address) (*Indexer, *API) {
+	indexer, err := newIndexer(backend, Config{Contracts: contracts})
+	if err != nil {
+		t.Fatalf("failed to create indexer: %v", err)
+	}
+	if err := indexer.init(); err != nil {
+		t.Fatalf("failed to initialize indexer: %v", err)
+	}
+	return indexer, NewAPI(indexer)
+}
+
+func checkSeries(t *testing.T, api *API, want ...string) {
+	t.Helper()
+
+	points, err := api.GetSeries(context.Background(), testDevice, "temperature", 0, 1<<40)
+	if err != nil {
+		t.Fatalf("failed to retrieve series: %v", err)
+	}
+	if len(points) != len(want) {
+		t.Fatalf("series length mismatch: have %d, want %d", len(points), len(want))
+	}
+	for i, point := range points {
+		if point.Value != want[i] {
+			t.Errorf("point %d value mismatch: have %s, want %s", i, point.Value, want[i])
+		}
+		if point.ValueHash != crypto.Keccak256Hash([]byte(want[i])) {
+			t.Errorf("point %d value hash mismatch: have %x, want %x", i, point.ValueHash, crypto.Keccak256Hash([]byte(want[i])))
+		}
+	}
+}
+
+// Tests that the index follows the canonical chain across reorgs.
+func TestIndexReorg(t *testing.T) {
+	backend := newTestBackend()
+	backend.extend(0, "a", [][][]*types.Log{
+		{{deviceRegistered(t, testContract, testDevice, "station-1", 100)}},
+		{{dataChanged(t, testContract, testDevice, "temperature", 110, "20.5")}},
+		{},
+		{{dataChanged(t, testContract, testDevice, "temperature", 130, "21.0"), dataChanged(t, testContract, testDevice, "humidity", 130, "40")}},
+		{{dataChanged(t, testOther, testDevice, "temperature", 140, "99")}},
+		{{dataChanged(t, testContract, testDevice, "temperature", 150, "21.5")}},
+	})
+	indexer, api := newTestIndexer(t, backend, testContract)
+	indexer.sync()
+
+	if indexer.head.Number != 6 {
+		t.Fatalf("index head mismatch: have %d, want %d", indexer.head.Number, 6)
+	}
+	checkSeries(t, api, "20.5", "21.0", "21.5")
+
+	points, err := api.GetSeries(context.Background(), testDevice, "temperature", 120, 140)
+	if err != nil {
+		t.Fatalf("failed to retrieve series: %v", err)
+	}
+	if len(points) != 1 || points[0].Timestamp != 130 || points[0].BlockNumber != 4 || points[0].LogIndex != 0 {
+		t.Fatalf("ranged series mismatch: have %+v", points)
+	}
+	devices, err := api.GetDevices(context.Background())
+	if err != nil {
+		t.Fatalf("failed to retrieve devices: %v", err)
+	}
+	if len(devices) != 1 || devices[0].Address != testDevice || devices[0].DeviceID != "station-1" || devices[0].Contract != testContract {
+		t.Fatalf("devices mismatch: have %+v", devices)
+	}
+	// Reorg out the last three blocks, the replaced points must disappear even
+	// before the indexer catches up
+	backend.extend(3, "b", [][][]*types.Log{
+		{},
+		{{dataChanged(t, testContract, testDevice, "temperature", 145, "19.0")}},
+	})
+	checkSeries(t, api, "20.5")
+
+	indexer.sync()
+	if indexer.head.Number != 5 || indexer.head.Hash != backend.CurrentHeader().Hash() {
+		t.Fatalf("index head mismatch: have %d (%x), want %d (%x)", indexer.head.Number, indexer.head.Hash, 5, backend.CurrentHeader().Hash())
+	}
+	checkSeries(t, api, "20.5", "19.0")
+
+	// Reorg out the registration, the device must disappear
+	backend.extend(0, "c", [][][]*types.Log{{}, {}, {}, {}, {}, {}, {}})
+	indexer.sync()
+
+	checkSeries(t, api)
+	if devices, _ := api.GetDevices(context.Background()); len(devices) != 0 {
+		t.Fatalf("devices mismatch: have %+v, want none", devices)
+	}
+	// Every index entry apart from the head and the journals must be gone
+	it := indexer.db.NewIterator(nil, nil)
+	defer it.Release()
+	for it.Next() {
+		if key := it.Key(); key[0] != headKey[0] && key[0] != journalPrefix[0] {
+			t.Errorf("stale index entry %x", key)
+		}
+	}
+}
+
+// Tests that a restarted indexer continues from its stored head, and starts
+// over if the indexed contracts change.
+func TestIndexRestart(t *testing.T) {
+	backend := newTestBackend()
+	backend.extend(0, "a", [][][]*types.Log{
+		{{dataChanged(t, testContract, testDevice, "temperature", 110, "20.5")}},
+		{{dataChanged(t, testOther, testDevice, "temperature", 120, "21.0")}},
+	})
+	indexer, _ := newTestIndexer(t, backend, testContract)
+	indexer.sync()
+
+	indexer, api := newTestIndexer(t, backend, testContract)
+	if indexer.head.Number != 2 {
+		t.Fatalf("restarted index head mismatch: have %d, want %d", indexer.head.Number, 2)
+	}
+	checkSeries(t, api, "20.5")
+
+	indexer, api = newTestIndexer(t, backend, testContract, testOther)
+	if indexer.head.Number != 0 {
+		t.Fatalf("reconfigured index head mismatch: have %d, want %d", indexer.head.Number, 0)
+	}
+	checkSeries(t, api)
+
+	indexer.sync()
+	checkSeries(t, api, "20.5", "21.0")
+}
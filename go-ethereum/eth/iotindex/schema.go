@@ -0,0 +1,85 @@
+package iotindex
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// The database layout of the index, within its own table:
+//
+//	headKey                                   -> rlp(indexHead)
+//	journalPrefix ++ number                   -> rlp(journal)
+//	seriesPrefix ++ device ++ sensor ++ time ++ number ++ log -> rlp(storedPoint)
+//	devicePrefix ++ device ++ contract        -> rlp(storedDevice)
+var (
+	headKey       = []byte("h")
+	journalPrefix = []byte("j")
+	seriesPrefix  = []byte("s")
+	devicePrefix  = []byte("d")
+)
+
+// indexHead is the last block whose logs are indexed, along with the contracts
+// they were indexed for.
+type indexHead struct {
+	Number    uint64
+	Hash      common.Hash
+	Contracts []common.Address
+}
+
+// journal records the index changes of a block, so they can be undone if the
+// block is reorged out.
+type journal struct {
+	Hash    common.Hash
+	Changes []change
+}
+
+// change is a single write to the index along with the value it replaced.
+type change struct {
+	Key     []byte
+	Prev    []byte
+	Existed bool
+}
+
+// storedPoint is a sensor value change as stored in the index.
+type storedPoint struct {
+	Contract  common.Address
+	ValueHash common.Hash
+	Value     string
+	BlockHash common.Hash
+	TxIndex   uint64
+}
+
+// storedDevice is a device registration as stored in the index.
+type storedDevice struct {
+	DeviceID  string
+	Location  string
+	Time      uint64
+	Number    uint64
+	BlockHash common.Hash
+}
+
+// journalKey = journalPrefix + number (uint64 big endian)
+func journalKey(number uint64) []byte {
+	return binary.BigEndian.AppendUint64(append([]byte{}, journalPrefix...), number)
+}
+
+// seriesPrefixKey = seriesPrefix + device + sensor hash
+func seriesPrefixKey(device common.Address, sensor common.Hash) []byte {
+	key := append(append([]byte{}, seriesPrefix...), device.Bytes()...)
+	return append(key, sensor.Bytes()...)
+}
+
+// seriesKey = seriesPrefix + device + sensor hash + time + number + log index
+// (uint64, uint64, uint32 big endian), ordering the points of a sensor by time.
+func seriesKey(device common.Address, sensor common.Hash, time uint64, number uint64, index uint) []byte {
+	key := binary.BigEndian.AppendUint64(seriesPrefixKey(device, sensor), time)
+	key = binary.BigEndian.AppendUint64(key, number)
+	return binary.BigEndian.AppendUint32(key, uint32(index))
+}
+
+// deviceKey = devicePrefix + device + contract
+func deviceKey(device common.Address, contract common.Address) []byte {
+	key := append(append([]byte{}, devicePrefix...), device.Bytes()...)
+	return append(key, contract.Bytes()...)
+}
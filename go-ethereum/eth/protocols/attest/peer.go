@@ -0,0 +1,86 @@
+package attest
+
+import (
+	mapset "github.com/deckarep/golang-set/v2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/poi"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p"
+)
+
+// maxKnownAttestations is the maximum attestation IDs to keep in the known list
+// before starting to randomly evict them.
+const maxKnownAttestations = 8192
+
+// Peer is a collection of relevant information we have about an `attest` peer.
+type Peer struct {
+	id string // Unique ID for the peer, cached
+
+	*p2p.Peer                   // The embedded P2P package peer
+	rw        p2p.MsgReadWriter // Input/output streams for attest
+	version   uint              // Protocol version negotiated
+
+	known mapset.Set[common.Hash] // Set of attestation IDs known to be known by this peer
+
+	logger log.Logger // Contextual logger with the peer id injected
+}
+
+// NewPeer creates a wrapper for a network connection and negotiated  protocol
+// version.
+func NewPeer(version uint, p *p2p.Peer, rw p2p.MsgReadWriter) *Peer {
+	id := p.ID().String()
+	return &Peer{
+		id:      id,
+		Peer:    p,
+		rw:      rw,
+		version: version,
+		known:   mapset.NewSet[common.Hash](),
+		logger:  log.New("peer", id[:8]),
+	}
+}
+
+// ID retrieves the peer's unique identifier.
+func (p *Peer) ID() string {
+	return p.id
+}
+
+// Version retrieves the peer's negotiated `attest` protocol version.
+func (p *Peer) Version() uint {
+	return p.version
+}
+
+// Log overrides the P2P logger with the higher level one containing only the id.
+func (p *Peer) Log() log.Logger {
+	return p.logger
+}
+
+// KnownAttestation returns whether the peer is known to already have an
+// attestation.
+func (p *Peer) KnownAttestation(id common.Hash) bool {
+	return p.known.Contains(id)
+}
+
+// markAttestation marks an attestation as known for the peer, ensuring that it
+// will never be propagated to this particular peer.
+func (p *Peer) markAttestation(id common.Hash) {
+	for p.known.Cardinality() >= maxKnownAttestations {
+		p.known.Pop()
+	}
+	p.known.Add(id)
+}
+
+// SendAttestations propagates a batch of attestations to the remote peer,
+// skipping the ones it is known to have.
+func (p *Peer) SendAttestations(atts []*poi.Attestation) error {
+	var send []*poi.Attestation
+	for _, att := range atts {
+		if id := att.ID(); !p.KnownAttestation(id) {
+			p.markAttestation(id)
+			send = append(send, att)
+		}
+	}
+	if len(send) == 0 {
+		return nil
+	}
+	return p2p.Send(p.rw, AttestationsMsg, AttestationsPacket(send))
+}
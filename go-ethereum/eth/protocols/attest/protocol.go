@@ -0,0 +1,48 @@
+// Package attest implements the `attest` devp2p sub-protocol, gossiping the
+// block attestations of PoI signers that drive the finality gadget.
+package attest
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/consensus/poi"
+)
+
+// Constants to match up protocol versions and messages
+const (
+	ATTEST1 = 1
+)
+
+// ProtocolName is the official short name of the `attest` protocol used during
+// devp2p capability negotiation.
+const ProtocolName = "attest"
+
+// ProtocolVersions are the supported versions of the `attest` protocol (first
+// is primary).
+var ProtocolVersions = []uint{ATTEST1}
+
+// protocolLengths are the number of implemented message corresponding to
+// different protocol versions.
+var protocolLengths = map[uint]uint64{ATTEST1: 1}
+
+// maxMessageSize is the maximum cap on the size of a protocol message.
+const maxMessageSize = 1024 * 1024
+
+// maxAttestations is the maximum number of attestations in a single message.
+const maxAttestations = 256
+
+const (
+	AttestationsMsg = 0x00
+)
+
+var (
+	errMsgTooLarge    = errors.New("message too long")
+	errDecode         = errors.New("invalid message")
+	errInvalidMsgCode = errors.New("invalid message code")
+)
+
+// AttestationsPacket is the network packet for propagating signer attestations.
+type AttestationsPacket []*poi.Attestation
+
+func (*AttestationsPacket) Name() string { return "Attestations" }
+func (*AttestationsPacket) Kind() byte   { return AttestationsMsg }
@@ -1303,28 +1303,29 @@ func (s *BlockChainAPI) rpcMarshalBlock(ctx context.Context, b *types.Block, inc
 
 // RPCTransaction represents a transaction that will serialize to the RPC representation of a transaction
 type RPCTransaction struct {
-	BlockHash           *common.Hash      `json:"blockHash"`
-	BlockNumber         *hexutil.Big      `json:"blockNumber"`
-	From                common.Address    `json:"from"`
-	Gas                 hexutil.Uint64    `json:"gas"`
-	GasPrice            *hexutil.Big      `json:"gasPrice"`
-	GasFeeCap           *hexutil.Big      `json:"maxFeePerGas,omitempty"`
-	GasTipCap           *hexutil.Big      `json:"maxPriorityFeePerGas,omitempty"`
-	MaxFeePerBlobGas    *hexutil.Big      `json:"maxFeePerBlobGas,omitempty"`
-	Hash                common.Hash       `json:"hash"`
-	Input               hexutil.Bytes     `json:"input"`
-	Nonce               hexutil.Uint64    `json:"nonce"`
-	To                  *common.Address   `json:"to"`
-	TransactionIndex    *hexutil.Uint64   `json:"transactionIndex"`
-	Value               *hexutil.Big      `json:"value"`
-	Type                hexutil.Uint64    `json:"type"`
-	Accesses            *types.AccessList `json:"accessList,omitempty"`
-	ChainID             *hexutil.Big      `json:"chainId,omitempty"`
-	BlobVersionedHashes []common.Hash     `json:"blobVersionedHashes,omitempty"`
-	V                   *hexutil.Big      `json:"v"`
-	R                   *hexutil.Big      `json:"r"`
-	S                   *hexutil.Big      `json:"s"`
-	YParity             *hexutil.Uint64   `json:"yParity,omitempty"`
+	BlockHash           *common.Hash          `json:"blockHash"`
+	BlockNumber         *hexutil.Big          `json:"blockNumber"`
+	From                common.Address        `json:"from"`
+	Gas                 hexutil.Uint64        `json:"gas"`
+	GasPrice            *hexutil.Big          `json:"gasPrice"`
+	GasFeeCap           *hexutil.Big          `json:"maxFeePerGas,omitempty"`
+	GasTipCap           *hexutil.Big          `json:"maxPriorityFeePerGas,omitempty"`
+	MaxFeePerBlobGas    *hexutil.Big          `json:"maxFeePerBlobGas,omitempty"`
+	Hash                common.Hash           `json:"hash"`
+	Input               hexutil.Bytes         `json:"input"`
+	Nonce               hexutil.Uint64        `json:"nonce"`
+	To                  *common.Address       `json:"to"`
+	TransactionIndex    *hexutil.Uint64       `json:"transactionIndex"`
+	Value               *hexutil.Big          `json:"value"`
+	Type                hexutil.Uint64        `json:"type"`
+	Accesses            *types.AccessList     `json:"accessList,omitempty"`
+	ChainID             *hexutil.Big          `json:"chainId,omitempty"`
+	BlobVersionedHashes []common.Hash         `json:"blobVersionedHashes,omitempty"`
+	Readings            []types.SensorReading `json:"readings,omitempty"`
+	V                   *hexutil.Big          `json:"v"`
+	R                   *hexutil.Big          `json:"r"`
+	S                   *hexutil.Big          `json:"s"`
+	YParity             *hexutil.Uint64       `json:"yParity,omitempty"`
 }
 
 // newRPCTransaction returns a transaction that will serialize to the RPC
@@ -1399,6 +1400,21 @@ func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber
 		}
 		result.MaxFeePerBlobGas = (*hexutil.Big)(tx.BlobGasFeeCap())
 		result.BlobVersionedHashes = tx.BlobHashes()
+
+	case types.SensorTxType:
+		yparity := hexutil.Uint64(v.Sign())
+		result.ChainID = (*hexutil.Big)(tx.ChainId())
+		result.YParity = &yparity
+		result.GasFeeCap = (*hexutil.Big)(tx.GasFeeCap())
+		result.GasTipCap = (*hexutil.Big)(tx.GasTipCap())
+		// if the transaction has been mined, compute the effective gas price
+		if baseFee != nil && blockHash != (common.Hash{}) {
+			result.GasPrice = (*hexutil.Big)(effectiveGasPrice(tx, baseFee))
+		} else {
+			result.GasPrice = (*hexutil.Big)(tx.GasFeeCap())
+		}
+		// Invalid readings can't be included, but may be pending
+		result.Readings, _ = types.DecodeSensorReadings(tx.Data())
 	}
 	return result
 }
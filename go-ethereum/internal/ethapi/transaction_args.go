@@ -30,6 +30,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto/kzg4844"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
@@ -72,6 +73,9 @@ type TransactionArgs struct {
 	Commitments []kzg4844.Commitment `json:"commitments"`
 	Proofs      []kzg4844.Proof      `json:"proofs"`
 
+	// For SensorTxType
+	Readings []types.SensorReading `json:"readings,omitempty"`
+
 	// This configures whether blobs are allowed to be passed.
 	blobSidecarAllowed bool
 }
@@ -126,6 +130,16 @@ func (args *TransactionArgs) setDefaults(ctx context.Context, b Backend, skipGas
 		return fmt.Errorf(`too many blobs in transaction (have=%d, max=%d)`, len(args.BlobHashes), maxBlobsPerTransaction)
 	}
 
+	// SensorTx fields
+	if args.Readings != nil {
+		if err := args.validateSensorTx(); err != nil {
+			return err
+		}
+		if args.MaxFeePerGas == nil {
+			return errors.New(`sensor transactions require "maxFeePerGas"`)
+		}
+	}
+
 	// create check
 	if args.To == nil {
 		if args.BlobHashes != nil {
@@ -158,6 +172,7 @@ func (args *TransactionArgs) setDefaults(ctx context.Context, b Backend, skipGas
 				AccessList:           args.AccessList,
 				BlobFeeCap:           args.BlobFeeCap,
 				BlobHashes:           args.BlobHashes,
+				Readings:             args.Readings,
 			}
 			latestBlockNr := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
 			estimated, err := DoEstimateGas(ctx, b, callArgs, latestBlockNr, nil, b.RPCGasCap())
@@ -437,6 +452,25 @@ func (args *TransactionArgs) ToMessage(globalGasCap uint64, baseFee *big.Int) (*
 	if args.AccessList != nil {
 		accessList = *args.AccessList
 	}
+	if args.Readings != nil {
+		if err := args.validateSensorTx(); err != nil {
+			return nil, err
+		}
+		readings, _ := types.EncodeSensorReadings(args.Readings)
+		msg := &core.Message{
+			From:              addr,
+			To:                args.To,
+			Value:             value,
+			GasLimit:          gas,
+			GasPrice:          gasPrice,
+			GasFeeCap:         gasFeeCap,
+			GasTipCap:         gasTipCap,
+			Data:              readings,
+			SensorTx:          true,
+			SkipAccountChecks: true,
+		}
+		return msg, nil
+	}
 	msg := &core.Message{
 		From:              addr,
 		To:                args.To,
@@ -459,6 +493,18 @@ func (args *TransactionArgs) ToMessage(globalGasCap uint64, baseFee *big.Int) (*
 func (args *TransactionArgs) toTransaction() *types.Transaction {
 	var data types.TxData
 	switch {
+	case args.Readings != nil:
+		readings, _ := types.EncodeSensorReadings(args.Readings)
+		data = &types.SensorTx{
+			To:        *args.To,
+			ChainID:   (*big.Int)(args.ChainID),
+			Nonce:     uint64(*args.Nonce),
+			Gas:       uint64(*args.Gas),
+			GasFeeCap: (*big.Int)(args.MaxFeePerGas),
+			GasTipCap: (*big.Int)(args.MaxPriorityFeePerGas),
+			Readings:  readings,
+		}
+
 	case args.BlobHashes != nil:
 		al := types.AccessList{}
 		if args.AccessList != nil {
@@ -527,6 +573,30 @@ func (args *TransactionArgs) toTransaction() *types.Transaction {
 	return types.NewTx(data)
 }
 
+// validateSensorTx checks that the readings are valid and the remaining fields
+// can be carried by a sensor transaction.
+func (args *TransactionArgs) validateSensorTx() error {
+	if _, err := types.EncodeSensorReadings(args.Readings); err != nil {
+		return fmt.Errorf("%w: need 1 or more readings with sensor names and values of 1 to 255 bytes", err)
+	}
+	if args.To == nil {
+		return errors.New(`missing "to" in sensor transaction`)
+	}
+	if *args.To != vm.SensorDataAddress {
+		return fmt.Errorf(`sensor transactions must be sent to the sensor data precompile %v`, vm.SensorDataAddress)
+	}
+	if len(args.data()) > 0 {
+		return errors.New(`sensor transactions carry "readings" instead of "input"`)
+	}
+	if args.Value != nil && args.Value.ToInt().Sign() != 0 {
+		return errors.New(`sensor transactions can't carry "value"`)
+	}
+	if args.AccessList != nil || args.BlobHashes != nil {
+		return errors.New(`sensor transactions can't carry an access list or blobs`)
+	}
+	return nil
+}
+
 // IsEIP4844 returns an indicator if the args contains EIP4844 fields.
 func (args *TransactionArgs) IsEIP4844() bool {
 	return args.BlobHashes != nil || args.BlobFeeCap != nil
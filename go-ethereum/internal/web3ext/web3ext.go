@@ -21,6 +21,7 @@ var Modules = map[string]string{
 	"admin":    AdminJs,
 	"clique":   CliqueJs,
 	"poi":      PoiJs,
+	"iot":      IoTJs,
 	"ethash":   EthashJs,
 	"debug":    DebugJs,
 	"eth":      EthJs,
@@ -143,12 +144,107 @@ web3._extend({
 			call: 'poi_setSignerPerformance',
 			params: 2
 		}),
+		new web3._extend.Method({
+			name: 'setSignerHealth',
+			call: 'poi_setSignerHealth',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'getHealth',
+			call: 'poi_getHealth',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getHealthHistory',
+			call: 'poi_getHealthHistory',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getFailures',
+			call: 'poi_getFailures',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getActiveSigners',
+			call: 'poi_getActiveSigners',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getSchedule',
+			call: 'poi_getSchedule',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, null]
+		}),
+		new web3._extend.Method({
+			name: 'proposeDevice',
+			call: 'poi_proposeDevice',
+			params: 3
+		}),
+		new web3._extend.Method({
+			name: 'discardDevice',
+			call: 'poi_discardDevice',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getDevices',
+			call: 'poi_getDevices',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getDevice',
+			call: 'poi_getDevice',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'submitEvidence',
+			call: 'poi_submitEvidence',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'rotate',
+			call: 'poi_rotate',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'submitRotation',
+			call: 'poi_submitRotation',
+			params: 1
+		}),
 	],
 	properties: [
 		new web3._extend.Property({
 			name: 'proposals',
 			getter: 'poi_proposals'
 		}),
+		new web3._extend.Property({
+			name: 'deviceProposals',
+			getter: 'poi_deviceProposals'
+		}),
+	]
+});
+`
+
+const IoTJs = `
+web3._extend({
+	property: 'iot',
+	methods: [
+		new web3._extend.Method({
+			name: 'getSeries',
+			call: 'iot_getSeries',
+			params: 4,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null, web3._extend.utils.fromDecimal, web3._extend.utils.fromDecimal]
+		}),
+		new web3._extend.Method({
+			name: 'getDevices',
+			call: 'iot_getDevices',
+			params: 0
+		}),
 	]
 });
 `
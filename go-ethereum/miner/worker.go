@@ -471,7 +471,7 @@ func (w *worker) newWorkLoop(recommit time.Duration) {
 		case <-timer.C:
 			// If sealing is running resubmit a new work cycle periodically to pull in
 			// higher priced transactions. Disable this overhead for pending blocks.
-			if w.isRunning() && (w.chainConfig.Clique == nil || w.chainConfig.Clique.Period > 0) {
+			if w.isRunning() && !w.sealsOnDemand() {
 				// Short circuit if no new transaction arrives.
 				if w.newTxs.Load() == 0 {
 					timer.Reset(recommit)
@@ -574,10 +574,10 @@ func (w *worker) mainLoop() {
 					w.updateSnapshot(w.current)
 				}
 			} else {
-				// Special case, if the consensus engine is 0 period clique(dev mode),
-				// submit sealing work here since all empty submission will be rejected
-				// by clique. Of course the advance sealing(empty submission) is disabled.
-				if w.chainConfig.Clique != nil && w.chainConfig.Clique.Period == 0 {
+				// Special case, if the consensus engine seals on demand (e.g. 0 period
+				// clique or poi), submit sealing work here since all empty submission
+				// will be rejected. Of course the advance sealing(empty submission) is disabled.
+				if w.sealsOnDemand() {
 					w.commitWork(nil, time.Now().Unix())
 				}
 			}
@@ -882,6 +882,14 @@ func (w *worker) commitTransactions(env *environment, plainTxs, blobTxs *transac
 			txs.Pop()
 			continue
 		}
+		// Ignore all transactions of senders the consensus engine doesn't allow
+		if validator, ok := w.engine.(consensus.TxValidator); ok && validator.RestrictsSenders(env.header) {
+			if err := validator.ValidateTxSender(w.chain, env.header, from); err != nil {
+				log.Trace("Ignoring transaction of restricted sender", "hash", ltx.Hash, "sender", from, "err", err)
+				txs.Pop()
+				continue
+			}
+		}
 		// Start executing the transaction
 		env.state.SetTxContext(tx.Hash(), env.tcount)
 
@@ -1189,8 +1197,9 @@ func (w *worker) commit(env *environment, interval func(), update bool, start ti
 		if err != nil {
 			return err
 		}
-		// If we're post merge, just ignore
-		if !w.isTTDReached(block.Header()) {
+		// If we're post merge, just ignore. Empty blocks are never sealed by on
+		// demand engines, don't bother submitting them.
+		if !w.isTTDReached(block.Header()) && !(env.tcount == 0 && w.sealsOnDemand()) {
 			select {
 			case w.taskCh <- &task{receipts: env.receipts, state: env.state, block: block, createdAt: time.Now()}:
 				fees := totalFees(block, env.receipts)
@@ -1210,6 +1219,15 @@ func (w *worker) commit(env *environment, interval func(), update bool, start ti
 	return nil
 }
 
+// sealsOnDemand returns whether the consensus engine only seals blocks with
+// transactions, in which case sealing work is only committed on new transactions.
+func (w *worker) sealsOnDemand() bool {
+	if engine, ok := w.engine.(consensus.OnDemandSealer); ok {
+		return engine.SealsOnDemand()
+	}
+	return false
+}
+
 // getSealingBlock generates the sealing block based on the given parameters.
 // The generation result will be passed back via the given channel no matter
 // the generation itself succeeds or not.
@@ -27,6 +27,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/consensus/clique"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/consensus/poi"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/txpool"
@@ -126,6 +127,12 @@ func newTestWorkerBackend(t *testing.T, chainConfig *params.ChainConfig, engine
 		e.Authorize(testBankAddress, func(account accounts.Account, s string, data []byte) ([]byte, error) {
 			return crypto.Sign(crypto.Keccak256(data), testBankKey)
 		})
+	case *poi.Poi:
+		gspec.ExtraData = make([]byte, 32+common.AddressLength+crypto.SignatureLength)
+		copy(gspec.ExtraData[32:32+common.AddressLength], testBankAddress.Bytes())
+		e.Authorize(testBankAddress, func(account accounts.Account, s string, data []byte) ([]byte, error) {
+			return crypto.Sign(crypto.Keccak256(data), testBankKey)
+		})
 	case *ethash.Ethash:
 	default:
 		t.Fatalf("unexpected consensus engine type: %T", engine)
@@ -211,6 +218,47 @@ func TestGenerateAndImportBlock(t *testing.T) {
 	}
 }
 
+func TestOnDemandSealingPoi(t *testing.T) {
+	t.Parallel()
+	var (
+		db     = rawdb.NewMemoryDatabase()
+		config = *params.TestChainConfig
+	)
+	config.Poi = &params.PoiConfig{Period: 0, Epoch: 30000}
+	engine := poi.New(config.Poi, db)
+	defer engine.Close()
+
+	// Create a worker without any pending transactions
+	b := newTestWorkerBackend(t, &config, engine, db, 0)
+	w := newWorker(testConfig, &config, engine, b, new(event.TypeMux), nil, false)
+	w.setEtherbase(testBankAddress)
+	defer w.close()
+
+	taskCh := make(chan int, 4)
+	w.newTaskHook = func(task *task) {
+		taskCh <- len(task.receipts)
+	}
+	w.skipSealHook = func(task *task) bool { return true }
+	w.start() // Start mining!
+
+	// Empty blocks are never sealed, no task should be submitted
+	select {
+	case receipts := <-taskCh:
+		t.Fatalf("unexpected task with %d receipts", receipts)
+	case <-time.NewTimer(2 * testConfig.Recommit).C:
+	}
+	// New transactions should be picked up right away
+	b.txPool.Add([]*types.Transaction{b.newRandomTx(false)}, true, false)
+	select {
+	case receipts := <-taskCh:
+		if receipts != 1 {
+			t.Fatalf("receipt number mismatch: have %d, want %d", receipts, 1)
+		}
+	case <-time.NewTimer(testConfig.Recommit / 2).C:
+		t.Fatal("new task timeout")
+	}
+}
+
 func TestEmptyWorkEthash(t *testing.T) {
 	t.Parallel()
 	testEmptyWork(t, ethashChainConfig, ethash.NewFaker())
@@ -381,6 +381,8 @@ type ChainConfig struct {
 	ArrowGlacierBlock   *big.Int `json:"arrowGlacierBlock,omitempty"`   // Eip-4345 (bomb delay) switch block (nil = no fork, 0 = already activated)
 	GrayGlacierBlock    *big.Int `json:"grayGlacierBlock,omitempty"`    // Eip-5133 (bomb delay) switch block (nil = no fork, 0 = already activated)
 	MergeNetsplitBlock  *big.Int `json:"mergeNetsplitBlock,omitempty"`  // Virtual fork after The Merge to use as a network splitter
+	PoiBlock            *big.Int `json:"poiBlock,omitempty"`            // Clique to PoI switch block (nil = no fork, requires both engine configs)
+	SensorDataBlock     *big.Int `json:"sensorDataBlock,omitempty"`     // Sensor data precompile switch block (nil = no fork, PoI chains only)
 
 	// Fork scheduling was switched from blocks to timestamps here
 
@@ -430,6 +432,38 @@ type PoiConfig struct {
 	BackupTimeout   uint64 `json:"backupTimeout"`   // Timeout in seconds before backup activation
 	HealthThreshold int    `json:"healthThreshold"` // Number of failures before marking unhealthy
 	RecoveryPeriod  uint64 `json:"recoveryPeriod"`  // Time in seconds before node can be healthy again
+	RecoveryBlocks  uint64 `json:"recoveryBlocks"`  // Number of blocks an unhealthy signer has to seal to be healthy again
+
+	WeightedSelection bool `json:"weightedSelection"` // Whether in-turn slots are allotted proportionally to signer performance
+	RestrictSenders   bool `json:"restrictSenders"`   // Whether only registered devices and signers may send transactions
+
+	Governance *common.Address `json:"governance,omitempty"` // Contract managing the signer set at epoch checkpoints instead of signer votes
+
+	BlockReward  *big.Int `json:"blockReward,omitempty"` // Wei minted to the signer of every block (nil = no issuance)
+	InTurnBonus  *big.Int `json:"inTurnBonus,omitempty"` // Wei minted to the signer of in-turn blocks on top of the block reward
+	ShareBaseFee bool     `json:"shareBaseFee"`          // Whether the base fee is split among the active signers instead of burnt
+
+	Telemetry bool `json:"telemetry"` // Whether signers seal their node telemetry into blocks, deriving their performance from it
+
+	Overrides []*PoiOverride `json:"overrides,omitempty"` // Parameter changes scheduled at block heights, in ascending order
+}
+
+// PoiOverride changes PoI parameters of a running network from a block on. Unset
+// fields keep the value in effect before. The epoch, weighted selection, the
+// governance contract and telemetry can't be changed at a height, as snapshots
+// and checkpoints depend on them.
+type PoiOverride struct {
+	Block *big.Int `json:"block"` // Block number the parameters take effect at
+
+	Period          *uint64  `json:"period,omitempty"`          // Number of seconds between blocks to enforce (non-zero)
+	BackupTimeout   *uint64  `json:"backupTimeout,omitempty"`   // Timeout in seconds before backup activation
+	HealthThreshold *int     `json:"healthThreshold,omitempty"` // Number of failures before marking unhealthy
+	RecoveryPeriod  *uint64  `json:"recoveryPeriod,omitempty"`  // Time in seconds before node can be healthy again
+	RecoveryBlocks  *uint64  `json:"recoveryBlocks,omitempty"`  // Number of blocks an unhealthy signer has to seal to be healthy again
+	RestrictSenders *bool    `json:"restrictSenders,omitempty"` // Whether only registered devices and signers may send transactions
+	BlockReward     *big.Int `json:"blockReward,omitempty"`     // Wei minted to the signer of every block (0 = stop issuance)
+	InTurnBonus     *big.Int `json:"inTurnBonus,omitempty"`     // Wei minted to the signer of in-turn blocks on top of the block reward
+	ShareBaseFee    *bool    `json:"shareBaseFee,omitempty"`    // Whether the base fee is split among the active signers instead of burnt
 }
 
 // String implements the stringer interface, returning the consensus engine details.
@@ -437,6 +471,91 @@ func (c *PoiConfig) String() string {
 	return "poi"
 }
 
+// At returns the parameters in effect at the given block number, with all the
+// overrides scheduled at or below it applied.
+func (c *PoiConfig) At(number uint64) *PoiConfig {
+	if len(c.Overrides) == 0 || c.Overrides[0].Block.Uint64() > number {
+		return c
+	}
+	cpy := *c
+	for _, o := range c.Overrides {
+		if o.Block.Uint64() > number {
+			break
+		}
+		if o.Period != nil {
+			cpy.Period = *o.Period
+		}
+		if o.BackupTimeout != nil {
+			cpy.BackupTimeout = *o.BackupTimeout
+		}
+		if o.HealthThreshold != nil {
+			cpy.HealthThreshold = *o.HealthThreshold
+		}
+		if o.RecoveryPeriod != nil {
+			cpy.RecoveryPeriod = *o.RecoveryPeriod
+		}
+		if o.RecoveryBlocks != nil {
+			cpy.RecoveryBlocks = *o.RecoveryBlocks
+		}
+		if o.RestrictSenders != nil {
+			cpy.RestrictSenders = *o.RestrictSenders
+		}
+		if o.BlockReward != nil {
+			cpy.BlockReward = o.BlockReward
+		}
+		if o.InTurnBonus != nil {
+			cpy.InTurnBonus = o.InTurnBonus
+		}
+		if o.ShareBaseFee != nil {
+			cpy.ShareBaseFee = *o.ShareBaseFee
+		}
+	}
+	return &cpy
+}
+
+// checkOverrides ensures that the overrides are scheduled in ascending order and
+// don't switch on-demand sealing on or off.
+func (c *PoiConfig) checkOverrides() error {
+	var last *big.Int
+	for i, o := range c.Overrides {
+		if o.Block == nil {
+			return fmt.Errorf("poi override %d has no block", i)
+		}
+		if last != nil && o.Block.Cmp(last) <= 0 {
+			return fmt.Errorf("unsupported poi override ordering: override %d at block %v, but override %d at block %v", i-1, last, i, o.Block)
+		}
+		if o.Period != nil && (*o.Period == 0 || c.Period == 0) {
+			return fmt.Errorf("poi override %d at block %v changes the period of on-demand sealing", i, o.Block)
+		}
+		last = o.Block
+	}
+	return nil
+}
+
+// equal returns whether two overrides change the same parameters to the same
+// values at the same block.
+func (o *PoiOverride) equal(other *PoiOverride) bool {
+	return configBlockEqual(o.Block, other.Block) &&
+		ptrEqual(o.Period, other.Period) &&
+		ptrEqual(o.BackupTimeout, other.BackupTimeout) &&
+		ptrEqual(o.HealthThreshold, other.HealthThreshold) &&
+		ptrEqual(o.RecoveryPeriod, other.RecoveryPeriod) &&
+		ptrEqual(o.RecoveryBlocks, other.RecoveryBlocks) &&
+		ptrEqual(o.RestrictSenders, other.RestrictSenders) &&
+		configBlockEqual(o.BlockReward, other.BlockReward) &&
+		configBlockEqual(o.InTurnBonus, other.InTurnBonus) &&
+		ptrEqual(o.ShareBaseFee, other.ShareBaseFee)
+}
+
+// ptrEqual returns whether two optional values are both unset or both set to the
+// same value.
+func ptrEqual[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
 // Description returns a human-readable description of ChainConfig.
 func (c *ChainConfig) Description() string {
 	var banner string
@@ -457,13 +576,17 @@ func (c *ChainConfig) Description() string {
 			banner += "Consensus: Beacon (proof-of-stake), merged from Ethash (proof-of-work)\n"
 		}
 	case c.Clique != nil:
-		if c.TerminalTotalDifficulty == nil {
+		if c.Poi != nil && c.PoiBlock != nil {
+			banner += fmt.Sprintf("Consensus: Clique (proof-of-authority), switching to PoI at #%v\n", c.PoiBlock)
+		} else if c.TerminalTotalDifficulty == nil {
 			banner += "Consensus: Clique (proof-of-authority)\n"
 		} else if !c.TerminalTotalDifficultyPassed {
 			banner += "Consensus: Beacon (proof-of-stake), merging from Clique (proof-of-authority)\n"
 		} else {
 			banner += "Consensus: Beacon (proof-of-stake), merged from Clique (proof-of-authority)\n"
 		}
+	case c.Poi != nil:
+		banner += "Consensus: PoI (proof-of-authority)\n"
 	default:
 		banner += "Consensus: unknown\n"
 	}
@@ -495,6 +618,9 @@ func (c *ChainConfig) Description() string {
 	if c.GrayGlacierBlock != nil {
 		banner += fmt.Sprintf(" - Gray Glacier:                #%-8v (https://github.com/ethereum/execution-specs/blob/master/network-upgrades/mainnet-upgrades/gray-glacier.md)\n", c.GrayGlacierBlock)
 	}
+	if c.SensorDataBlock != nil {
+		banner += fmt.Sprintf(" - Sensor data precompile:      #%-8v\n", c.SensorDataBlock)
+	}
 	banner += "\n"
 
 	// Add a special section for the merge as it's non-obvious
@@ -601,6 +727,17 @@ func (c *ChainConfig) IsGrayGlacier(num *big.Int) bool {
 	return isBlockForked(c.GrayGlacierBlock, num)
 }
 
+// IsPoi returns whether num is either equal to the PoI fork block or greater.
+func (c *ChainConfig) IsPoi(num *big.Int) bool {
+	return isBlockForked(c.PoiBlock, num)
+}
+
+// IsSensorData returns whether num is either equal to the sensor data precompile
+// fork block or greater.
+func (c *ChainConfig) IsSensorData(num *big.Int) bool {
+	return isBlockForked(c.SensorDataBlock, num)
+}
+
 // IsTerminalPoWBlock returns whether the given block is the last block of PoW stage.
 func (c *ChainConfig) IsTerminalPoWBlock(parentTotalDiff *big.Int, totalDiff *big.Int) bool {
 	if c.TerminalTotalDifficulty == nil {
@@ -719,6 +856,22 @@ func (c *ChainConfig) CheckConfigForkOrder() error {
 			lastFork = cur
 		}
 	}
+	if c.Poi != nil {
+		if err := c.Poi.checkOverrides(); err != nil {
+			return err
+		}
+	}
+	// The sensor data precompile is a PoI extension, so it can only be enabled
+	// once the chain is sealed by PoI
+	if c.SensorDataBlock != nil {
+		if c.Poi == nil {
+			return fmt.Errorf("sensorDataBlock %v requires the poi engine", c.SensorDataBlock)
+		}
+		if c.PoiBlock != nil && c.SensorDataBlock.Cmp(c.PoiBlock) < 0 {
+			return fmt.Errorf("unsupported fork ordering: poiBlock enabled at block %v, but sensorDataBlock enabled at block %v",
+				c.PoiBlock, c.SensorDataBlock)
+		}
+	}
 	return nil
 }
 
@@ -778,6 +931,17 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, headNumber *big.Int,
 	if isForkBlockIncompatible(c.MergeNetsplitBlock, newcfg.MergeNetsplitBlock, headNumber) {
 		return newBlockCompatError("Merge netsplit fork block", c.MergeNetsplitBlock, newcfg.MergeNetsplitBlock)
 	}
+	if isForkBlockIncompatible(c.PoiBlock, newcfg.PoiBlock, headNumber) {
+		return newBlockCompatError("PoI fork block", c.PoiBlock, newcfg.PoiBlock)
+	}
+	if isForkBlockIncompatible(c.SensorDataBlock, newcfg.SensorDataBlock, headNumber) {
+		return newBlockCompatError("Sensor data fork block", c.SensorDataBlock, newcfg.SensorDataBlock)
+	}
+	if c.Poi != nil && newcfg.Poi != nil {
+		if stored, new, ok := poiOverridesIncompatible(c.Poi.Overrides, newcfg.Poi.Overrides, headNumber); !ok {
+			return newBlockCompatError("PoI override block", stored, new)
+		}
+	}
 	if isForkTimestampIncompatible(c.ShanghaiTime, newcfg.ShanghaiTime, headTimestamp) {
 		return newTimestampCompatError("Shanghai fork timestamp", c.ShanghaiTime, newcfg.ShanghaiTime)
 	}
@@ -820,6 +984,35 @@ func (c *ChainConfig) LatestFork(time uint64) forks.Fork {
 	}
 }
 
+// poiOverridesIncompatible checks whether the PoI overrides scheduled at or below
+// the head differ between two configs, returning the blocks of the first change
+// and false if they do.
+func poiOverridesIncompatible(stored, new []*PoiOverride, head *big.Int) (*big.Int, *big.Int, bool) {
+	for i := 0; i < len(stored) || i < len(new); i++ {
+		var a, b *PoiOverride
+		if i < len(stored) {
+			a = stored[i]
+		}
+		if i < len(new) {
+			b = new[i]
+		}
+		switch {
+		case a != nil && b != nil && a.equal(b):
+			continue
+		case a != nil && isBlockForked(a.Block, head), b != nil && isBlockForked(b.Block, head):
+			var sb, nb *big.Int
+			if a != nil {
+				sb = a.Block
+			}
+			if b != nil {
+				nb = b.Block
+			}
+			return sb, nb, false
+		}
+	}
+	return nil, nil, true
+}
+
 // isForkBlockIncompatible returns true if a fork scheduled at block s1 cannot be
 // rescheduled to block s2 because head is already past the fork.
 func isForkBlockIncompatible(s1, s2, head *big.Int) bool {
@@ -953,6 +1146,7 @@ type Rules struct {
 	IsBerlin, IsLondon                                      bool
 	IsMerge, IsShanghai, IsCancun, IsPrague                 bool
 	IsVerkle                                                bool
+	IsSensorData                                            bool
 }
 
 // Rules ensures c's ChainID is not nil.
@@ -980,5 +1174,6 @@ func (c *ChainConfig) Rules(num *big.Int, isMerge bool, timestamp uint64) Rules
 		IsCancun:         isMerge && c.IsCancun(num, timestamp),
 		IsPrague:         isMerge && c.IsPrague(num, timestamp),
 		IsVerkle:         isMerge && c.IsVerkle(num, timestamp),
+		IsSensorData:     c.IsSensorData(num),
 	}
 }
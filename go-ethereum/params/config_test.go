@@ -137,3 +137,71 @@ func TestConfigRules(t *testing.T) {
 		t.Errorf("expected %v to be shanghai", stamp)
 	}
 }
+
+func TestCheckSensorDataFork(t *testing.T) {
+	c := *AllPoiProtocolChanges
+	c.SensorDataBlock = big.NewInt(10)
+	if err := c.CheckConfigForkOrder(); err != nil {
+		t.Errorf("valid sensor data fork rejected: %v", err)
+	}
+	c.PoiBlock = big.NewInt(20)
+	if err := c.CheckConfigForkOrder(); err == nil {
+		t.Errorf("sensor data fork before the PoI fork accepted")
+	}
+	c.Poi, c.PoiBlock = nil, nil
+	if err := c.CheckConfigForkOrder(); err == nil {
+		t.Errorf("sensor data fork without PoI accepted")
+	}
+}
+
+func TestPoiOverrides(t *testing.T) {
+	period, reward := uint64(10), big.NewInt(5)
+	config := &PoiConfig{Period: 5, Epoch: 30000, BlockReward: big.NewInt(1)}
+	config.Overrides = []*PoiOverride{
+		{Block: big.NewInt(10), Period: &period},
+		{Block: big.NewInt(20), BlockReward: reward},
+	}
+	for _, tt := range []struct {
+		number uint64
+		period uint64
+		reward int64
+	}{
+		{0, 5, 1}, {9, 5, 1}, {10, 10, 1}, {19, 10, 1}, {20, 10, 5}, {100, 10, 5},
+	} {
+		have := config.At(tt.number)
+		if have.Period != tt.period || have.BlockReward.Int64() != tt.reward {
+			t.Errorf("block %d: parameters mismatch: have period %d reward %v, want period %d reward %d", tt.number, have.Period, have.BlockReward, tt.period, tt.reward)
+		}
+	}
+	if config.Period != 5 || config.BlockReward.Int64() != 1 {
+		t.Errorf("base parameters changed: period %d reward %v", config.Period, config.BlockReward)
+	}
+	// Overrides must be ordered and may not touch on-demand sealing
+	c := *AllPoiProtocolChanges
+	c.Poi = config
+	if err := c.CheckConfigForkOrder(); err != nil {
+		t.Errorf("valid overrides rejected: %v", err)
+	}
+	c.Poi = &PoiConfig{Period: 5, Epoch: 30000, Overrides: []*PoiOverride{config.Overrides[1], config.Overrides[0]}}
+	if err := c.CheckConfigForkOrder(); err == nil {
+		t.Errorf("unordered overrides accepted")
+	}
+	zero := uint64(0)
+	c.Poi = &PoiConfig{Period: 5, Epoch: 30000, Overrides: []*PoiOverride{{Block: big.NewInt(10), Period: &zero}}}
+	if err := c.CheckConfigForkOrder(); err == nil {
+		t.Errorf("override switching to on-demand sealing accepted")
+	}
+	// Overrides already in effect can't change, future ones can
+	stored, changed := *AllPoiProtocolChanges, *AllPoiProtocolChanges
+	stored.Poi = config
+	changed.Poi = &PoiConfig{Period: 5, Epoch: 30000, BlockReward: big.NewInt(1), Overrides: []*PoiOverride{
+		config.Overrides[0],
+		{Block: big.NewInt(30), BlockReward: reward},
+	}}
+	if err := stored.CheckCompatible(&changed, 15, 0); err != nil {
+		t.Errorf("rescheduling future override rejected: %v", err)
+	}
+	if err := stored.CheckCompatible(&changed, 25, 0); err == nil {
+		t.Errorf("rescheduling applied override accepted")
+	}
+}
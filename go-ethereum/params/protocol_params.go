@@ -94,6 +94,10 @@ const (
 	TxAccessListAddressGas    uint64 = 2400 // Per address specified in EIP 2930 access list
 	TxAccessListStorageKeyGas uint64 = 1900 // Per storage key specified in EIP 2930 access list
 
+	SensorTxGas        uint64 = 12000 // Per sensor transaction, which carries no value and calls an existing account
+	SensorTxReadingGas uint64 = 200   // Per reading of a sensor transaction
+	SensorTxByteGas    uint64 = 16    // Per byte of the compact readings of a sensor transaction
+
 	// These have been changed during the course of the chain
 	CallGasFrontier              uint64 = 40  // Once per CALL operation & message call transaction.
 	CallGasEIP150                uint64 = 700 // Static portion of gas for CALL-derivates after EIP 150 (Tangerine)
@@ -159,6 +163,12 @@ const (
 	Bls12381MapG1Gas          uint64 = 5500   // Gas price for BLS12-381 mapping field element to G1 operation
 	Bls12381MapG2Gas          uint64 = 110000 // Gas price for BLS12-381 mapping field element to G2 operation
 
+	SensorDataBaseGas      uint64 = 1000                    // Base price for a sensor data update
+	SensorDataReadingGas   uint64 = 8000                    // Per-reading price for a sensor data update, covering its storage writes and log topics
+	SensorDataByteGas      uint64 = 16                      // Per-byte price for the input of a sensor data update
+	SensorDataQueryGas     uint64 = 200                     // Base price for a sensor data query
+	SensorDataNewSensorGas uint64 = 2 * SstoreSetGasEIP2200 // Price for creating the two storage slots of a sensor updated for the first time
+
 	// The Refund Quotient is the cap on how much of the used gas can be refunded. Before EIP-3529,
 	// up to half the consumed gas could be refunded. Redefined as 1/5th in EIP-3529
 	RefundQuotient        uint64 = 2
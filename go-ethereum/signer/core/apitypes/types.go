@@ -184,6 +184,18 @@ var (
 		accounts.MimetypeClique,
 		0x02,
 	}
+	ApplicationPoi = SigFormat{
+		accounts.MimetypePoi,
+		0x03,
+	}
+	ApplicationPoiAttestation = SigFormat{
+		accounts.MimetypePoiAttestation,
+		0x04,
+	}
+	ApplicationPoiRotation = SigFormat{
+		accounts.MimetypePoiRotation,
+		0x05,
+	}
 	TextPlain = SigFormat{
 		accounts.MimetypeTextPlain,
 		0x45,
@@ -17,16 +17,19 @@
 package core
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"mime"
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/consensus/clique"
+	"github.com/ethereum/go-ethereum/consensus/poi"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rlp"
@@ -164,6 +167,85 @@ func (api *SignerAPI) determineSignatureFormat(ctx context.Context, contentType
 		// Clique uses V on the form 0 or 1
 		useEthereumV = false
 		req = &SignDataRequest{ContentType: mediaType, Rawdata: cliqueRlp, Messages: messages, Hash: sighash}
+	case apitypes.ApplicationPoi.Mime:
+		// PoI headers are sealed the same way as clique ones
+		poiData, err := fromHex(data)
+		if err != nil {
+			return nil, useEthereumV, err
+		}
+		header := &types.Header{}
+		if err := rlp.DecodeBytes(poiData, header); err != nil {
+			return nil, useEthereumV, err
+		}
+		// Add space in the extradata to put the signature
+		newExtra := make([]byte, len(header.Extra)+65)
+		copy(newExtra, header.Extra)
+		header.Extra = newExtra
+
+		// Get back the rlp data, encoded by us
+		sighash, poiRlp, err := poiHeaderHashAndRlp(header)
+		if err != nil {
+			return nil, useEthereumV, err
+		}
+		// PoI uses V on the form 0 or 1
+		useEthereumV = false
+		req = &SignDataRequest{ContentType: mediaType, Rawdata: poiRlp, Messages: poiHeaderMessages(header), Hash: sighash, Header: header}
+	case apitypes.ApplicationPoiAttestation.Mime:
+		// PoI signers attest to blocks for the finality gadget
+		attData, err := fromHex(data)
+		if err != nil {
+			return nil, useEthereumV, err
+		}
+		var att struct {
+			Tag    string
+			Number uint64
+			Hash   common.Hash
+		}
+		if err := rlp.DecodeBytes(attData, &att); err != nil {
+			return nil, useEthereumV, err
+		}
+		attRlp := poi.AttestationRLP(att.Number, att.Hash)
+		if !bytes.Equal(attRlp, attData) {
+			return nil, useEthereumV, errors.New("invalid poi attestation")
+		}
+		messages := []*apitypes.NameValueType{
+			{
+				Name:  "PoI attestation",
+				Typ:   "poi",
+				Value: fmt.Sprintf("finalize block %d [%#x]", att.Number, att.Hash),
+			},
+		}
+		// PoI uses V on the form 0 or 1
+		useEthereumV = false
+		req = &SignDataRequest{ContentType: mediaType, Rawdata: attRlp, Messages: messages, Hash: crypto.Keccak256(attRlp)}
+	case apitypes.ApplicationPoiRotation.Mime:
+		// PoI signers hand their identity over to a new key
+		rotData, err := fromHex(data)
+		if err != nil {
+			return nil, useEthereumV, err
+		}
+		var rot struct {
+			Tag    string
+			Signer common.Address
+			Number uint64
+		}
+		if err := rlp.DecodeBytes(rotData, &rot); err != nil {
+			return nil, useEthereumV, err
+		}
+		rotRlp := poi.RotationRLP(rot.Signer, rot.Number)
+		if !bytes.Equal(rotRlp, rotData) {
+			return nil, useEthereumV, errors.New("invalid poi rotation")
+		}
+		messages := []*apitypes.NameValueType{
+			{
+				Name:  "PoI signer rotation",
+				Typ:   "poi",
+				Value: fmt.Sprintf("hand signer over to %v at block %d", rot.Signer, rot.Number),
+			},
+		}
+		// PoI uses V on the form 0 or 1
+		useEthereumV = false
+		req = &SignDataRequest{ContentType: mediaType, Rawdata: rotRlp, Messages: messages, Hash: crypto.Keccak256(rotRlp)}
 	case apitypes.DataTyped.Mime:
 		// EIP-712 conformant typed data
 		var err error
@@ -219,6 +301,62 @@ func cliqueHeaderHashAndRlp(header *types.Header) (hash, rlp []byte, err error)
 	return hash, rlp, err
 }
 
+// poiHeaderHashAndRlp returns the hash which is used as input for the proof-of-identity
+// signing. It is the hash of the entire header apart from the 65 byte signature
+// contained at the end of the extra data.
+//
+// The method requires the extra data to be at least 65 bytes to avoid the panic in poi.go.
+func poiHeaderHashAndRlp(header *types.Header) (hash, rlp []byte, err error) {
+	if len(header.Extra) < 65 {
+		err = fmt.Errorf("poi header extradata too short, %d < 65", len(header.Extra))
+		return
+	}
+	rlp = poi.PoiRLP(header)
+	hash = poi.SealHash(header).Bytes()
+	return hash, rlp, err
+}
+
+// poiHeaderMessages returns the details of a PoI header to display to the user
+// before signing it, so that votes cast by the header can be reviewed.
+func poiHeaderMessages(header *types.Header) []*apitypes.NameValueType {
+	messages := []*apitypes.NameValueType{
+		{
+			Name:  "PoI header",
+			Typ:   "poi",
+			Value: fmt.Sprintf("poi header %d [%#x]", header.Number, header.Hash()),
+		},
+		{
+			Name:  "Parent hash",
+			Typ:   "hash",
+			Value: header.ParentHash.Hex(),
+		},
+		{
+			Name:  "Difficulty",
+			Typ:   "uint256",
+			Value: fmt.Sprintf("%v", header.Difficulty),
+		},
+	}
+	if header.Coinbase != (common.Address{}) {
+		vote := "deauthorize"
+		if header.Nonce.Uint64() == math.MaxUint64 {
+			vote = "authorize"
+		}
+		messages = append(messages, &apitypes.NameValueType{
+			Name:  "Signer vote",
+			Typ:   "address",
+			Value: fmt.Sprintf("%s %s", vote, header.Coinbase.Hex()),
+		})
+	}
+	if header.MixDigest != (common.Hash{}) {
+		messages = append(messages, &apitypes.NameValueType{
+			Name:  "Signer status vote",
+			Typ:   "hexdata",
+			Value: header.MixDigest.Hex(),
+		})
+	}
+	return messages
+}
+
 // SignTypedData signs EIP-712 conformant typed data
 // hash = keccak256("\x19${byteVersion}${domainSeparator}${hashStruct(message)}")
 // It returns
@@ -24,6 +24,7 @@ import (
 	"strings"
 
 	"github.com/dop251/goja"
+	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/internal/jsre/deps"
 	"github.com/ethereum/go-ethereum/log"
@@ -169,6 +170,13 @@ func (r *rulesetUI) ApproveTx(request *core.SignTxRequest) (core.SignTxResponse,
 
 func (r *rulesetUI) ApproveSignData(request *core.SignDataRequest) (core.SignDataResponse, error) {
 	jsonreq, err := json.Marshal(request)
+	if request != nil && request.ContentType == accounts.MimetypePoi {
+		// PoI headers go through the dedicated hook if the ruleset defines it, so
+		// that rules can e.g. refuse to sign two different headers at one height
+		if approved, err := r.checkApproval("ApprovePoiHeader", jsonreq, err); err == nil {
+			return core.SignDataResponse{Approved: approved}, nil
+		}
+	}
 	approved, err := r.checkApproval("ApproveSignData", jsonreq, err)
 	if err != nil {
 		log.Info("Rule-based approval error, going to manual", "error", err)
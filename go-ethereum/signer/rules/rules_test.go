@@ -624,3 +624,46 @@ function ApproveSignData(r){
 		t.Fatalf("Expected approved")
 	}
 }
+
+func TestSignPoiHeader(t *testing.T) {
+	t.Parallel()
+	js := `function ApprovePoiHeader(r){
+    var number = parseInt(r.header.number);
+    var last = storage.get("lastPoiHeader");
+    if(last != "" && number <= parseInt(last)){
+        return "Reject"
+    }
+    storage.put("lastPoiHeader", "" + number);
+    return "Approve"
+}`
+	r, err := initRuleEngine(js)
+	if err != nil {
+		t.Fatalf("Couldn't create evaluator %v", err)
+	}
+	addr, _ := mixAddr("0x694267f14675d7e1b9494fd8d72fefe1755710fa")
+	tests := []struct {
+		number   int64
+		approved bool
+	}{
+		{1, true},
+		{2, true},
+		{2, false}, // Same height signed twice, equivocation
+		{1, false},
+		{3, true},
+	}
+	for i, tt := range tests {
+		header := &types.Header{Number: big.NewInt(tt.number), Difficulty: big.NewInt(2), Extra: make([]byte, 32+65)}
+		resp, err := r.ApproveSignData(&core.SignDataRequest{
+			ContentType: accounts.MimetypePoi,
+			Address:     *addr,
+			Meta:        core.Metadata{Remote: "remoteip", Local: "localip", Scheme: "inproc"},
+			Header:      header,
+		})
+		if err != nil {
+			t.Fatalf("test %d: unexpected error %v", i, err)
+		}
+		if resp.Approved != tt.approved {
+			t.Errorf("test %d: approval mismatch: have %v, want %v", i, resp.Approved, tt.approved)
+		}
+	}
+}